@@ -21,6 +21,7 @@ func InitializeMarketplaceController() (*managers.ControllerMain, error) {
 	marketplaceController := controller.ProvideMarketplaceController(defaultCommandRunnerProvider)
 	meterbaseController := controller.ProvideMeterbaseController(defaultCommandRunnerProvider)
 	meterDefinitionController := controller.ProvideMeterDefinitionController(defaultCommandRunnerProvider)
+	meterDefinitionWebhook := controller.ProvideMeterDefinitionWebhook()
 	razeeDeployController := controller.ProvideRazeeDeployController()
 	olmSubscriptionController := controller.ProvideOlmSubscriptionController()
 	operatorConfig, err := config.ProvideConfig()
@@ -28,10 +29,12 @@ func InitializeMarketplaceController() (*managers.ControllerMain, error) {
 		return nil, err
 	}
 	meterReportController := controller.ProvideMeterReportController(defaultCommandRunnerProvider, operatorConfig)
+	meterReportSummaryController := controller.ProvideMeterReportSummaryController(defaultCommandRunnerProvider)
+	meterDefinitionBindingController := controller.ProvideMeterDefinitionBindingController(defaultCommandRunnerProvider)
 	olmClusterServiceVersionController := controller.ProvideOlmClusterServiceVersionController()
 	remoteResourceS3Controller := controller.ProvideRemoteResourceS3Controller()
 	nodeController := controller.ProvideNodeController()
-	controllerList := controller.ProvideControllerList(marketplaceController, meterbaseController, meterDefinitionController, razeeDeployController, olmSubscriptionController, meterReportController, olmClusterServiceVersionController, remoteResourceS3Controller, nodeController)
+	controllerList := controller.ProvideControllerList(marketplaceController, meterbaseController, meterDefinitionController, meterDefinitionWebhook, razeeDeployController, olmSubscriptionController, meterReportController, meterReportSummaryController, meterDefinitionBindingController, olmClusterServiceVersionController, remoteResourceS3Controller, nodeController)
 	restConfig, err := config2.GetConfig()
 	if err != nil {
 		return nil, err
@@ -45,7 +45,7 @@ var (
 func run(cmd *cobra.Command, args []string) {
 	log.Info("serving metrics")
 
-	server, err := metric_server.NewServer(opts)
+	server, cleanup, err := metric_server.NewServer(opts)
 
 	if err != nil {
 		log.Error(err, "failed to get server")
@@ -54,10 +54,11 @@ func run(cmd *cobra.Command, args []string) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	err = server.Serve(ctx.Done())
+	cancel()
+	cleanup()
 
 	if err != nil {
 		log.Error(err, "error running server")
-		cancel()
 		os.Exit(1)
 	}
 
@@ -16,12 +16,19 @@ package metrics
 
 import (
 	"context"
+	"sort"
 	"strings"
 
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"emperror.dev/errors"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 	"k8s.io/kube-state-metrics/pkg/options"
@@ -80,9 +87,73 @@ func (b *Builder) WithMeterDefinitionStore(store *meter_definition.MeterDefiniti
 	b.meterDefStore = store
 }
 
+// DefaultResources are the resources metric stores are built for when no
+// resources are enabled.
+var DefaultResources = []string{
+	"pods",
+	"services",
+	"endpoints",
+	"servicemonitors",
+	"persistentvolumeclaims",
+	"deployments",
+	"statefulsets",
+	"daemonsets",
+	"jobs",
+	"cronjobs",
+	"customresources",
+}
+
+// resourceDependencies lists the resources a resource's metrics are joined
+// with by the meterdefinition queries. Service and service monitor metrics
+// are joined on the endpoints metrics, so they are empty without them.
+var resourceDependencies = map[string][]string{
+	"services":        {"endpoints"},
+	"servicemonitors": {"endpoints"},
+}
+
+// WithEnabledResources sets the resources metric stores are built for.
+// Resources the enabled resources depend on are enabled with them.
+func (b *Builder) WithEnabledResources(resources []string) error {
+	set := map[string]struct{}{}
+	for _, resource := range resources {
+		if _, ok := availableStores[resource]; !ok {
+			return errors.NewWithDetails("resource does not exist",
+				"resource", resource,
+				"availableResources", strings.Join(AvailableResources(), ","))
+		}
+
+		set[resource] = struct{}{}
+		for _, dependency := range resourceDependencies[resource] {
+			set[dependency] = struct{}{}
+		}
+	}
+
+	enabled := make([]string, 0, len(set))
+	for resource := range set {
+		enabled = append(enabled, resource)
+	}
+	sort.Strings(enabled)
+	b.enabledResources = enabled
+	return nil
+}
+
+// AvailableResources returns the resources metric stores can be built for.
+func AvailableResources() []string {
+	resources := make([]string, 0, len(availableStores))
+	for resource := range availableStores {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
 func (b *Builder) Build() []*MetricsStore {
 	stores := []*MetricsStore{}
-	activeStoreNames := []string{"pods", "services", "persistentvolumeclaims"}
+	activeStoreNames := b.enabledResources
+
+	if len(activeStoreNames) == 0 {
+		activeStoreNames = DefaultResources
+	}
 
 	klog.Info("Active resources", "resources", strings.Join(activeStoreNames, ","))
 
@@ -97,7 +168,15 @@ func (b *Builder) Build() []*MetricsStore {
 var availableStores = map[string]func(f *Builder) *MetricsStore{
 	"pods":                   func(b *Builder) *MetricsStore { return b.buildPodStore() },
 	"services":               func(b *Builder) *MetricsStore { return b.buildServiceStore() },
+	"endpoints":              func(b *Builder) *MetricsStore { return b.buildEndpointsStore() },
+	"servicemonitors":        func(b *Builder) *MetricsStore { return b.buildServiceMonitorStore() },
 	"persistentvolumeclaims": func(b *Builder) *MetricsStore { return b.buildPVCStore() },
+	"deployments":            func(b *Builder) *MetricsStore { return b.buildDeploymentStore() },
+	"statefulsets":           func(b *Builder) *MetricsStore { return b.buildStatefulSetStore() },
+	"daemonsets":             func(b *Builder) *MetricsStore { return b.buildDaemonSetStore() },
+	"jobs":                   func(b *Builder) *MetricsStore { return b.buildJobStore() },
+	"cronjobs":               func(b *Builder) *MetricsStore { return b.buildCronJobStore() },
+	"customresources":        func(b *Builder) *MetricsStore { return b.buildCustomResourceStore() },
 }
 
 func (b *Builder) buildServiceStore() *MetricsStore {
@@ -108,6 +187,22 @@ func (b *Builder) buildServiceStore() *MetricsStore {
 	)
 }
 
+func (b *Builder) buildEndpointsStore() *MetricsStore {
+	return b.buildStore(
+		endpointsMetricsFamilies,
+		&v1.Endpoints{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildServiceMonitorStore() *MetricsStore {
+	return b.buildStore(
+		serviceMonitorMetricsFamilies,
+		&monitoringv1.ServiceMonitor{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
 func (b *Builder) buildPodStore() *MetricsStore {
 	return b.buildStore(
 		podMetricsFamilies,
@@ -124,6 +219,54 @@ func (b *Builder) buildPVCStore() *MetricsStore {
 	)
 }
 
+func (b *Builder) buildDeploymentStore() *MetricsStore {
+	return b.buildStore(
+		deploymentMetricsFamilies,
+		&appsv1.Deployment{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildStatefulSetStore() *MetricsStore {
+	return b.buildStore(
+		statefulSetMetricsFamilies,
+		&appsv1.StatefulSet{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildDaemonSetStore() *MetricsStore {
+	return b.buildStore(
+		daemonSetMetricsFamilies,
+		&appsv1.DaemonSet{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildJobStore() *MetricsStore {
+	return b.buildStore(
+		jobMetricsFamilies,
+		&batchv1.Job{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildCronJobStore() *MetricsStore {
+	return b.buildStore(
+		cronJobMetricsFamilies,
+		&batchv1beta1.CronJob{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
+func (b *Builder) buildCustomResourceStore() *MetricsStore {
+	return b.buildStore(
+		customResourceMetricsFamilies,
+		&unstructured.Unstructured{},
+		&MeterDefFetcher{b.cc, b.meterDefStore},
+	)
+}
+
 func (b *Builder) buildStore(
 	metricFamilies []FamilyGenerator,
 	expectedType interface{},
@@ -132,17 +275,20 @@ func (b *Builder) buildStore(
 	composedMetricGenFuncs := ComposeMetricGenFuncs(metricFamilies)
 	familyHeaders := ExtractMetricFamilyHeaders(metricFamilies)
 
-	return NewMetricsStore(
+	store := NewMetricsStore(
 		familyHeaders,
 		composedMetricGenFuncs,
 		b.meterDefStore,
 		meterDefFetcher,
 		expectedType,
 	)
+	store.sharding = meter_definition.Sharding{Shard: b.shard, TotalShards: b.totalShards}
+
+	return store
 }
 
-func ComposeMetricGenFuncs(familyGens []FamilyGenerator) func(interface{}, []*marketplacev1alpha1.MeterDefinition) []FamilyByteSlicer {
-	return func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) []FamilyByteSlicer {
+func ComposeMetricGenFuncs(familyGens []FamilyGenerator) func(interface{}, []*marketplacev1beta1.MeterDefinition) []FamilyByteSlicer {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) []FamilyByteSlicer {
 		families := make([]FamilyByteSlicer, len(familyGens))
 
 		for i, gen := range familyGens {
@@ -0,0 +1,57 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnabledResources(t *testing.T) {
+	b := NewBuilder()
+
+	require.NoError(t, b.WithEnabledResources([]string{"pods", "persistentvolumeclaims"}))
+	assert.Equal(t, []string{"persistentvolumeclaims", "pods"}, b.enabledResources)
+}
+
+func TestWithEnabledResourcesEnablesDependencies(t *testing.T) {
+	b := NewBuilder()
+
+	require.NoError(t, b.WithEnabledResources([]string{"services"}))
+	assert.Equal(t, []string{"endpoints", "services"}, b.enabledResources)
+
+	require.NoError(t, b.WithEnabledResources([]string{"servicemonitors", "endpoints", "pods"}))
+	assert.Equal(t, []string{"endpoints", "pods", "servicemonitors"}, b.enabledResources)
+}
+
+func TestWithEnabledResourcesRejectsUnknownResources(t *testing.T) {
+	b := NewBuilder()
+	require.NoError(t, b.WithEnabledResources([]string{"pods"}))
+
+	err := b.WithEnabledResources([]string{"pods", "nodes"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resource does not exist")
+	assert.Equal(t, []string{"pods"}, b.enabledResources, "enabled resources are unchanged")
+}
+
+func TestAvailableResources(t *testing.T) {
+	resources := AvailableResources()
+
+	assert.ElementsMatch(t, DefaultResources, resources)
+	assert.True(t, sort.StringsAreSorted(resources))
+}
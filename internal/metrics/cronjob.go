@@ -0,0 +1,70 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descCronJobLabelsDefaultLabels = []string{"namespace", "cronjob"}
+)
+
+var cronJobMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_cronjob_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for cronjob",
+		},
+		GenerateMeterFunc: wrapCronJobFunc(func(cronJob *batchv1beta1.CronJob, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			cronJobUID := string(cronJob.UID)
+			suspended := strconv.FormatBool(cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend)
+
+			metrics = append(metrics, &kbsm.Metric{
+				LabelKeys:   []string{"cronjob_uid", "suspended"},
+				LabelValues: []string{cronJobUID, suspended},
+				Value:       1,
+			})
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+}
+
+func wrapCronJobFunc(f func(*batchv1beta1.CronJob, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		cronJob := obj.(*batchv1beta1.CronJob)
+
+		metricFamily := f(cronJob, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descCronJobLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{cronJob.Namespace, cronJob.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCronJobInfoMetrics(t *testing.T) {
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps", UID: "apps-nightly"},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_cronjob_info{namespace="apps",cronjob="nightly",cronjob_uid="apps-nightly",suspended="false",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, cronJobMetricsFamilies, "meterdef_cronjob_info", cronJob))
+
+	cronJob.Spec.Suspend = ptr.Bool(true)
+
+	assert.Equal(t, []string{
+		`meterdef_cronjob_info{namespace="apps",cronjob="nightly",cronjob_uid="apps-nightly",suspended="true",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, cronJobMetricsFamilies, "meterdef_cronjob_info", cronJob))
+}
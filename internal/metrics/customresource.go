@@ -0,0 +1,168 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package metrics
+
+import (
+	"strconv"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descCustomResourceLabelsDefaultLabels = []string{"namespace", "customresource"}
+)
+
+var customResourceMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_customresource_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for custom resource",
+		},
+		GenerateMeterFunc: wrapCustomResourceFunc(func(cr *unstructured.Unstructured, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			crUID := string(cr.GetUID())
+			gvk := cr.GroupVersionKind()
+
+			metrics = append(metrics, &kbsm.Metric{
+				LabelKeys:   []string{"customresource_uid", "customresource_group", "customresource_kind"},
+				LabelValues: []string{crUID, gvk.Group, gvk.Kind},
+				Value:       1,
+			})
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_customresource_value",
+			Type: kbsm.Gauge,
+			Help: "Values of the fields of a custom resource",
+		},
+		GenerateMeterFunc: func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			cr := obj.(*unstructured.Unstructured)
+			metrics := []*kbsm.Metric{}
+
+			crUID := string(cr.GetUID())
+			gvk := cr.GroupVersionKind()
+
+			// the value fields are defined per meter definition so the
+			// metrics are mapped to their meter definition here
+			for _, mdef := range meterDefinitions {
+				mdefLabelKeys, mdefLabelValues := GetMeterDefLabelsKeys(mdef)
+
+				for _, field := range getCustomResourceValueFields(mdef, gvk) {
+					value, ok := getCustomResourceValue(cr, field)
+
+					if !ok {
+						continue
+					}
+
+					labelKeys := append([]string{}, descCustomResourceLabelsDefaultLabels...)
+					labelKeys = append(labelKeys, "customresource_uid", "customresource_group", "customresource_kind", "field")
+					labelValues := []string{cr.GetNamespace(), cr.GetName(), crUID, gvk.Group, gvk.Kind, field.Name}
+
+					metrics = append(metrics, &kbsm.Metric{
+						LabelKeys:   append(labelKeys, mdefLabelKeys...),
+						LabelValues: append(labelValues, mdefLabelValues...),
+						Value:       value,
+					})
+				}
+			}
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		},
+	},
+}
+
+// getCustomResourceValueFields returns the value fields of the CustomResource
+// workloads of the meter definition for the kind.
+func getCustomResourceValueFields(
+	mdef *marketplacev1beta1.MeterDefinition,
+	gvk schema.GroupVersionKind,
+) []marketplacev1beta1.CustomResourceValueField {
+	fields := []marketplacev1beta1.CustomResourceValueField{}
+
+	for _, workload := range mdef.Spec.Workloads {
+		if workload.WorkloadType != marketplacev1beta1.WorkloadTypeCustomResource || workload.CustomResource == nil {
+			continue
+		}
+
+		if schema.FromAPIVersionAndKind(workload.CustomResource.APIVersion, workload.CustomResource.Kind) != gvk {
+			continue
+		}
+
+		fields = append(fields, workload.CustomResource.ValueFields...)
+	}
+
+	return fields
+}
+
+// getCustomResourceValue returns the numeric value at the JSONPath of the
+// field, or false if the field is missing or not a number.
+func getCustomResourceValue(
+	cr *unstructured.Unstructured,
+	field marketplacev1beta1.CustomResourceValueField,
+) (float64, bool) {
+	j := jsonpath.New(field.Name)
+
+	if err := j.Parse(field.JSONPath); err != nil {
+		log.Error(err, "failed to parse jsonPath", "field", field.Name, "jsonPath", field.JSONPath)
+		return 0, false
+	}
+
+	results, err := j.FindResults(cr.Object)
+
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return 0, false
+	}
+
+	switch v := results[0][0].Interface().(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		value, err := strconv.ParseFloat(v, 64)
+		return value, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func wrapCustomResourceFunc(f func(*unstructured.Unstructured, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		cr := obj.(*unstructured.Unstructured)
+
+		metricFamily := f(cr, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descCustomResourceLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{cr.GetNamespace(), cr.GetName()}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestCustomResource() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "partner.metering.com/v1alpha1",
+		"kind":       "App",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "apps",
+			"uid":       "apps-app",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"cpu":      1.5,
+			"size":     "10",
+			"tier":     "gold",
+			"items":    []interface{}{int64(4), int64(5)},
+		},
+	}}
+}
+
+func TestGetCustomResourceValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonPath string
+		value    float64
+		ok       bool
+	}{
+		{name: "int", jsonPath: "{.spec.replicas}", value: 3, ok: true},
+		{name: "float", jsonPath: "{.spec.cpu}", value: 1.5, ok: true},
+		{name: "numeric string", jsonPath: "{.spec.size}", value: 10, ok: true},
+		{name: "list item", jsonPath: "{.spec.items[1]}", value: 5, ok: true},
+		{name: "non-numeric string", jsonPath: "{.spec.tier}"},
+		{name: "missing", jsonPath: "{.spec.storage}"},
+		{name: "list", jsonPath: "{.spec.items}"},
+		{name: "object", jsonPath: "{.spec}"},
+		{name: "invalid", jsonPath: "{.spec.replicas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := getCustomResourceValue(newTestCustomResource(), marketplacev1beta1.CustomResourceValueField{
+				Name:     tt.name,
+				JSONPath: tt.jsonPath,
+			})
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestCustomResourceValueMetrics(t *testing.T) {
+	mdef := newTestMeterDefinition()
+	mdef.Spec.Workloads = []marketplacev1beta1.Workload{
+		{
+			Name:         "apps",
+			WorkloadType: marketplacev1beta1.WorkloadTypeCustomResource,
+			CustomResource: &marketplacev1beta1.CustomResourceWorkload{
+				GroupVersionKind: common.GroupVersionKind{APIVersion: "partner.metering.com/v1alpha1", Kind: "App"},
+				ValueFields: []marketplacev1beta1.CustomResourceValueField{
+					{Name: "replicas", JSONPath: "{.spec.replicas}"},
+					{Name: "tier", JSONPath: "{.spec.tier}"},
+				},
+			},
+		},
+		{
+			Name:         "databases",
+			WorkloadType: marketplacev1beta1.WorkloadTypeCustomResource,
+			CustomResource: &marketplacev1beta1.CustomResourceWorkload{
+				GroupVersionKind: common.GroupVersionKind{APIVersion: "partner.metering.com/v1alpha1", Kind: "Database"},
+				ValueFields: []marketplacev1beta1.CustomResourceValueField{
+					{Name: "size", JSONPath: "{.spec.size}"},
+				},
+			},
+		},
+	}
+
+	var family *FamilyGenerator
+	for i := range customResourceMetricsFamilies {
+		if customResourceMetricsFamilies[i].Name == "meterdef_customresource_value" {
+			family = &customResourceMetricsFamilies[i]
+		}
+	}
+	require.NotNil(t, family)
+
+	f := family.GenerateMeterFunc(newTestCustomResource(), []*marketplacev1beta1.MeterDefinition{mdef})
+	f.Name = family.Name
+
+	// only the numeric fields of the kind are exposed
+	assert.Equal(t,
+		`meterdef_customresource_value{namespace="apps",customresource="app",customresource_uid="apps-app",customresource_group="partner.metering.com",customresource_kind="App",field="replicas",`+testMeterDefLabels+`} 3`+"\n",
+		string(f.ByteSlice()))
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descDaemonSetLabelsDefaultLabels = []string{"namespace", "daemonset"}
+)
+
+var daemonSetMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_daemonset_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for daemonset",
+		},
+		GenerateMeterFunc: wrapDaemonSetFunc(func(daemonSet *appsv1.DaemonSet, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			daemonSetUID := string(daemonSet.UID)
+			running := strconv.FormatBool(daemonSet.Status.NumberReady > 0)
+
+			metrics = append(metrics, &kbsm.Metric{
+				LabelKeys:   []string{"daemonset_uid", "running"},
+				LabelValues: []string{daemonSetUID, running},
+				Value:       1,
+			})
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+}
+
+func wrapDaemonSetFunc(f func(*appsv1.DaemonSet, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		daemonSet := obj.(*appsv1.DaemonSet)
+
+		metricFamily := f(daemonSet, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descDaemonSetLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{daemonSet.Namespace, daemonSet.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDaemonSetInfoMetrics(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "apps", UID: "apps-agent"},
+		Status:     appsv1.DaemonSetStatus{NumberReady: 3},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_daemonset_info{namespace="apps",daemonset="agent",daemonset_uid="apps-agent",running="true",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, daemonSetMetricsFamilies, "meterdef_daemonset_info", daemonSet))
+
+	daemonSet.Status.NumberReady = 0
+
+	assert.Equal(t, []string{
+		`meterdef_daemonset_info{namespace="apps",daemonset="agent",daemonset_uid="apps-agent",running="false",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, daemonSetMetricsFamilies, "meterdef_daemonset_info", daemonSet))
+}
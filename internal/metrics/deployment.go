@@ -0,0 +1,70 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descDeploymentLabelsDefaultLabels = []string{"namespace", "deployment"}
+)
+
+var deploymentMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_deployment_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for deployment",
+		},
+		GenerateMeterFunc: wrapDeploymentFunc(func(deployment *appsv1.Deployment, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			deploymentUID := string(deployment.UID)
+			running := strconv.FormatBool(deployment.Status.AvailableReplicas > 0)
+
+			metrics = append(metrics, &kbsm.Metric{
+				LabelKeys:   []string{"deployment_uid", "running"},
+				LabelValues: []string{deploymentUID, running},
+				Value:       1,
+			})
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+}
+
+func wrapDeploymentFunc(f func(*appsv1.Deployment, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		deployment := obj.(*appsv1.Deployment)
+
+		metricFamily := f(deployment, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descDeploymentLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{deployment.Namespace, deployment.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descEndpointsLabelsDefaultLabels = []string{"namespace", "service"}
+)
+
+var endpointsMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_endpoints_ready_addresses",
+			Type: kbsm.Gauge,
+			Help: "Metering of the ready addresses of the endpoints of the service",
+		},
+		GenerateMeterFunc: wrapEndpointsFunc(func(e *v1.Endpoints, mdefs []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			ready := 0
+			for _, subset := range e.Subsets {
+				ready = ready + len(subset.Addresses)
+			}
+
+			return &kbsm.Family{
+				Metrics: []*kbsm.Metric{
+					{Value: float64(ready)},
+				},
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_endpoints_not_ready_addresses",
+			Type: kbsm.Gauge,
+			Help: "Metering of the not ready addresses of the endpoints of the service",
+		},
+		GenerateMeterFunc: wrapEndpointsFunc(func(e *v1.Endpoints, mdefs []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			notReady := 0
+			for _, subset := range e.Subsets {
+				notReady = notReady + len(subset.NotReadyAddresses)
+			}
+
+			return &kbsm.Family{
+				Metrics: []*kbsm.Metric{
+					{Value: float64(notReady)},
+				},
+			}
+		}),
+	},
+}
+
+// wrapEndpointsFunc is a helper function for generating endpoints-based
+// metrics, endpoints are labeled by the service they belong to
+func wrapEndpointsFunc(f func(*v1.Endpoints, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		endpoints := obj.(*v1.Endpoints)
+
+		metricFamily := f(endpoints, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descEndpointsLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{endpoints.Namespace, endpoints.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
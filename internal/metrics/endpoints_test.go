@@ -0,0 +1,60 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEndpointsAddressMetrics(t *testing.T) {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "apps-app"},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.1.1"}},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_endpoints_ready_addresses{namespace="apps",service="app",` + testMeterDefLabels + `} 3`,
+	}, generateFamily(t, endpointsMetricsFamilies, "meterdef_endpoints_ready_addresses", endpoints))
+
+	assert.Equal(t, []string{
+		`meterdef_endpoints_not_ready_addresses{namespace="apps",service="app",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, endpointsMetricsFamilies, "meterdef_endpoints_not_ready_addresses", endpoints))
+}
+
+func TestEmptyEndpointsAddressMetrics(t *testing.T) {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "apps-app"},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_endpoints_ready_addresses{namespace="apps",service="app",` + testMeterDefLabels + `} 0`,
+	}, generateFamily(t, endpointsMetricsFamilies, "meterdef_endpoints_ready_addresses", endpoints))
+
+	assert.Equal(t, []string{
+		`meterdef_endpoints_not_ready_addresses{namespace="apps",service="app",` + testMeterDefLabels + `} 0`,
+	}, generateFamily(t, endpointsMetricsFamilies, "meterdef_endpoints_not_ready_addresses", endpoints))
+}
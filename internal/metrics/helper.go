@@ -18,10 +18,11 @@ import (
 	"context"
 	"strings"
 
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kbsm "k8s.io/kube-state-metrics/pkg/metric"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -30,7 +31,7 @@ import (
 var log = logf.Log.WithName("meteric")
 
 type FamilyGenerator struct {
-	GenerateMeterFunc func(interface{}, []*marketplacev1alpha1.MeterDefinition) *kbsm.Family
+	GenerateMeterFunc func(interface{}, []*marketplacev1beta1.MeterDefinition) *kbsm.Family
 	kbsm.FamilyGenerator
 }
 
@@ -49,12 +50,12 @@ func (g *FamilyGenerator) generateHeader() string {
 	return header.String()
 }
 
-func GetMeterDefLabelsKeys(mdef *marketplacev1alpha1.MeterDefinition) ([]string, []string) {
+func GetMeterDefLabelsKeys(mdef *marketplacev1beta1.MeterDefinition) ([]string, []string) {
 	return []string{"meter_def_name", "meter_def_namespace", "meter_def_domain", "meter_def_kind"},
 		[]string{mdef.Name, mdef.Namespace, mdef.Spec.Group, mdef.Spec.Kind}
 }
 
-func GetAllMeterLabelsKeys(mdefs []*marketplacev1alpha1.MeterDefinition) ([]string, []string) {
+func GetAllMeterLabelsKeys(mdefs []*marketplacev1beta1.MeterDefinition) ([]string, []string) {
 	allMdefLabelKeys, allMdefLabelValues := []string{}, []string{}
 	for _, meterDef := range mdefs {
 		mdefLabelKeys, mdefLabelValues := GetMeterDefLabelsKeys(meterDef)
@@ -65,17 +66,22 @@ func GetAllMeterLabelsKeys(mdefs []*marketplacev1alpha1.MeterDefinition) ([]stri
 	return allMdefLabelKeys, allMdefLabelValues
 }
 
-func MapMeterDefinitions(metrics []*kbsm.Metric, mdefs []*marketplacev1alpha1.MeterDefinition) []*kbsm.Metric {
+func MapMeterDefinitions(metrics []*kbsm.Metric, mdefs []*marketplacev1beta1.MeterDefinition) []*kbsm.Metric {
 	newMeters := make([]*kbsm.Metric, 0, len(mdefs))
 
 	for _, m := range metrics {
+		// cap the labels of the metric so the labels of each meter
+		// definition are appended to a copy
+		labelKeys := m.LabelKeys[:len(m.LabelKeys):len(m.LabelKeys)]
+		labelValues := m.LabelValues[:len(m.LabelValues):len(m.LabelValues)]
+
 		for _, mdef := range mdefs {
 			mdefLabelKeys, mdefLabelValues := GetMeterDefLabelsKeys(mdef)
 
 			newMeters = append(newMeters, &kbsm.Metric{
 				Value:       m.Value,
-				LabelKeys:   append(m.LabelKeys, mdefLabelKeys...),
-				LabelValues: append(m.LabelValues, mdefLabelValues...),
+				LabelKeys:   append(labelKeys, mdefLabelKeys...),
+				LabelValues: append(labelValues, mdefLabelValues...),
 			})
 		}
 	}
@@ -88,8 +94,8 @@ type MeterDefFetcher struct {
 	meterDefinitionStore *meter_definition.MeterDefinitionStore
 }
 
-func (p *MeterDefFetcher) GetMeterDefinitions(obj interface{}) ([]*marketplacev1alpha1.MeterDefinition, error) {
-	results := []*marketplacev1alpha1.MeterDefinition{}
+func (p *MeterDefFetcher) GetMeterDefinitions(obj interface{}) ([]*marketplacev1beta1.MeterDefinition, error) {
+	results := []*marketplacev1beta1.MeterDefinition{}
 	metaobj, err := meta.Accessor(obj)
 
 	if err != nil {
@@ -101,12 +107,12 @@ func (p *MeterDefFetcher) GetMeterDefinitions(obj interface{}) ([]*marketplacev1
 
 func (p *MeterDefFetcher) getMeterDefs(
 	uid types.UID,
-) ([]*marketplacev1alpha1.MeterDefinition, error) {
-	results := []*marketplacev1alpha1.MeterDefinition{}
+) ([]*marketplacev1beta1.MeterDefinition, error) {
+	results := []*marketplacev1beta1.MeterDefinition{}
 	refs := p.meterDefinitionStore.GetMeterDefinitionRefs(uid)
 
 	for _, ref := range refs {
-		meterDefinition := &marketplacev1alpha1.MeterDefinition{}
+		meterDefinition := &marketplacev1beta1.MeterDefinition{}
 		err := p.getMeterDef(ref.MeterDef, meterDefinition)
 
 		if err != nil {
@@ -122,11 +128,29 @@ func (p *MeterDefFetcher) getMeterDefs(
 
 func (p *MeterDefFetcher) getMeterDef(
 	name types.NamespacedName,
-	mdef *marketplacev1alpha1.MeterDefinition,
+	mdef *marketplacev1beta1.MeterDefinition,
+) error {
+	// meter definitions without a namespace are cluster meter definitions
+	if name.Namespace == "" {
+		clusterMeterdef := &marketplacev1beta1.ClusterMeterDefinition{}
+		if err := p.get(name, clusterMeterdef); err != nil {
+			return err
+		}
+
+		clusterMeterdef.ToMeterDefinition().DeepCopyInto(mdef)
+		return nil
+	}
+
+	return p.get(name, mdef)
+}
+
+func (p *MeterDefFetcher) get(
+	name types.NamespacedName,
+	obj runtime.Object,
 ) error {
 	result, _ := p.cc.Do(
 		context.TODO(),
-		reconcileutils.GetAction(name, mdef),
+		reconcileutils.GetAction(name, obj),
 	)
 
 	if !result.Is(reconcileutils.Continue) {
@@ -0,0 +1,87 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descJobLabelsDefaultLabels = []string{"namespace", "job_name"}
+)
+
+var jobMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_job_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for job",
+		},
+		GenerateMeterFunc: wrapJobFunc(func(job *batchv1.Job, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			jobUID := string(job.UID)
+
+			metrics = append(metrics, &kbsm.Metric{
+				LabelKeys:   []string{"job_uid", "status"},
+				LabelValues: []string{jobUID, getJobStatus(job)},
+				Value:       1,
+			})
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+}
+
+// getJobStatus returns Complete or Failed once the job has finished and
+// Active before.
+func getJobStatus(job *batchv1.Job) string {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		}
+	}
+
+	return "Active"
+}
+
+func wrapJobFunc(f func(*batchv1.Job, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		job := obj.(*batchv1.Job)
+
+		metricFamily := f(job, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descJobLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{job.Namespace, job.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJobInfoMetrics(t *testing.T) {
+	newJob := func(conditions ...batchv1.JobCondition) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "apps", UID: "apps-backup"},
+			Status:     batchv1.JobStatus{Conditions: conditions},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		job    *batchv1.Job
+		status string
+	}{
+		{
+			name:   "active",
+			job:    newJob(),
+			status: "Active",
+		},
+		{
+			name:   "complete",
+			job:    newJob(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			status: "Complete",
+		},
+		{
+			name:   "failed",
+			job:    newJob(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}),
+			status: "Failed",
+		},
+		{
+			name:   "condition not true",
+			job:    newJob(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionFalse}),
+			status: "Active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, []string{
+				`meterdef_job_info{namespace="apps",job_name="backup",job_uid="apps-backup",status="` + tt.status + `",` + testMeterDefLabels + `} 1`,
+			}, generateFamily(t, jobMetricsFamilies, "meterdef_job_info", tt.job))
+		})
+	}
+}
@@ -15,7 +15,7 @@
 package metrics
 
 import (
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	kbsm "k8s.io/kube-state-metrics/pkg/metric"
@@ -32,7 +32,7 @@ var podMetricsFamilies = []FamilyGenerator{
 			Type: kbsm.Gauge,
 			Help: "Metering info for pod",
 		},
-		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 			metrics := []*kbsm.Metric{}
 
 			podUID := string(pod.UID)
@@ -49,11 +49,105 @@ var podMetricsFamilies = []FamilyGenerator{
 			}
 		}),
 	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_pod_container_resource_requests",
+			Type: kbsm.Gauge,
+			Help: "Metering of the resources requested by a container of the pod",
+		},
+		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			return &kbsm.Family{
+				Metrics: containerResourceMetrics(pod, func(c corev1.Container) corev1.ResourceList {
+					return c.Resources.Requests
+				}),
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_pod_container_resource_limits",
+			Type: kbsm.Gauge,
+			Help: "Metering of the resource limits of a container of the pod",
+		},
+		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			return &kbsm.Family{
+				Metrics: containerResourceMetrics(pod, func(c corev1.Container) corev1.ResourceList {
+					return c.Resources.Limits
+				}),
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_pod_status_phase",
+			Type: kbsm.Gauge,
+			Help: "Metering of the phase of the pod",
+		},
+		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			for _, phase := range podPhases {
+				metrics = append(metrics, &kbsm.Metric{
+					LabelKeys:   []string{"phase"},
+					LabelValues: []string{string(phase)},
+					Value:       boolFloat64(pod.Status.Phase == phase),
+				})
+			}
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_pod_status_ready",
+			Type: kbsm.Gauge,
+			Help: "Metering of the ready condition of the pod",
+		},
+		GenerateMeterFunc: wrapPodFunc(func(pod *corev1.Pod, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			status := corev1.ConditionUnknown
+
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == corev1.PodReady {
+					status = condition.Status
+				}
+			}
+
+			return &kbsm.Family{
+				Metrics: conditionMetrics(status),
+			}
+		}),
+	},
+}
+
+var podPhases = []corev1.PodPhase{
+	corev1.PodPending,
+	corev1.PodRunning,
+	corev1.PodSucceeded,
+	corev1.PodFailed,
+	corev1.PodUnknown,
+}
+
+// containerResourceMetrics returns the resource metrics of each container of
+// the pod with the container label.
+func containerResourceMetrics(pod *corev1.Pod, resources func(corev1.Container) corev1.ResourceList) []*kbsm.Metric {
+	metrics := []*kbsm.Metric{}
+
+	for _, c := range pod.Spec.Containers {
+		for _, m := range resourceMetrics(resources(c)) {
+			m.LabelKeys = append([]string{"container"}, m.LabelKeys...)
+			m.LabelValues = append([]string{c.Name}, m.LabelValues...)
+			metrics = append(metrics, m)
+		}
+	}
+
+	return metrics
 }
 
 // wrapPodFunc is a helper function for generating pod-based metrics
-func wrapPodFunc(f func(*v1.Pod, []*marketplacev1alpha1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
-	return func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+func wrapPodFunc(f func(*v1.Pod, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 		pod := obj.(*v1.Pod)
 
 		metricFamily := f(pod, meterDefinitions)
@@ -0,0 +1,123 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testMeterDefLabels are the labels of the meter definition of
+// newTestMeterDefinition.
+const testMeterDefLabels = `meter_def_name="meterdef",meter_def_namespace="apps",meter_def_domain="partner.metering.com",meter_def_kind="App"`
+
+// generateFamily returns the metric lines of the family generated for the
+// object with the test meter definition.
+func generateFamily(t *testing.T, families []FamilyGenerator, name string, obj interface{}) []string {
+	for _, family := range families {
+		if family.Name != name {
+			continue
+		}
+
+		f := family.GenerateMeterFunc(obj, []*marketplacev1beta1.MeterDefinition{newTestMeterDefinition()})
+		f.Name = family.Name
+
+		lines := strings.TrimSuffix(string(f.ByteSlice()), "\n")
+		if lines == "" {
+			return []string{}
+		}
+
+		return strings.Split(lines, "\n")
+	}
+
+	require.FailNow(t, "family not found", name)
+	return nil
+}
+
+func TestPodContainerResourceMetrics(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "apps-app"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+							corev1.ResourceCPU:    resource.MustParse("250m"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+							"example.com/gpu":  resource.MustParse("2"),
+						},
+					},
+				},
+				{Name: "sidecar"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_pod_container_resource_requests{namespace="apps",pod="app",container="app",resource="cpu",unit="core",` + testMeterDefLabels + `} 0.25`,
+		`meterdef_pod_container_resource_requests{namespace="apps",pod="app",container="app",resource="memory",unit="byte",` + testMeterDefLabels + `} 6.7108864e+07`,
+	}, generateFamily(t, podMetricsFamilies, "meterdef_pod_container_resource_requests", pod))
+
+	assert.Equal(t, []string{
+		`meterdef_pod_container_resource_limits{namespace="apps",pod="app",container="app",resource="cpu",unit="core",` + testMeterDefLabels + `} 1`,
+		`meterdef_pod_container_resource_limits{namespace="apps",pod="app",container="app",resource="example_com_gpu",unit="integer",` + testMeterDefLabels + `} 2`,
+	}, generateFamily(t, podMetricsFamilies, "meterdef_pod_container_resource_limits", pod))
+}
+
+func TestPodStatusMetrics(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "apps-app"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_pod_status_phase{namespace="apps",pod="app",phase="Pending",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_phase{namespace="apps",pod="app",phase="Running",` + testMeterDefLabels + `} 1`,
+		`meterdef_pod_status_phase{namespace="apps",pod="app",phase="Succeeded",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_phase{namespace="apps",pod="app",phase="Failed",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_phase{namespace="apps",pod="app",phase="Unknown",` + testMeterDefLabels + `} 0`,
+	}, generateFamily(t, podMetricsFamilies, "meterdef_pod_status_phase", pod))
+
+	assert.Equal(t, []string{
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="true",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="false",` + testMeterDefLabels + `} 1`,
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="unknown",` + testMeterDefLabels + `} 0`,
+	}, generateFamily(t, podMetricsFamilies, "meterdef_pod_status_ready", pod))
+
+	// a pod without the ready condition is unknown
+	pod.Status.Conditions = nil
+	assert.Equal(t, []string{
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="true",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="false",` + testMeterDefLabels + `} 0`,
+		`meterdef_pod_status_ready{namespace="apps",pod="app",condition="unknown",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, podMetricsFamilies, "meterdef_pod_status_ready", pod))
+}
@@ -15,7 +15,9 @@
 package metrics
 
 import (
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"strings"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	kbsm "k8s.io/kube-state-metrics/pkg/metric"
@@ -32,14 +34,15 @@ var pvcMetricsFamilies = []FamilyGenerator{
 			Type: kbsm.Gauge,
 			Help: "Metering info for persistentvolumeclaim",
 		},
-		GenerateMeterFunc: wrapPersistentVolumeClaimFunc(func(pvc *corev1.PersistentVolumeClaim, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+		GenerateMeterFunc: wrapPersistentVolumeClaimFunc(func(pvc *corev1.PersistentVolumeClaim, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 			metrics := []*kbsm.Metric{}
 
 			phase := pvc.Status.Phase
+			labelKeys, labelValues := pvcStorageLabels(pvc)
 
 			metrics = append(metrics, &kbsm.Metric{
-				LabelKeys:   []string{"phase"},
-				LabelValues: []string{string(phase)},
+				LabelKeys:   append([]string{"phase"}, labelKeys...),
+				LabelValues: append([]string{string(phase)}, labelValues...),
 				Value:       1,
 			})
 
@@ -48,11 +51,105 @@ var pvcMetricsFamilies = []FamilyGenerator{
 			}
 		}),
 	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_persistentvolumeclaim_capacity_bytes",
+			Type: kbsm.Gauge,
+			Help: "Metering of the storage capacity of the volume bound to the persistentvolumeclaim",
+		},
+		GenerateMeterFunc: wrapPersistentVolumeClaimFunc(func(pvc *corev1.PersistentVolumeClaim, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			return &kbsm.Family{
+				Metrics: pvcStorageMetrics(pvc, pvc.Status.Capacity),
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_persistentvolumeclaim_request_bytes",
+			Type: kbsm.Gauge,
+			Help: "Metering of the storage requested by the persistentvolumeclaim",
+		},
+		GenerateMeterFunc: wrapPersistentVolumeClaimFunc(func(pvc *corev1.PersistentVolumeClaim, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			return &kbsm.Family{
+				Metrics: pvcStorageMetrics(pvc, pvc.Spec.Resources.Requests),
+			}
+		}),
+	},
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_persistentvolumeclaim_volume_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for the persistentvolume bound to the persistentvolumeclaim",
+		},
+		GenerateMeterFunc: wrapPersistentVolumeClaimFunc(func(pvc *corev1.PersistentVolumeClaim, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			metrics := []*kbsm.Metric{}
+
+			// only claims bound to a volume have one
+			if pvc.Spec.VolumeName != "" {
+				metrics = append(metrics, &kbsm.Metric{
+					LabelKeys:   []string{"persistentvolume"},
+					LabelValues: []string{pvc.Spec.VolumeName},
+					Value:       1,
+				})
+			}
+
+			return &kbsm.Family{
+				Metrics: metrics,
+			}
+		}),
+	},
+}
+
+// pvcStorageMetrics returns the storage of the resource list with the
+// storage labels of the claim, if it is set.
+func pvcStorageMetrics(pvc *corev1.PersistentVolumeClaim, resources corev1.ResourceList) []*kbsm.Metric {
+	metrics := []*kbsm.Metric{}
+
+	storage, ok := resources[corev1.ResourceStorage]
+	if !ok {
+		return metrics
+	}
+
+	labelKeys, labelValues := pvcStorageLabels(pvc)
+	metrics = append(metrics, &kbsm.Metric{
+		LabelKeys:   labelKeys,
+		LabelValues: labelValues,
+		Value:       float64(storage.Value()),
+	})
+
+	return metrics
+}
+
+// pvcStorageLabels returns the storage class, access modes and volume mode
+// labels of the claim.
+func pvcStorageLabels(pvc *corev1.PersistentVolumeClaim) ([]string, []string) {
+	accessModes := make([]string, 0, len(pvc.Spec.AccessModes))
+	for _, mode := range pvc.Spec.AccessModes {
+		accessModes = append(accessModes, string(mode))
+	}
+
+	volumeMode := ""
+	if pvc.Spec.VolumeMode != nil {
+		volumeMode = string(*pvc.Spec.VolumeMode)
+	}
+
+	return []string{"storageclass", "access_modes", "volume_mode"},
+		[]string{pvcStorageClass(pvc), strings.Join(accessModes, ","), volumeMode}
+}
+
+// pvcStorageClass returns the storage class of the claim, falling back to
+// the beta annotation claims used before storageClassName.
+func pvcStorageClass(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+
+	return pvc.Annotations[corev1.BetaStorageClassAnnotation]
 }
 
 // wrapPersistentVolumeClaimFunc is a helper function for generating pvc-based metrics
-func wrapPersistentVolumeClaimFunc(f func(*v1.PersistentVolumeClaim, []*marketplacev1alpha1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
-	return func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+func wrapPersistentVolumeClaimFunc(f func(*v1.PersistentVolumeClaim, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 		pvc := obj.(*v1.PersistentVolumeClaim)
 
 		metricFamily := f(pvc, meterDefinitions)
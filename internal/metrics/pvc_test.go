@@ -0,0 +1,89 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPersistentVolumeClaimStorageMetrics(t *testing.T) {
+	storageClass := "premium"
+	filesystem := corev1.PersistentVolumeFilesystem
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "apps", UID: "apps-data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadOnlyMany},
+			VolumeMode:       &filesystem,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+			VolumeName: "pv-data",
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")},
+		},
+	}
+
+	storageLabels := `storageclass="premium",access_modes="ReadWriteOnce,ReadOnlyMany",volume_mode="Filesystem",`
+
+	assert.Equal(t, []string{
+		`meterdef_persistentvolumeclaim_info{namespace="apps",persistentvolumeclaim="data",phase="Bound",` + storageLabels + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_info", pvc))
+
+	assert.Equal(t, []string{
+		`meterdef_persistentvolumeclaim_capacity_bytes{namespace="apps",persistentvolumeclaim="data",` + storageLabels + testMeterDefLabels + `} 2.147483648e+09`,
+	}, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_capacity_bytes", pvc))
+
+	assert.Equal(t, []string{
+		`meterdef_persistentvolumeclaim_request_bytes{namespace="apps",persistentvolumeclaim="data",` + storageLabels + testMeterDefLabels + `} 1.073741824e+09`,
+	}, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_request_bytes", pvc))
+
+	assert.Equal(t, []string{
+		`meterdef_persistentvolumeclaim_volume_info{namespace="apps",persistentvolumeclaim="data",persistentvolume="pv-data",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_volume_info", pvc))
+}
+
+func TestPendingPersistentVolumeClaimMetrics(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "data",
+			Namespace:   "apps",
+			UID:         "apps-data",
+			Annotations: map[string]string{corev1.BetaStorageClassAnnotation: "standard"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("512Mi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+
+	// the storage class falls back to the beta annotation
+	assert.Equal(t, []string{
+		`meterdef_persistentvolumeclaim_request_bytes{namespace="apps",persistentvolumeclaim="data",storageclass="standard",access_modes="",volume_mode="",` + testMeterDefLabels + `} 5.36870912e+08`,
+	}, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_request_bytes", pvc))
+
+	// a claim that isn't bound has no capacity or volume
+	assert.Empty(t, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_capacity_bytes", pvc))
+	assert.Empty(t, generateFamily(t, pvcMetricsFamilies, "meterdef_persistentvolumeclaim_volume_info", pvc))
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kube-state-metrics/pkg/constant"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	invalidLabelCharRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	conditionStatuses  = []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+)
+
+// resourceMetrics returns a metric with the resource and unit labels for
+// each resource of the list, sorted by resource. The units are the ones of
+// kube-state-metrics so queries work on either.
+func resourceMetrics(resources corev1.ResourceList) []*kbsm.Metric {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	metrics := []*kbsm.Metric{}
+
+	for _, name := range names {
+		resourceName := corev1.ResourceName(name)
+		quantity := resources[resourceName]
+
+		var unit constant.ResourceUnit
+		value := float64(quantity.Value())
+
+		switch {
+		case resourceName == corev1.ResourceCPU:
+			unit = constant.UnitCore
+			value = float64(quantity.MilliValue()) / 1000
+		case resourceName == corev1.ResourceMemory,
+			resourceName == corev1.ResourceStorage,
+			resourceName == corev1.ResourceEphemeralStorage,
+			strings.HasPrefix(name, corev1.ResourceHugePagesPrefix),
+			strings.HasPrefix(name, corev1.ResourceAttachableVolumesPrefix):
+			unit = constant.UnitByte
+		case isExtendedResourceName(resourceName):
+			unit = constant.UnitInteger
+		default:
+			continue
+		}
+
+		metrics = append(metrics, &kbsm.Metric{
+			LabelKeys:   []string{"resource", "unit"},
+			LabelValues: []string{sanitizeLabelName(name), string(unit)},
+			Value:       value,
+		})
+	}
+
+	return metrics
+}
+
+// conditionMetrics returns a metric for each condition status with the
+// condition label, set to 1 for the status of the condition.
+func conditionMetrics(status corev1.ConditionStatus) []*kbsm.Metric {
+	metrics := make([]*kbsm.Metric, 0, len(conditionStatuses))
+
+	for _, s := range conditionStatuses {
+		metrics = append(metrics, &kbsm.Metric{
+			LabelKeys:   []string{"condition"},
+			LabelValues: []string{strings.ToLower(string(s))},
+			Value:       boolFloat64(status == s),
+		})
+	}
+
+	return metrics
+}
+
+func isExtendedResourceName(name corev1.ResourceName) bool {
+	if !strings.Contains(string(name), "/") ||
+		strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) ||
+		strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix) {
+		return false
+	}
+
+	nameForQuota := fmt.Sprintf("%s%s", corev1.DefaultResourceRequestsPrefix, string(name))
+	return len(validation.IsQualifiedName(nameForQuota)) == 0
+}
+
+func sanitizeLabelName(s string) string {
+	return invalidLabelCharRE.ReplaceAllString(s, "_")
+}
+
+func boolFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -15,7 +15,7 @@
 package metrics
 
 import (
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	kbsm "k8s.io/kube-state-metrics/pkg/metric"
 )
@@ -31,7 +31,7 @@ var serviceMetricsFamilies = []FamilyGenerator{
 			Type: kbsm.Gauge,
 			Help: "Info about the service for servicemonitor",
 		},
-		GenerateMeterFunc: wrapServiceFunc(func(s *v1.Service, mdefs []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+		GenerateMeterFunc: wrapServiceFunc(func(s *v1.Service, mdefs []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 			// kube-state-metric labels
 			clusterIP := s.Spec.ClusterIP
 			externalName := s.Spec.ExternalName
@@ -50,8 +50,8 @@ var serviceMetricsFamilies = []FamilyGenerator{
 }
 
 // wrapServiceFunc is a helper function for generating service-based metrics
-func wrapServiceFunc(f func(*v1.Service, []*marketplacev1alpha1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
-	return func(obj interface{}, meterDefinitions []*marketplacev1alpha1.MeterDefinition) *kbsm.Family {
+func wrapServiceFunc(f func(*v1.Service, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
 		svc := obj.(*v1.Service)
 
 		metricFamily := f(svc, meterDefinitions)
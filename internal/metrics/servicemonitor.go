@@ -0,0 +1,64 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	kbsm "k8s.io/kube-state-metrics/pkg/metric"
+)
+
+var (
+	descServiceMonitorLabelsDefaultLabels = []string{"namespace", "servicemonitor"}
+)
+
+var serviceMonitorMetricsFamilies = []FamilyGenerator{
+	{
+		FamilyGenerator: kbsm.FamilyGenerator{
+			Name: "meterdef_servicemonitor_info",
+			Type: kbsm.Gauge,
+			Help: "Metering info for servicemonitor",
+		},
+		GenerateMeterFunc: wrapServiceMonitorFunc(func(sm *monitoringv1.ServiceMonitor, mdefs []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+			return &kbsm.Family{
+				Metrics: []*kbsm.Metric{
+					{
+						LabelKeys:   []string{"servicemonitor_uid"},
+						LabelValues: []string{string(sm.UID)},
+						Value:       1,
+					},
+				},
+			}
+		}),
+	},
+}
+
+// wrapServiceMonitorFunc is a helper function for generating servicemonitor-based metrics
+func wrapServiceMonitorFunc(f func(*monitoringv1.ServiceMonitor, []*marketplacev1beta1.MeterDefinition) *kbsm.Family) func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+	return func(obj interface{}, meterDefinitions []*marketplacev1beta1.MeterDefinition) *kbsm.Family {
+		sm := obj.(*monitoringv1.ServiceMonitor)
+
+		metricFamily := f(sm, meterDefinitions)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append(descServiceMonitorLabelsDefaultLabels, m.LabelKeys...)
+			m.LabelValues = append([]string{sm.Namespace, sm.Name}, m.LabelValues...)
+		}
+
+		metricFamily.Metrics = MapMeterDefinitions(metricFamily.Metrics, meterDefinitions)
+
+		return metricFamily
+	}
+}
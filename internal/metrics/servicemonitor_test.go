@@ -0,0 +1,33 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceMonitorInfoMetrics(t *testing.T) {
+	sm := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "apps-app-monitor"},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_servicemonitor_info{namespace="apps",servicemonitor="app",servicemonitor_uid="apps-app-monitor",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, serviceMonitorMetricsFamilies, "meterdef_servicemonitor_info", sm))
+}
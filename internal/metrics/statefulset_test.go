@@ -0,0 +1,40 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatefulSetInfoMetrics(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps", UID: "apps-db"},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+
+	assert.Equal(t, []string{
+		`meterdef_statefulset_info{namespace="apps",statefulset="db",statefulset_uid="apps-db",running="true",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, statefulSetMetricsFamilies, "meterdef_statefulset_info", statefulSet))
+
+	statefulSet.Status.ReadyReplicas = 0
+
+	assert.Equal(t, []string{
+		`meterdef_statefulset_info{namespace="apps",statefulset="db",statefulset_uid="apps-db",running="false",` + testMeterDefLabels + `} 1`,
+	}, generateFamily(t, statefulSetMetricsFamilies, "meterdef_statefulset_info", statefulSet))
+}
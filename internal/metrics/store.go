@@ -20,8 +20,10 @@ import (
 	"io"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"emperror.dev/errors"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,7 +34,7 @@ type FamilyByteSlicer interface {
 }
 
 type MeterDefinitionFetcher interface {
-	GetMeterDefinitions(interface{}) ([]*marketplacev1alpha1.MeterDefinition, error)
+	GetMeterDefinitions(interface{}) ([]*marketplacev1beta1.MeterDefinition, error)
 }
 
 // MetricsStore implements the k8s.io/client-go/tools/cache.Store
@@ -53,19 +55,26 @@ type MetricsStore struct {
 
 	// generateMetricsFunc generates metrics based on a given Kubernetes object
 	// and returns them grouped by metric family.
-	generateMetricsFunc func(interface{}, []*marketplacev1alpha1.MeterDefinition) []FamilyByteSlicer
+	generateMetricsFunc func(interface{}, []*marketplacev1beta1.MeterDefinition) []FamilyByteSlicer
 
 	meterDefStore *meter_definition.MeterDefinitionStore
 
 	meterDefFetcher MeterDefinitionFetcher
 
 	expectedType interface{}
+
+	// sharding selects the objects the store generates metrics for
+	sharding meter_definition.Sharding
+
+	// synced is set once the store has received the objects of the
+	// meter definition store
+	synced int32
 }
 
 // NewMetricsStore returns a new MetricsStore
 func NewMetricsStore(
 	headers []string,
-	generateFunc func(interface{}, []*marketplacev1alpha1.MeterDefinition) []FamilyByteSlicer,
+	generateFunc func(interface{}, []*marketplacev1beta1.MeterDefinition) []FamilyByteSlicer,
 	meterDefStore *meter_definition.MeterDefinitionStore,
 	meterDefFetcher MeterDefinitionFetcher,
 	expectedType interface{},
@@ -84,8 +93,7 @@ func (s *MetricsStore) Start(
 	ctx context.Context,
 ) {
 	ch := make(chan *meter_definition.ObjectResourceMessage)
-	s.meterDefStore.RegisterListener(fmt.Sprintf("metricStore-%T", s.expectedType), ch)
-	expectedTypeVal := reflect.TypeOf(s.expectedType)
+	s.meterDefStore.RegisterListener(s.Name(), ch)
 
 	go func() {
 		defer close(ch)
@@ -93,15 +101,7 @@ func (s *MetricsStore) Start(
 		for {
 			select {
 			case msg := <-ch:
-				if msg == nil || reflect.TypeOf(msg.Object) != expectedTypeVal {
-					break
-				}
-				switch msg.Action {
-				case meter_definition.AddMessageAction:
-					_ = s.Add(msg.Object)
-				case meter_definition.DeleteMessageAction:
-					_ = s.Delete(msg.Object)
-				}
+				s.handleMessage(msg)
 			case <-ctx.Done():
 				return
 			}
@@ -109,6 +109,42 @@ func (s *MetricsStore) Start(
 	}()
 }
 
+// handleMessage applies a message of the meter definition store to the
+// metrics.
+func (s *MetricsStore) handleMessage(msg *meter_definition.ObjectResourceMessage) {
+	if msg != nil && msg.Action == meter_definition.ResyncMessageAction {
+		if err := s.resync(msg.Objects); err != nil {
+			log.Error(err, "failed to resync metrics", "store", s.Name())
+		}
+
+		// objects that failed are left out until they change
+		atomic.StoreInt32(&s.synced, 1)
+		return
+	}
+
+	if msg == nil || reflect.TypeOf(msg.Object) != reflect.TypeOf(s.expectedType) {
+		return
+	}
+
+	switch msg.Action {
+	case meter_definition.AddMessageAction:
+		_ = s.Add(msg.Object)
+	case meter_definition.DeleteMessageAction:
+		_ = s.Delete(msg.Object)
+	}
+}
+
+// Name is the name of the store, by the type of its objects.
+func (s *MetricsStore) Name() string {
+	return fmt.Sprintf("metricStore-%T", s.expectedType)
+}
+
+// HasSynced returns true once the store has generated the metrics of the
+// objects listed by the meter definition store.
+func (s *MetricsStore) HasSynced() bool {
+	return atomic.LoadInt32(&s.synced) == 1
+}
+
 // Implementing k8s.io/client-go/tools/cache.Store interface
 
 // Add inserts adds to the MetricsStore by calling the metrics generator functions and
@@ -119,6 +155,10 @@ func (s *MetricsStore) Add(obj interface{}) error {
 		return err
 	}
 
+	if !s.sharding.Keep(o.GetUID()) {
+		return nil
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -183,20 +223,43 @@ func (s *MetricsStore) GetByKey(key string) (item interface{}, exists bool, err
 }
 
 // Replace will delete the contents of the store, using instead the
-// given list.
+// given list. Every object is added, the errors of the objects that failed
+// are returned together.
 func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 	s.mutex.Lock()
 	s.metrics = map[types.UID][][]byte{}
 	s.mutex.Unlock()
 
+	var errs error
 	for _, o := range list {
-		err := s.Add(o)
-		if err != nil {
-			return err
+		errs = errors.Append(errs, s.Add(o))
+	}
+
+	return errs
+}
+
+// resync replaces the metrics with the metrics of the objects of the expected
+// type so messages missed by the store converge.
+func (s *MetricsStore) resync(objects []meter_definition.ObjectResource) error {
+	expectedTypeVal := reflect.TypeOf(s.expectedType)
+	list := []interface{}{}
+	seen := map[types.UID]bool{}
+
+	for _, resource := range objects {
+		if reflect.TypeOf(resource.Object) != expectedTypeVal {
+			continue
 		}
+
+		o, err := meta.Accessor(resource.Object)
+		if err != nil || seen[o.GetUID()] {
+			continue
+		}
+
+		seen[o.GetUID()] = true
+		list = append(list, resource.Object)
 	}
 
-	return nil
+	return s.Replace(list, "")
 }
 
 // Resync implements the Resync method of the store interface.
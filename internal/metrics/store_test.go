@@ -0,0 +1,95 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"emperror.dev/errors"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testMeterDefFetcher returns the meter definition for every object except
+// the ones it fails for.
+type testMeterDefFetcher struct {
+	meterdef *marketplacev1beta1.MeterDefinition
+	failFor  map[string]bool
+}
+
+func (f *testMeterDefFetcher) GetMeterDefinitions(obj interface{}) ([]*marketplacev1beta1.MeterDefinition, error) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.failFor[o.GetName()] {
+		return nil, errors.New("failed to get meterdefinitions")
+	}
+
+	return []*marketplacev1beta1.MeterDefinition{f.meterdef}, nil
+}
+
+func newTestMeterDefinition() *marketplacev1beta1.MeterDefinition {
+	return &marketplacev1beta1.MeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "meterdef", Namespace: "apps"},
+		Spec: marketplacev1beta1.MeterDefinitionSpec{
+			Group: "partner.metering.com",
+			Kind:  "App",
+		},
+	}
+}
+
+func newTestPod(name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "apps",
+		UID:       "apps-" + types.UID(name),
+	}}
+}
+
+func TestMetricsStoreReplaceWithFailingObject(t *testing.T) {
+	fetcher := &testMeterDefFetcher{meterdef: newTestMeterDefinition(), failFor: map[string]bool{"bad": true}}
+	store := NewMetricsStore(
+		ExtractMetricFamilyHeaders(podMetricsFamilies),
+		ComposeMetricGenFuncs(podMetricsFamilies),
+		nil, fetcher, &v1.Pod{})
+
+	err := store.Replace([]interface{}{newTestPod("bad"), newTestPod("good")}, "")
+	assert.Error(t, err)
+	assert.Len(t, store.metrics, 1)
+	assert.Contains(t, store.metrics, newTestPod("good").UID)
+
+	// the store is synced with the objects that did not fail
+	store.handleMessage(&meter_definition.ObjectResourceMessage{
+		Action: meter_definition.ResyncMessageAction,
+		Objects: []meter_definition.ObjectResource{
+			{Object: newTestPod("bad")},
+			{Object: newTestPod("good")},
+		},
+	})
+	assert.True(t, store.HasSynced())
+
+	w := &bytes.Buffer{}
+	store.WriteAll(w)
+	assert.Contains(t, w.String(), `pod="good"`)
+	assert.NotContains(t, w.String(), `pod="bad"`)
+}
@@ -16,6 +16,7 @@ package common
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -31,6 +32,10 @@ type JobReference struct {
 	// Required
 	Name string `json:"name"`
 
+	// UID of the job
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
 	// Represents time when the job was acknowledged by the job controller.
 	// It is not guaranteed to be set in happens-before order across separate operations.
 	// It is represented in RFC3339 form and is in UTC.
@@ -59,6 +64,11 @@ type JobReference struct {
 	// Defaults to 6
 	// +optional
 	BackoffLimit int32 `json:"backoffLimit,omitempty" protobuf:"varint,7,opt,name=backoffLimit"`
+
+	// Whether the job has a Failed condition, i.e. because the active
+	// deadline was exceeded.
+	// +optional
+	ConditionFailed bool `json:"conditionFailed,omitempty"`
 }
 
 func (j *JobReference) NamespacedName() types.NamespacedName {
@@ -71,6 +81,7 @@ func (j *JobReference) NamespacedName() types.NamespacedName {
 func (j *JobReference) SetFromJob(job *batchv1.Job) {
 	j.Name = job.Name
 	j.Namespace = job.Namespace
+	j.UID = job.UID
 	j.StartTime = job.Status.StartTime
 	j.CompletionTime = job.Status.CompletionTime
 	j.Succeeded = job.Status.Succeeded
@@ -81,6 +92,13 @@ func (j *JobReference) SetFromJob(job *batchv1.Job) {
 		j.BackoffLimit = *job.Spec.BackoffLimit
 	}
 
+	j.ConditionFailed = false
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			j.ConditionFailed = true
+		}
+	}
+
 }
 
 func (j *JobReference) IsSuccessful() bool {
@@ -88,7 +106,7 @@ func (j *JobReference) IsSuccessful() bool {
 }
 
 func (j *JobReference) IsFailed() bool {
-	return j.Failed == j.BackoffLimit+1 && !j.IsSuccessful()
+	return (j.ConditionFailed || j.Failed == j.BackoffLimit+1) && !j.IsSuccessful()
 }
 
 func (j *JobReference) IsActive() bool {
@@ -1,8 +1,11 @@
 package common
 
 import (
+	"github.com/gotidy/ptr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("JobReference", func(){
@@ -41,6 +44,27 @@ var _ = Describe("JobReference", func(){
 		Expect(jr.IsFailed()).To(BeFalse())
 	})
 
+	It("should fail when the job controller marks it failed", func() {
+		jr.SetFromJob(&batchv1.Job{
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.Int32(5),
+			},
+			Status: batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:   batchv1.JobFailed,
+						Status: corev1.ConditionTrue,
+						Reason: "DeadlineExceeded",
+					},
+				},
+			},
+		})
+
+		Expect(jr.IsDone()).To(BeTrue())
+		Expect(jr.IsFailed()).To(BeTrue())
+	})
+
 	It("should show active", func() {
 		jr.Active = 1
 		jr.BackoffLimit = 1
@@ -97,6 +97,143 @@ type MeterBaseSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +optional
 	AdditionalScrapeConfigs *corev1.SecretKeySelector `json:"additionalScrapeConfigs,omitempty"`
+
+	// MetricState configures the metric state that generates the metrics
+	// of the workloads of meter definitions.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	MetricState *MetricStateSpec `json:"metricState,omitempty"`
+
+	// Reporting configures the schedule, retention and timezone of the
+	// MeterReports generated by the MeterBase. Default is daily reports in UTC
+	// kept for 30 days.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Reporting *ReportingSpec `json:"reporting,omitempty"`
+
+	// ReporterJobTemplate customizes the jobs that run the reporter for
+	// each MeterReport.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	ReporterJobTemplate *ReporterJobTemplateSpec `json:"reporterJobTemplate,omitempty"`
+}
+
+// MetricStateSpec contains configuration for the metric state.
+type MetricStateSpec struct {
+	// Shards is the number of replicas of the metric state. The workloads
+	// are split between the replicas by the hash of their UID. Default is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Shards *int32 `json:"shards,omitempty"`
+}
+
+// ReporterJobTemplateSpec contains configuration merged into the reporter
+// jobs. Fields that are not set keep the defaults of the job.
+type ReporterJobTemplateSpec struct {
+	// Resource requirements for the reporter container.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector for the reporter pods.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations for the reporter pods.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity for the reporter pods.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName of the reporter pods.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Env are additional environment variables for the reporter container.
+	// Variables with the same name as a default variable replace it.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ImagePullSecrets for the reporter pods.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration a reporter job may be active
+	// before it is terminated and marked as failed.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// BackoffLimit is the number of retries before a reporter job is marked
+	// as failed. Default is 5.
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// TTLSecondsAfterFinished limits how long a finished reporter job is kept
+	// before it is deleted.
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// ReportingPeriod is the length of time covered by a single MeterReport.
+// +kubebuilder:validation:Enum=Hourly;Daily;Weekly;Monthly
+type ReportingPeriod string
+
+const (
+	ReportingPeriodHourly  ReportingPeriod = "Hourly"
+	ReportingPeriodDaily   ReportingPeriod = "Daily"
+	ReportingPeriodWeekly  ReportingPeriod = "Weekly"
+	ReportingPeriodMonthly ReportingPeriod = "Monthly"
+)
+
+// ReportingSpec contains configuration for the generation of MeterReports.
+type ReportingSpec struct {
+	// Period is the length of time covered by each MeterReport. Weekly reports
+	// start on Monday and monthly reports start on the first day of the calendar
+	// month. Default is Daily.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Period ReportingPeriod `json:"period,omitempty"`
+
+	// RetentionCount is the number of most recent report periods to keep. Reports
+	// for older periods are deleted and not regenerated.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	RetentionCount *int32 `json:"retentionCount,omitempty"`
+
+	// RetentionDuration is how long reports are kept. Reports that started before
+	// this duration are deleted and not regenerated. If both RetentionCount and
+	// RetentionDuration are set, the shorter of the two applies. Default is 720h
+	// when neither is set.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	RetentionDuration *metav1.Duration `json:"retentionDuration,omitempty"`
+
+	// Timezone is the IANA name of the billing timezone, i.e. "America/New_York".
+	// Report periods begin at midnight in this timezone. Default is UTC.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// StartDate overrides the earliest date reports are generated for. Default is
+	// the creation time of the MeterBase.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	StartDate *metav1.Time `json:"startDate,omitempty"`
 }
 
 // MeterBaseStatus defines the observed state of MeterBase.
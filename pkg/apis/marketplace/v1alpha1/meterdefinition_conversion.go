@@ -0,0 +1,310 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// MeterDefinitionDeprecatedFieldsAnnotation holds the v1alpha1 spec fields
+// that have no v1beta1 equivalent so they survive a round trip through v1beta1.
+const MeterDefinitionDeprecatedFieldsAnnotation = "marketplace.redhat.com/v1alpha1-deprecated-fields"
+
+// MeterDefinitionWorkloadFieldsAnnotation holds the v1beta1 workload fields
+// that have no v1alpha1 equivalent so they survive a round trip through v1alpha1.
+const MeterDefinitionWorkloadFieldsAnnotation = "marketplace.redhat.com/v1beta1-workload-fields"
+
+// MeterDefinitionWorkloadStatusAnnotation holds the v1beta1 workload status
+// that has no v1alpha1 equivalent so it survives a round trip through v1alpha1.
+const MeterDefinitionWorkloadStatusAnnotation = "marketplace.redhat.com/v1beta1-workload-status"
+
+// workloadFields are the v1beta1 workload fields missing from v1alpha1.
+type workloadFields struct {
+	CustomResource *v1beta1.CustomResourceWorkload `json:"customResource,omitempty"`
+	FieldFilters   []v1beta1.FieldFilter           `json:"fieldFilters,omitempty"`
+	OwnerDepth     *int32                          `json:"ownerDepth,omitempty"`
+	MatchAnyOwner  bool                            `json:"matchAnyOwner,omitempty"`
+}
+
+func (w workloadFields) isEmpty() bool {
+	return w.CustomResource == nil && w.FieldFilters == nil && w.OwnerDepth == nil && !w.MatchAnyOwner
+}
+
+// meterDefinitionDeprecatedFields are the v1alpha1 spec fields removed in v1beta1.
+type meterDefinitionDeprecatedFields struct {
+	Version            *string  `json:"meterVersion,omitempty"`
+	ServiceMeterLabels []string `json:"serviceMeterLabels,omitempty"`
+	PodMeterLabels     []string `json:"podMeterLabels,omitempty"`
+}
+
+var _ conversion.Convertible = &MeterDefinition{}
+
+// ConvertTo converts this MeterDefinition to the v1beta1 hub version.
+func (src *MeterDefinition) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.MeterDefinition)
+	if !ok {
+		return errors.Errorf("unsupported conversion hub %T", dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	deprecated := meterDefinitionDeprecatedFields{
+		Version:            src.Spec.Version,
+		ServiceMeterLabels: src.Spec.ServiceMeterLabels,
+		PodMeterLabels:     src.Spec.PodMeterLabels,
+	}
+
+	if deprecated.Version != nil || deprecated.ServiceMeterLabels != nil || deprecated.PodMeterLabels != nil {
+		data, err := json.Marshal(deprecated)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal deprecated fields")
+		}
+
+		annotations := dst.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[MeterDefinitionDeprecatedFieldsAnnotation] = string(data)
+		dst.SetAnnotations(annotations)
+	}
+
+	dst.Spec = v1beta1.MeterDefinitionSpec{
+		Group:               src.Spec.Group,
+		Kind:                src.Spec.Kind,
+		InstalledBy:         src.Spec.InstalledBy.DeepCopy(),
+		WorkloadVertexType:  v1beta1.WorkloadVertex(src.Spec.WorkloadVertexType),
+		VertexLabelSelector: src.Spec.VertexLabelSelector.DeepCopy(),
+	}
+
+	if src.Spec.Workloads != nil {
+		dst.Spec.Workloads = make([]v1beta1.Workload, 0, len(src.Spec.Workloads))
+		for _, workload := range src.Spec.Workloads {
+			dst.Spec.Workloads = append(dst.Spec.Workloads, convertWorkloadTo(workload))
+		}
+	}
+
+	if data, ok := dst.GetAnnotations()[MeterDefinitionWorkloadFieldsAnnotation]; ok {
+		fields := map[string]workloadFields{}
+		if err := json.Unmarshal([]byte(data), &fields); err != nil {
+			return errors.Wrap(err, "failed to unmarshal workload fields")
+		}
+
+		for i := range dst.Spec.Workloads {
+			workload := &dst.Spec.Workloads[i]
+			if field, ok := fields[workload.Name]; ok {
+				workload.CustomResource = field.CustomResource
+				workload.FieldFilters = field.FieldFilters
+				workload.OwnerDepth = field.OwnerDepth
+				workload.MatchAnyOwner = field.MatchAnyOwner
+			}
+		}
+
+		annotations := dst.GetAnnotations()
+		delete(annotations, MeterDefinitionWorkloadFieldsAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		dst.SetAnnotations(annotations)
+	}
+
+	var workloadStatus []v1beta1.WorkloadStatus
+	if data, ok := dst.GetAnnotations()[MeterDefinitionWorkloadStatusAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &workloadStatus); err != nil {
+			return errors.Wrap(err, "failed to unmarshal workload status")
+		}
+
+		annotations := dst.GetAnnotations()
+		delete(annotations, MeterDefinitionWorkloadStatusAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		dst.SetAnnotations(annotations)
+	}
+
+	dst.Status = v1beta1.MeterDefinitionStatus{WorkloadStatus: workloadStatus}
+
+	if src.Status.Conditions != nil {
+		dst.Status.Conditions = make(status.Conditions, len(src.Status.Conditions))
+		copy(dst.Status.Conditions, src.Status.Conditions)
+	}
+
+	if src.Status.WorkloadResources != nil {
+		dst.Status.WorkloadResources = make([]v1beta1.WorkloadResource, 0, len(src.Status.WorkloadResources))
+		for _, resource := range src.Status.WorkloadResources {
+			dst.Status.WorkloadResources = append(dst.Status.WorkloadResources, v1beta1.WorkloadResource{
+				ReferencedWorkloadName:  resource.ReferencedWorkloadName,
+				NamespacedNameReference: *resource.NamespacedNameReference.DeepCopy(),
+			})
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the v1beta1 hub version to this version.
+func (dst *MeterDefinition) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.MeterDefinition)
+	if !ok {
+		return errors.Errorf("unsupported conversion hub %T", srcRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec = MeterDefinitionSpec{
+		Group:               src.Spec.Group,
+		Kind:                src.Spec.Kind,
+		InstalledBy:         src.Spec.InstalledBy.DeepCopy(),
+		WorkloadVertexType:  WorkloadVertex(src.Spec.WorkloadVertexType),
+		VertexLabelSelector: src.Spec.VertexLabelSelector.DeepCopy(),
+	}
+
+	if data, ok := dst.GetAnnotations()[MeterDefinitionDeprecatedFieldsAnnotation]; ok {
+		deprecated := meterDefinitionDeprecatedFields{}
+		if err := json.Unmarshal([]byte(data), &deprecated); err != nil {
+			return errors.Wrap(err, "failed to unmarshal deprecated fields")
+		}
+
+		dst.Spec.Version = deprecated.Version
+		dst.Spec.ServiceMeterLabels = deprecated.ServiceMeterLabels
+		dst.Spec.PodMeterLabels = deprecated.PodMeterLabels
+
+		annotations := dst.GetAnnotations()
+		delete(annotations, MeterDefinitionDeprecatedFieldsAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		dst.SetAnnotations(annotations)
+	}
+
+	if src.Spec.Workloads != nil {
+		dst.Spec.Workloads = make([]Workload, 0, len(src.Spec.Workloads))
+		fields := map[string]workloadFields{}
+
+		for _, workload := range src.Spec.Workloads {
+			dst.Spec.Workloads = append(dst.Spec.Workloads, convertWorkloadFrom(workload))
+
+			field := workloadFields{
+				CustomResource: workload.CustomResource.DeepCopy(),
+				MatchAnyOwner:  workload.MatchAnyOwner,
+			}
+
+			if workload.OwnerDepth != nil {
+				depth := *workload.OwnerDepth
+				field.OwnerDepth = &depth
+			}
+
+			if workload.FieldFilters != nil {
+				field.FieldFilters = make([]v1beta1.FieldFilter, len(workload.FieldFilters))
+				for i := range workload.FieldFilters {
+					workload.FieldFilters[i].DeepCopyInto(&field.FieldFilters[i])
+				}
+			}
+
+			if !field.isEmpty() {
+				fields[workload.Name] = field
+			}
+		}
+
+		if len(fields) != 0 {
+			data, err := json.Marshal(fields)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal workload fields")
+			}
+
+			annotations := dst.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[MeterDefinitionWorkloadFieldsAnnotation] = string(data)
+			dst.SetAnnotations(annotations)
+		}
+	}
+
+	if src.Status.WorkloadStatus != nil {
+		data, err := json.Marshal(src.Status.WorkloadStatus)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal workload status")
+		}
+
+		annotations := dst.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[MeterDefinitionWorkloadStatusAnnotation] = string(data)
+		dst.SetAnnotations(annotations)
+	}
+
+	dst.Status = MeterDefinitionStatus{}
+
+	if src.Status.Conditions != nil {
+		dst.Status.Conditions = make(status.Conditions, len(src.Status.Conditions))
+		copy(dst.Status.Conditions, src.Status.Conditions)
+	}
+
+	if src.Status.WorkloadResources != nil {
+		dst.Status.WorkloadResources = make([]WorkloadResource, 0, len(src.Status.WorkloadResources))
+		for _, resource := range src.Status.WorkloadResources {
+			dst.Status.WorkloadResources = append(dst.Status.WorkloadResources, WorkloadResource{
+				ReferencedWorkloadName:  resource.ReferencedWorkloadName,
+				NamespacedNameReference: *resource.NamespacedNameReference.DeepCopy(),
+			})
+		}
+	}
+
+	return nil
+}
+
+func convertWorkloadTo(src Workload) v1beta1.Workload {
+	dst := v1beta1.Workload{
+		Name:               src.Name,
+		WorkloadType:       v1beta1.WorkloadType(src.WorkloadType),
+		OwnerCRD:           src.OwnerCRD.DeepCopy(),
+		LabelSelector:      src.LabelSelector.DeepCopy(),
+		AnnotationSelector: src.AnnotationSelector.DeepCopy(),
+	}
+
+	if src.MetricLabels != nil {
+		dst.MetricLabels = make([]v1beta1.MeterLabelQuery, 0, len(src.MetricLabels))
+		for _, label := range src.MetricLabels {
+			dst.MetricLabels = append(dst.MetricLabels, v1beta1.MeterLabelQuery(label))
+		}
+	}
+
+	return dst
+}
+
+func convertWorkloadFrom(src v1beta1.Workload) Workload {
+	dst := Workload{
+		Name:               src.Name,
+		WorkloadType:       WorkloadType(src.WorkloadType),
+		OwnerCRD:           src.OwnerCRD.DeepCopy(),
+		LabelSelector:      src.LabelSelector.DeepCopy(),
+		AnnotationSelector: src.AnnotationSelector.DeepCopy(),
+	}
+
+	if src.MetricLabels != nil {
+		dst.MetricLabels = make([]MeterLabelQuery, 0, len(src.MetricLabels))
+		for _, label := range src.MetricLabels {
+			dst.MetricLabels = append(dst.MetricLabels, MeterLabelQuery(label))
+		}
+	}
+
+	return dst
+}
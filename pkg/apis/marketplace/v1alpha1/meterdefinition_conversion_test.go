@@ -0,0 +1,159 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/gotidy/ptr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MeterDefinition conversion", func() {
+	var meterdef *MeterDefinition
+
+	BeforeEach(func() {
+		meterdef = &MeterDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "example-meterdefinition",
+				Namespace:   "default",
+				Annotations: map[string]string{"foo": "bar"},
+			},
+			Spec: MeterDefinitionSpec{
+				Group:              "partner.metering.com",
+				Version:            ptr.String("v1alpha1"),
+				Kind:               "App",
+				ServiceMeterLabels: []string{"rpc_durations_seconds"},
+				PodMeterLabels:     []string{"container_cpu_usage"},
+				InstalledBy: &common.NamespacedNameReference{
+					Name:      "app-operator.v1.0.0",
+					Namespace: "default",
+				},
+				WorkloadVertexType: WorkloadVertexOperatorGroup,
+				Workloads: []Workload{
+					{
+						Name:         "app-pods",
+						WorkloadType: WorkloadTypePod,
+						OwnerCRD: &common.GroupVersionKind{
+							APIVersion: "partner.metering.com/v1alpha1",
+							Kind:       "App",
+						},
+						MetricLabels: []MeterLabelQuery{
+							{Label: "container_spec_cpu_shares", Aggregation: "sum"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should convert to v1beta1", func() {
+		hub := &v1beta1.MeterDefinition{}
+		Expect(meterdef.ConvertTo(hub)).To(Succeed())
+
+		Expect(hub.Spec.Group).To(Equal("partner.metering.com"))
+		Expect(hub.Spec.Kind).To(Equal("App"))
+		Expect(hub.Spec.WorkloadVertexType).To(Equal(v1beta1.WorkloadVertexOperatorGroup))
+		Expect(hub.Spec.Workloads).To(HaveLen(1))
+		Expect(hub.Spec.Workloads[0].WorkloadType).To(Equal(v1beta1.WorkloadTypePod))
+		Expect(hub.Annotations).To(HaveKey(MeterDefinitionDeprecatedFieldsAnnotation))
+	})
+
+	It("should round trip through v1beta1", func() {
+		hub := &v1beta1.MeterDefinition{}
+		Expect(meterdef.ConvertTo(hub)).To(Succeed())
+
+		converted := &MeterDefinition{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+		Expect(converted.ObjectMeta).To(Equal(meterdef.ObjectMeta))
+		Expect(converted.Spec).To(Equal(meterdef.Spec))
+	})
+
+	It("should not add the annotation without deprecated fields", func() {
+		meterdef.Spec.Version = nil
+		meterdef.Spec.ServiceMeterLabels = nil
+		meterdef.Spec.PodMeterLabels = nil
+
+		hub := &v1beta1.MeterDefinition{}
+		Expect(meterdef.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).ToNot(HaveKey(MeterDefinitionDeprecatedFieldsAnnotation))
+
+		converted := &MeterDefinition{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec).To(Equal(meterdef.Spec))
+	})
+
+	It("should keep v1beta1 workload fields through v1alpha1", func() {
+		hub := &v1beta1.MeterDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-meterdefinition",
+				Namespace: "default",
+			},
+			Spec: v1beta1.MeterDefinitionSpec{
+				Group: "partner.metering.com",
+				Kind:  "App",
+				Workloads: []v1beta1.Workload{
+					{
+						Name:         "app-instances",
+						WorkloadType: v1beta1.WorkloadTypeCustomResource,
+						CustomResource: &v1beta1.CustomResourceWorkload{
+							GroupVersionKind: common.GroupVersionKind{
+								APIVersion: "partner.metering.com/v1alpha1",
+								Kind:       "App",
+							},
+							ValueFields: []v1beta1.CustomResourceValueField{
+								{Name: "size", JSONPath: "{.spec.size}"},
+							},
+						},
+						MatchAnyOwner: true,
+						FieldFilters: []v1beta1.FieldFilter{
+							{JSONPath: "{.spec.edition}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"enterprise"}},
+						},
+						MetricLabels: []v1beta1.MeterLabelQuery{
+							{Label: "app_size", Aggregation: "max"},
+						},
+					},
+				},
+			},
+			Status: v1beta1.MeterDefinitionStatus{
+				WorkloadStatus: []v1beta1.WorkloadStatus{
+					{
+						Name:               "app-instances",
+						Metric:             "app_size",
+						CurrentMetricValue: "3",
+						LastReadTime:       metav1.NewTime(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC).Local()),
+					},
+				},
+			},
+		}
+
+		spoke := &MeterDefinition{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Annotations).To(HaveKey(MeterDefinitionWorkloadFieldsAnnotation))
+		Expect(spoke.Annotations).To(HaveKey(MeterDefinitionWorkloadStatusAnnotation))
+
+		converted := &v1beta1.MeterDefinition{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+		Expect(converted.ObjectMeta).To(Equal(hub.ObjectMeta))
+		Expect(converted.Spec).To(Equal(hub.Spec))
+		Expect(converted.Status).To(Equal(hub.Status))
+	})
+})
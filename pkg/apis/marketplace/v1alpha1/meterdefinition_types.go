@@ -16,7 +16,6 @@ package v1alpha1
 
 import (
 	"encoding/json"
-	"strings"
 
 	"github.com/operator-framework/operator-sdk/pkg/status"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
@@ -167,14 +166,20 @@ type WorkloadResource struct {
 	common.NamespacedNameReference `json:",inline"`
 }
 
+// ByAlphabetical sorts workload resources by workload name, namespace and
+// name.
 type ByAlphabetical []WorkloadResource
 
 func (a ByAlphabetical) Len() int      { return len(a) }
 func (a ByAlphabetical) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a ByAlphabetical) Less(i, j int) bool {
-	return strings.Compare(a[i].ReferencedWorkloadName, a[j].ReferencedWorkloadName) > 0 &&
-		strings.Compare(a[i].NamespacedNameReference.Namespace, a[j].NamespacedNameReference.Namespace) > 0 &&
-		strings.Compare(a[i].NamespacedNameReference.Name, a[j].NamespacedNameReference.Name) > 0
+	if a[i].ReferencedWorkloadName != a[j].ReferencedWorkloadName {
+		return a[i].ReferencedWorkloadName < a[j].ReferencedWorkloadName
+	}
+	if a[i].Namespace != a[j].Namespace {
+		return a[i].Namespace < a[j].Namespace
+	}
+	return a[i].Name < a[j].Name
 }
 
 func NewWorkloadResource(workload Workload, obj interface{}, scheme *runtime.Scheme) (*WorkloadResource, error) {
@@ -246,7 +251,7 @@ type MeterDefinitionStatus struct {
 }
 
 // MeterDefinition defines the meter workloads used to enable pay for
-// use billing.
+// use billing. Deprecated: use v1beta1.
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 //
 // +k8s:openapi-gen=true
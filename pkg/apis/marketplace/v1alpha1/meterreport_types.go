@@ -41,8 +41,42 @@ type MeterReportSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +optional
 	MeterDefinitions []MeterDefinition `json:"meterDefinitions,omitempty"`
+
+	// Type of the report. Scheduled reports are created and pruned by the
+	// MeterBase. AdHoc reports are created by users for an arbitrary window
+	// and are ignored by the MeterBase. Default is Scheduled.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Type MeterReportType `json:"type,omitempty"`
+
+	// RunRequest is an opaque token. When it changes, the outcome of the
+	// previous run is archived in the status history and the report is run
+	// again. The marketplace.redhat.com/runRequest annotation is used if
+	// this field is empty.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	RunRequest string `json:"runRequest,omitempty"`
 }
 
+// MeterReportType is the origin of a MeterReport.
+// +kubebuilder:validation:Enum=Scheduled;AdHoc
+type MeterReportType string
+
+const (
+	MeterReportTypeScheduled MeterReportType = "Scheduled"
+	MeterReportTypeAdHoc     MeterReportType = "AdHoc"
+)
+
+const (
+	// MeterReportRunRequestAnnotation requests a new run of a report when
+	// spec.runRequest is not set.
+	MeterReportRunRequestAnnotation = "marketplace.redhat.com/runRequest"
+
+	// MeterReportMaxRunHistory is the number of previous runs kept in the
+	// status of a report.
+	MeterReportMaxRunHistory = 10
+)
+
 // MeterReportStatus defines the observed state of MeterReport
 type MeterReportStatus struct {
 	// Conditions represent the latest available observations of an object's stateonfig
@@ -70,6 +104,192 @@ type MeterReportStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +optional
 	QueryErrorList []string `json:"queryErrorList,omitempty"`
+
+	// ReportID is the ID of the report generated by the reporter.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	ReportID string `json:"reportID,omitempty"`
+
+	// ReporterVersion is the version of the reporter that generated the report.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	ReporterVersion string `json:"reporterVersion,omitempty"`
+
+	// RunStartTime is when the reporter started generating the report.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	RunStartTime *metav1.Time `json:"runStartTime,omitempty"`
+
+	// RunFinishTime is when the reporter finished generating the report.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	RunFinishTime *metav1.Time `json:"runFinishTime,omitempty"`
+
+	// Files are the files generated for the report.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	Files []MeterReportFile `json:"files,omitempty"`
+
+	// MeterDefinitionResults are the query results for each MeterDefinition
+	// in the report.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	MeterDefinitionResults []MeterDefinitionResult `json:"meterDefinitionResults,omitempty"`
+
+	// MeterTotals are the totals of each metric by workload for the report
+	// period. They are used to build MeterReportSummaries.
+	// +optional
+	MeterTotals []MeterTotal `json:"meterTotals,omitempty"`
+
+	// LastRunRequest is the run request token the current run was started for.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	LastRunRequest string `json:"lastRunRequest,omitempty"`
+
+	// RunHistory is the outcome of previous runs of the report, oldest first.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	RunHistory []MeterReportRun `json:"runHistory,omitempty"`
+}
+
+// MeterReportFile is a file generated for a report.
+type MeterReportFile struct {
+	// Name of the file.
+	Name string `json:"name"`
+
+	// SHA256 is the hex encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// MeterDefinitionResult is the outcome of the queries for a MeterDefinition
+// in a report.
+type MeterDefinitionResult struct {
+	// Name of the MeterDefinition.
+	Name string `json:"name"`
+
+	// Namespace of the MeterDefinition.
+	Namespace string `json:"namespace"`
+
+	// QueryCount is the number of queries run.
+	QueryCount int `json:"queryCount"`
+
+	// RowCount is the number of rows returned by the queries.
+	RowCount int `json:"rowCount"`
+
+	// Duration is the time spent running the queries.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// Errors are the errors from the queries.
+	// +optional
+	Errors []MeterReportQueryError `json:"errors,omitempty"`
+}
+
+// MeterTotal is the sum of the values of a metric for a workload.
+type MeterTotal struct {
+	// Domain is the meter group of the MeterDefinition.
+	Domain string `json:"domain"`
+
+	// Kind is the meter kind of the MeterDefinition.
+	Kind string `json:"kind"`
+
+	// Namespace of the workload.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Workload is the name of the metered object.
+	// +optional
+	Workload string `json:"workload,omitempty"`
+
+	// Metric is the metric label.
+	Metric string `json:"metric"`
+
+	// Value is the sum of the metric values as a decimal string.
+	Value string `json:"value"`
+}
+
+// Key returns the total without its value, totals of the same metric of a
+// workload have the same key.
+func (t MeterTotal) Key() MeterTotal {
+	t.Value = ""
+	return t
+}
+
+// Less orders totals by domain, kind, namespace, workload and metric.
+func (t MeterTotal) Less(o MeterTotal) bool {
+	switch {
+	case t.Domain != o.Domain:
+		return t.Domain < o.Domain
+	case t.Kind != o.Kind:
+		return t.Kind < o.Kind
+	case t.Namespace != o.Namespace:
+		return t.Namespace < o.Namespace
+	case t.Workload != o.Workload:
+		return t.Workload < o.Workload
+	default:
+		return t.Metric < o.Metric
+	}
+}
+
+// MeterReportQueryError is an error from the query of a metric.
+type MeterReportQueryError struct {
+	// Workload is the name of the workload the metric belongs to.
+	// +optional
+	Workload string `json:"workload,omitempty"`
+
+	// Label is the metric label that was queried.
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// Error is the error message.
+	Error string `json:"error"`
+}
+
+// MeterReportRun is the archived outcome of a previous run of a report.
+type MeterReportRun struct {
+	// RunRequest is the run request token the run was started for.
+	// +optional
+	RunRequest string `json:"runRequest,omitempty"`
+
+	// ArchivedTime is when the run was replaced by a new run.
+	ArchivedTime metav1.Time `json:"archivedTime"`
+
+	// Conditions of the report at the end of the run.
+	// +optional
+	Conditions status.Conditions `json:"conditions,omitempty"`
+
+	// ReportID is the ID of the report generated by the run.
+	// +optional
+	ReportID string `json:"reportID,omitempty"`
+
+	// RunStartTime is when the reporter started generating the report.
+	// +optional
+	RunStartTime *metav1.Time `json:"runStartTime,omitempty"`
+
+	// RunFinishTime is when the reporter finished generating the report.
+	// +optional
+	RunFinishTime *metav1.Time `json:"runFinishTime,omitempty"`
+
+	// Files are the files generated by the run.
+	// +optional
+	Files []MeterReportFile `json:"files,omitempty"`
+
+	// AssociatedJob is the job of the run.
+	// +optional
+	AssociatedJob *common.JobReference `json:"jobReference,omitempty"`
+
+	// MetricUploadCount is the number of metrics in the report
+	// +optional
+	MetricUploadCount *int `json:"metricUploadCount,omitempty"`
+
+	// UploadID is the ID associated with the upload
+	// +optional
+	UploadID *types.UID `json:"uploadUID,omitempty"`
+
+	// QueryErrorList shows if there were any errors from queries
+	// for the report.
+	// +optional
+	QueryErrorList []string `json:"queryErrorList,omitempty"`
 }
 
 const (
@@ -79,6 +299,10 @@ const (
 	ReportConditionReasonJobWaiting    status.ConditionReason = "Waiting"
 	ReportConditionReasonJobFinished   status.ConditionReason = "Finished"
 	ReportConditionReasonJobErrored    status.ConditionReason = "Errored"
+
+	ReportConditionTypeRunSucceeded   status.ConditionType   = "RunSucceeded"
+	ReportConditionReasonRunSucceeded status.ConditionReason = "Succeeded"
+	ReportConditionReasonRunFailed    status.ConditionReason = "Failed"
 )
 
 var (
@@ -112,12 +336,36 @@ var (
 		Reason:  ReportConditionReasonJobErrored,
 		Message: "Job has errored",
 	}
+	ReportConditionRunSucceeded = status.Condition{
+		Type:    ReportConditionTypeRunSucceeded,
+		Status:  corev1.ConditionTrue,
+		Reason:  ReportConditionReasonRunSucceeded,
+		Message: "Report was generated",
+	}
 )
 
+// NewReportConditionRunFailed returns the condition of a reporter run that
+// failed with err.
+func NewReportConditionRunFailed(err error) status.Condition {
+	return status.Condition{
+		Type:    ReportConditionTypeRunSucceeded,
+		Status:  corev1.ConditionFalse,
+		Reason:  ReportConditionReasonRunFailed,
+		Message: err.Error(),
+	}
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // MeterReport is the Schema for the meterreports API
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Start",type="string",format="date-time",JSONPath=".spec.startTime"
+// +kubebuilder:printcolumn:name="End",type="string",format="date-time",JSONPath=".spec.endTime"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"JobRunning\")].reason"
+// +kubebuilder:printcolumn:name="Metrics",type="integer",JSONPath=".status.metricUploadCount"
+// +kubebuilder:printcolumn:name="Report ID",type="string",JSONPath=".status.reportID",priority=1
+// +kubebuilder:printcolumn:name="Reporter",type="string",JSONPath=".status.reporterVersion",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +operator-sdk:gen-csv:customresourcedefinitions.displayName="Reports"
 // +kubebuilder:resource:path=meterreports,scope=Namespaced
 type MeterReport struct {
@@ -140,3 +388,59 @@ type MeterReportList struct {
 func init() {
 	SchemeBuilder.Register(&MeterReport{}, &MeterReportList{})
 }
+
+// IsAdHoc returns true if the report was requested by a user instead of
+// the MeterBase schedule.
+func (r *MeterReport) IsAdHoc() bool {
+	return r.Spec.Type == MeterReportTypeAdHoc
+}
+
+// GetRunRequest returns the run request token of the report, preferring
+// spec.runRequest over the run request annotation.
+func (r *MeterReport) GetRunRequest() string {
+	if r.Spec.RunRequest != "" {
+		return r.Spec.RunRequest
+	}
+
+	return r.GetAnnotations()[MeterReportRunRequestAnnotation]
+}
+
+// ArchiveRun moves the outcome of the current run into the run history and
+// resets the status for a new run.
+func (r *MeterReport) ArchiveRun(now metav1.Time) {
+	run := MeterReportRun{
+		RunRequest:        r.Status.LastRunRequest,
+		ArchivedTime:      now,
+		AssociatedJob:     r.Status.AssociatedJob,
+		MetricUploadCount: r.Status.MetricUploadCount,
+		UploadID:          r.Status.UploadID,
+		QueryErrorList:    r.Status.QueryErrorList,
+		ReportID:          r.Status.ReportID,
+		RunStartTime:      r.Status.RunStartTime,
+		RunFinishTime:     r.Status.RunFinishTime,
+		Files:             r.Status.Files,
+	}
+
+	if r.Status.Conditions != nil {
+		run.Conditions = *r.Status.Conditions
+	}
+
+	r.Status.RunHistory = append(r.Status.RunHistory, run)
+	if len(r.Status.RunHistory) > MeterReportMaxRunHistory {
+		r.Status.RunHistory = r.Status.RunHistory[len(r.Status.RunHistory)-MeterReportMaxRunHistory:]
+	}
+
+	conds := status.NewConditions(ReportConditionJobNotStarted)
+	r.Status.Conditions = &conds
+	r.Status.AssociatedJob = nil
+	r.Status.MetricUploadCount = nil
+	r.Status.UploadID = nil
+	r.Status.QueryErrorList = nil
+	r.Status.ReportID = ""
+	r.Status.ReporterVersion = ""
+	r.Status.RunStartTime = nil
+	r.Status.RunFinishTime = nil
+	r.Status.Files = nil
+	r.Status.MeterDefinitionResults = nil
+	r.Status.MeterTotals = nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MeterReportSummarySpec defines the desired state of MeterReportSummary
+// +k8s:openapi-gen=true
+type MeterReportSummarySpec struct {
+	// StartTime of the billing period
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime of the billing period
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	EndTime metav1.Time `json:"endTime"`
+
+	// Timezone is the IANA name of the timezone the billing period is split
+	// into days in. Defaults to UTC.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// FinalizeAfter is how long after the end of the billing period the summary
+	// waits for pending reports before it is finalized. Defaults to 48h.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	FinalizeAfter *metav1.Duration `json:"finalizeAfter,omitempty"`
+}
+
+// MeterReportSummaryStatus defines the observed state of MeterReportSummary
+// +k8s:openapi-gen=true
+type MeterReportSummaryStatus struct {
+	// Conditions represent the latest available observations of the summary
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	Conditions status.Conditions `json:"conditions,omitempty"`
+
+	// Totals are the sums of each metric by workload over the billing period
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	Totals []MeterTotal `json:"totals,omitempty"`
+
+	// Reports are the names of the MeterReports included in the totals
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	Reports []string `json:"reports,omitempty"`
+
+	// MissingDays are the days of the period that no MeterReport covers
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	MissingDays []string `json:"missingDays,omitempty"`
+
+	// ErroredDays are the days of the period with a MeterReport that errored
+	// or had query errors
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	ErroredDays []string `json:"erroredDays,omitempty"`
+
+	// PendingDays are the days of the period with a MeterReport that has not
+	// finished
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	PendingDays []string `json:"pendingDays,omitempty"`
+
+	// LastUpdateTime is when the totals were last calculated
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// FinalizedTime is when the summary was finalized. A finalized summary is
+	// no longer updated.
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	FinalizedTime *metav1.Time `json:"finalizedTime,omitempty"`
+}
+
+const (
+	SummaryConditionTypeFinalized       status.ConditionType   = "Finalized"
+	SummaryConditionReasonPeriodOpen    status.ConditionReason = "PeriodOpen"
+	SummaryConditionReasonReportPending status.ConditionReason = "ReportsPending"
+	SummaryConditionReasonFinalized     status.ConditionReason = "Finalized"
+)
+
+var (
+	SummaryConditionPeriodOpen = status.Condition{
+		Type:    SummaryConditionTypeFinalized,
+		Status:  corev1.ConditionFalse,
+		Reason:  SummaryConditionReasonPeriodOpen,
+		Message: "Billing period has not ended",
+	}
+	SummaryConditionReportsPending = status.Condition{
+		Type:    SummaryConditionTypeFinalized,
+		Status:  corev1.ConditionFalse,
+		Reason:  SummaryConditionReasonReportPending,
+		Message: "Billing period has ended and reports are pending",
+	}
+	SummaryConditionFinalized = status.Condition{
+		Type:    SummaryConditionTypeFinalized,
+		Status:  corev1.ConditionTrue,
+		Reason:  SummaryConditionReasonFinalized,
+		Message: "Summary has been finalized",
+	}
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterReportSummary is the Schema for the meterreportsummaries API
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Start",type="string",format="date-time",JSONPath=".spec.startTime"
+// +kubebuilder:printcolumn:name="End",type="string",format="date-time",JSONPath=".spec.endTime"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Finalized\")].reason"
+// +kubebuilder:printcolumn:name="Finalized",type="string",format="date-time",JSONPath=".status.finalizedTime",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Report Summaries"
+// +kubebuilder:resource:path=meterreportsummaries,scope=Namespaced
+type MeterReportSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeterReportSummarySpec   `json:"spec,omitempty"`
+	Status MeterReportSummaryStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterReportSummaryList contains a list of MeterReportSummary
+type MeterReportSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeterReportSummary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeterReportSummary{}, &MeterReportSummaryList{})
+}
+
+// IsFinalized returns true if the summary is frozen.
+func (s *MeterReportSummary) IsFinalized() bool {
+	return s.Status.FinalizedTime != nil
+}
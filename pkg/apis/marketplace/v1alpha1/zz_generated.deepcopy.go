@@ -408,6 +408,21 @@ func (in *MeterBaseSpec) DeepCopyInto(out *MeterBaseSpec) {
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Reporting != nil {
+		in, out := &in.Reporting, &out.Reporting
+		*out = new(ReportingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricState != nil {
+		in, out := &in.MetricState, &out.MetricState
+		*out = new(MetricStateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReporterJobTemplate != nil {
+		in, out := &in.ReporterJobTemplate, &out.ReporterJobTemplate
+		*out = new(ReporterJobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -534,6 +549,28 @@ func (in *MeterDefinitionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterDefinitionResult) DeepCopyInto(out *MeterDefinitionResult) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]MeterReportQueryError, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterDefinitionResult.
+func (in *MeterDefinitionResult) DeepCopy() *MeterDefinitionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterDefinitionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeterDefinitionSpec) DeepCopyInto(out *MeterDefinitionSpec) {
 	*out = *in
@@ -656,6 +693,22 @@ func (in *MeterReport) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportFile) DeepCopyInto(out *MeterReportFile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportFile.
+func (in *MeterReportFile) DeepCopy() *MeterReportFile {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeterReportList) DeepCopyInto(out *MeterReportList) {
 	*out = *in
@@ -689,6 +742,79 @@ func (in *MeterReportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportQueryError) DeepCopyInto(out *MeterReportQueryError) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportQueryError.
+func (in *MeterReportQueryError) DeepCopy() *MeterReportQueryError {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportQueryError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportRun) DeepCopyInto(out *MeterReportRun) {
+	*out = *in
+	in.ArchivedTime.DeepCopyInto(&out.ArchivedTime)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(status.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RunStartTime != nil {
+		in, out := &in.RunStartTime, &out.RunStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunFinishTime != nil {
+		in, out := &in.RunFinishTime, &out.RunFinishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]MeterReportFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssociatedJob != nil {
+		in, out := &in.AssociatedJob, &out.AssociatedJob
+		*out = new(common.JobReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricUploadCount != nil {
+		in, out := &in.MetricUploadCount, &out.MetricUploadCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.UploadID != nil {
+		in, out := &in.UploadID, &out.UploadID
+		*out = new(types.UID)
+		**out = **in
+	}
+	if in.QueryErrorList != nil {
+		in, out := &in.QueryErrorList, &out.QueryErrorList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportRun.
+func (in *MeterReportRun) DeepCopy() *MeterReportRun {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeterReportSpec) DeepCopyInto(out *MeterReportSpec) {
 	*out = *in
@@ -753,6 +879,38 @@ func (in *MeterReportStatus) DeepCopyInto(out *MeterReportStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RunStartTime != nil {
+		in, out := &in.RunStartTime, &out.RunStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunFinishTime != nil {
+		in, out := &in.RunFinishTime, &out.RunFinishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]MeterReportFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.MeterDefinitionResults != nil {
+		in, out := &in.MeterDefinitionResults, &out.MeterDefinitionResults
+		*out = make([]MeterDefinitionResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MeterTotals != nil {
+		in, out := &in.MeterTotals, &out.MeterTotals
+		*out = make([]MeterTotal, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunHistory != nil {
+		in, out := &in.RunHistory, &out.RunHistory
+		*out = make([]MeterReportRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -766,6 +924,162 @@ func (in *MeterReportStatus) DeepCopy() *MeterReportStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportSummary) DeepCopyInto(out *MeterReportSummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportSummary.
+func (in *MeterReportSummary) DeepCopy() *MeterReportSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MeterReportSummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportSummaryList) DeepCopyInto(out *MeterReportSummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MeterReportSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportSummaryList.
+func (in *MeterReportSummaryList) DeepCopy() *MeterReportSummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportSummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MeterReportSummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportSummarySpec) DeepCopyInto(out *MeterReportSummarySpec) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.FinalizeAfter != nil {
+		in, out := &in.FinalizeAfter, &out.FinalizeAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportSummarySpec.
+func (in *MeterReportSummarySpec) DeepCopy() *MeterReportSummarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportSummarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterReportSummaryStatus) DeepCopyInto(out *MeterReportSummaryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(status.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Totals != nil {
+		in, out := &in.Totals, &out.Totals
+		*out = make([]MeterTotal, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingDays != nil {
+		in, out := &in.MissingDays, &out.MissingDays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ErroredDays != nil {
+		in, out := &in.ErroredDays, &out.ErroredDays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDays != nil {
+		in, out := &in.PendingDays, &out.PendingDays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FinalizedTime != nil {
+		in, out := &in.FinalizedTime, &out.FinalizedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterReportSummaryStatus.
+func (in *MeterReportSummaryStatus) DeepCopy() *MeterReportSummaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterReportSummaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeterTotal) DeepCopyInto(out *MeterTotal) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeterTotal.
+func (in *MeterTotal) DeepCopy() *MeterTotal {
+	if in == nil {
+		return nil
+	}
+	out := new(MeterTotal)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Options) DeepCopyInto(out *Options) {
 	*out = *in
@@ -799,6 +1113,27 @@ func (in *Options) DeepCopy() *Options {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricStateSpec) DeepCopyInto(out *MetricStateSpec) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricStateSpec.
+func (in *MetricStateSpec) DeepCopy() *MetricStateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricStateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
 	*out = *in
@@ -1171,6 +1506,103 @@ func (in *RemoteResourceS3Status) DeepCopy() *RemoteResourceS3Status {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReporterJobTemplateSpec) DeepCopyInto(out *ReporterJobTemplateSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReporterJobTemplateSpec.
+func (in *ReporterJobTemplateSpec) DeepCopy() *ReporterJobTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReporterJobTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportingSpec) DeepCopyInto(out *ReportingSpec) {
+	*out = *in
+	if in.RetentionCount != nil {
+		in, out := &in.RetentionCount, &out.RetentionCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetentionDuration != nil {
+		in, out := &in.RetentionDuration, &out.RetentionDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StartDate != nil {
+		in, out := &in.StartDate, &out.StartDate
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportingSpec.
+func (in *ReportingSpec) DeepCopy() *ReportingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Request) DeepCopyInto(out *Request) {
 	*out = *in
@@ -0,0 +1,115 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterMeterDefinitionSpec defines the desired metering spec of a
+// cluster-wide meter definition
+// +k8s:openapi-gen=true
+type ClusterMeterDefinitionSpec struct {
+	// Group defines the operator group of the meter
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Group string `json:"group"`
+
+	// Kind defines the primary CRD kind of the meter
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Kind string `json:"kind"`
+
+	// NamespaceSelector selects the namespaces the workloads are metered in.
+	// All namespaces are selected if it is omitted.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ExcludedNamespaces are never metered, even if they are selected by
+	// the NamespaceSelector.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// Workloads identify the workloads to meter.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +kubebuilder:validation:MinItems=1
+	Workloads []Workload `json:"workloads"`
+}
+
+// ClusterMeterDefinition defines meter workloads across namespaces. It is
+// cluster scoped so only cluster administrators can create or edit it.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=clustermeterdefinitions,scope=Cluster
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Cluster Meter Definitions"
+// +genclient
+// +genclient:nonNamespaced
+type ClusterMeterDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterMeterDefinitionSpec `json:"spec,omitempty"`
+	Status MeterDefinitionStatus      `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterMeterDefinitionList contains a list of ClusterMeterDefinition
+type ClusterMeterDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterMeterDefinition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterMeterDefinition{}, &ClusterMeterDefinitionList{})
+}
+
+// ToMeterDefinition returns the cluster meter definition as a MeterDefinition
+// without a namespace that selects its namespaces by label, so it can be
+// looked up, stored and reported on like any other meter definition. The
+// excluded namespaces are not part of the MeterDefinition spec.
+func (def *ClusterMeterDefinition) ToMeterDefinition() *MeterDefinition {
+	meterdef := &MeterDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "MeterDefinition",
+		},
+		Spec: MeterDefinitionSpec{
+			Group:               def.Spec.Group,
+			Kind:                def.Spec.Kind,
+			WorkloadVertexType:  WorkloadVertexNamespace,
+			VertexLabelSelector: def.Spec.NamespaceSelector.DeepCopy(),
+		},
+	}
+
+	def.ObjectMeta.DeepCopyInto(&meterdef.ObjectMeta)
+	meterdef.Namespace = ""
+
+	if def.Spec.Workloads != nil {
+		meterdef.Spec.Workloads = make([]Workload, len(def.Spec.Workloads))
+		for i := range def.Spec.Workloads {
+			def.Spec.Workloads[i].DeepCopyInto(&meterdef.Spec.Workloads[i])
+		}
+	}
+
+	def.Status.DeepCopyInto(&meterdef.Status)
+
+	return meterdef
+}
@@ -0,0 +1,424 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	MeterDefConditionTypeHasResult           status.ConditionType   = "FoundMatches"
+	MeterDefConditionReasonNoResultsInStatus status.ConditionReason = "No results in status"
+	MeterDefConditionReasonResultsInStatus   status.ConditionReason = "Results in status"
+
+	MeterDefConditionTypeReporting           status.ConditionType   = "Reporting"
+	MeterDefConditionReasonMetricValuesFound status.ConditionReason = "MetricValuesFound"
+	MeterDefConditionReasonNoMetricValues    status.ConditionReason = "NoMetricValues"
+	MeterDefConditionReasonMetricQueryFailed status.ConditionReason = "MetricQueryFailed"
+)
+
+var (
+	MeterDefConditionNoResults = status.Condition{
+		Type:    MeterDefConditionTypeHasResult,
+		Status:  corev1.ConditionFalse,
+		Reason:  MeterDefConditionReasonNoResultsInStatus,
+		Message: "Meter definition has no results yet.",
+	}
+	MeterDefConditionHasResults = status.Condition{
+		Type:    MeterDefConditionTypeHasResult,
+		Status:  corev1.ConditionTrue,
+		Reason:  MeterDefConditionReasonResultsInStatus,
+		Message: "Meter definition has results.",
+	}
+	MeterDefConditionReporting = status.Condition{
+		Type:    MeterDefConditionTypeReporting,
+		Status:  corev1.ConditionTrue,
+		Reason:  MeterDefConditionReasonMetricValuesFound,
+		Message: "Meter definition metrics have values.",
+	}
+	MeterDefConditionNoMetricValues = status.Condition{
+		Type:    MeterDefConditionTypeReporting,
+		Status:  corev1.ConditionFalse,
+		Reason:  MeterDefConditionReasonNoMetricValues,
+		Message: "Meter definition metrics have no values.",
+	}
+	MeterDefConditionMetricQueryFailed = status.Condition{
+		Type:    MeterDefConditionTypeReporting,
+		Status:  corev1.ConditionFalse,
+		Reason:  MeterDefConditionReasonMetricQueryFailed,
+		Message: "Meter definition metric queries failed.",
+	}
+)
+
+// MeterDefinitionSpec defines the desired metering spec
+// +k8s:openapi-gen=true
+type MeterDefinitionSpec struct {
+	// Group defines the operator group of the meter
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Group string `json:"group"`
+
+	// Kind defines the primary CRD kind of the meter
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Kind string `json:"kind"`
+
+	// InstalledBy is a reference to the CSV that install the meter
+	// definition. This is used to determine an operator group.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:hidden"
+	// +optional
+	InstalledBy *common.NamespacedNameReference `json:"installedBy,omitempty"`
+
+	// WorkloadVertexType is the top most object of a workload. It allows
+	// you to identify the upper bounds of your workloads.
+	// +kubebuilder:validation:Enum=Namespace;OperatorGroup
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Namespace,urn:alm:descriptor:com.tectonic.ui:select:OperatorGroup"
+	// +optional
+	WorkloadVertexType WorkloadVertex `json:"workloadVertexType,omitempty"`
+
+	// VertexLabelSelector is used when Namespace is selected. Can be omitted
+	// if you select OperatorGroup
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:fieldDependency:workloadVertexType:Namespace"
+	// +optional
+	VertexLabelSelector *metav1.LabelSelector `json:"workloadVertexLabelSelector,omitempty"`
+
+	// Workloads identify the workloads to meter.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +kubebuilder:validation:MinItems=1
+	Workloads []Workload `json:"workloads"`
+}
+
+const (
+	WorkloadVertexOperatorGroup WorkloadVertex = "OperatorGroup"
+	WorkloadVertexNamespace     WorkloadVertex = "Namespace"
+)
+
+const (
+	WorkloadTypePod            WorkloadType = "Pod"
+	WorkloadTypeService        WorkloadType = "Service"
+	WorkloadTypeServiceMonitor WorkloadType = "ServiceMonitor"
+	WorkloadTypePVC            WorkloadType = "PersistentVolumeClaim"
+	WorkloadTypeDeployment     WorkloadType = "Deployment"
+	WorkloadTypeStatefulSet    WorkloadType = "StatefulSet"
+	WorkloadTypeDaemonSet      WorkloadType = "DaemonSet"
+	WorkloadTypeJob            WorkloadType = "Job"
+	WorkloadTypeCronJob        WorkloadType = "CronJob"
+	WorkloadTypeCustomResource WorkloadType = "CustomResource"
+)
+
+const (
+	// OwnerWildcard matches any version or kind of an OwnerCRD.
+	OwnerWildcard = "*"
+
+	// DefaultOwnerDepth is the number of levels of owners walked when a
+	// workload has no OwnerDepth.
+	DefaultOwnerDepth int32 = 5
+)
+
+type WorkloadVertex string
+type WorkloadType string
+
+// Workload helps identify what to target for metering.
+type Workload struct {
+	// Name of the workload, must be unique in a meter definition.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Name string `json:"name"`
+
+	// WorkloadType identifies the type of workload to look for. This can be
+	// a pod, service, persistent volume claim, one of the apps and batch
+	// controllers or a custom resource.
+	// +kubebuilder:validation:Enum=Pod;Service;PersistentVolumeClaim;Deployment;StatefulSet;DaemonSet;Job;CronJob;CustomResource
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Pod,urn:alm:descriptor:com.tectonic.ui:select:Service,urn:alm:descriptor:com.tectonic.ui:select:PersistentVolumeClaim,urn:alm:descriptor:com.tectonic.ui:select:Deployment,urn:alm:descriptor:com.tectonic.ui:select:StatefulSet,urn:alm:descriptor:com.tectonic.ui:select:DaemonSet,urn:alm:descriptor:com.tectonic.ui:select:Job,urn:alm:descriptor:com.tectonic.ui:select:CronJob,urn:alm:descriptor:com.tectonic.ui:select:CustomResource"
+	WorkloadType WorkloadType `json:"type"`
+
+	// OwnerCRD is the name of the GVK to look for as the owner of all the
+	// meterable assets. If omitted, the labels and annotations are used instead.
+	// The version of the apiVersion may be * to match any version of the
+	// group, like partner.metering.com/*, and the kind may be * to match any
+	// kind of the group.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	OwnerCRD *common.GroupVersionKind `json:"ownerCRD,omitempty"`
+
+	// OwnerDepth is the number of levels of owners walked looking for the
+	// OwnerCRD. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	OwnerDepth *int32 `json:"ownerDepth,omitempty"`
+
+	// MatchAnyOwner walks all the owner references looking for the OwnerCRD
+	// instead of only the controller reference.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	MatchAnyOwner bool `json:"matchAnyOwner,omitempty"`
+
+	// CustomResource is the custom resource to meter. Required when the
+	// workload type is CustomResource.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	CustomResource *CustomResourceWorkload `json:"customResource,omitempty"`
+
+	// LabelSelector are used to filter to the correct workload.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// AnnotationSelector are used to filter to the correct workload.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+
+	// FieldFilters are used to filter to the correct workload by the values
+	// of its fields. All the filters must match.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	FieldFilters []FieldFilter `json:"fieldFilters,omitempty"`
+
+	// MetricLabels are the labels to collect
+	// +kubebuilder:validation:MinItems=1
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	MetricLabels []MeterLabelQuery `json:"metricLabels"`
+}
+
+// CustomResourceWorkload identifies the instances of a custom resource to
+// meter.
+type CustomResourceWorkload struct {
+	// GroupVersionKind of the custom resource
+	common.GroupVersionKind `json:",inline"`
+
+	// ValueFields are numeric fields of each instance to expose as values,
+	// for example the replicas or size of the instance.
+	// +optional
+	ValueFields []CustomResourceValueField `json:"valueFields,omitempty"`
+}
+
+// CustomResourceValueField is a numeric field of a custom resource.
+type CustomResourceValueField struct {
+	// Name of the value, exposed as the field label
+	Name string `json:"name"`
+
+	// JSONPath of the field, for example {.spec.replicas}
+	JSONPath string `json:"jsonPath"`
+}
+
+// FieldFilterOperator is the relationship of a field to the values of a
+// field filter.
+type FieldFilterOperator string
+
+const (
+	FieldFilterOpIn           FieldFilterOperator = "In"
+	FieldFilterOpNotIn        FieldFilterOperator = "NotIn"
+	FieldFilterOpExists       FieldFilterOperator = "Exists"
+	FieldFilterOpDoesNotExist FieldFilterOperator = "DoesNotExist"
+	FieldFilterOpHasPrefix    FieldFilterOperator = "HasPrefix"
+)
+
+// FieldFilter matches the values of a field of a workload. If the JSONPath
+// finds several values, like the images of all the containers of a pod, the
+// filter matches if any of them matches.
+type FieldFilter struct {
+	// JSONPath of the field, for example {.status.phase} or
+	// {.spec.containers[*].image}
+	JSONPath string `json:"jsonPath"`
+
+	// Operator is the relationship of the field to the values. In, NotIn and
+	// HasPrefix require values, Exists and DoesNotExist require none.
+	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist;HasPrefix
+	Operator FieldFilterOperator `json:"operator"`
+
+	// Values to compare the field to
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// WorkloadResource is a resource found by a workload of a meter definition.
+type WorkloadResource struct {
+	ReferencedWorkloadName string `json:"referencedWorkloadName"`
+
+	common.NamespacedNameReference `json:",inline"`
+}
+
+// ByAlphabetical sorts workload resources by workload name, namespace and
+// name.
+type ByAlphabetical []WorkloadResource
+
+func (a ByAlphabetical) Len() int      { return len(a) }
+func (a ByAlphabetical) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByAlphabetical) Less(i, j int) bool {
+	if a[i].ReferencedWorkloadName != a[j].ReferencedWorkloadName {
+		return a[i].ReferencedWorkloadName < a[j].ReferencedWorkloadName
+	}
+	if a[i].Namespace != a[j].Namespace {
+		return a[i].Namespace < a[j].Namespace
+	}
+	return a[i].Name < a[j].Name
+}
+
+func NewWorkloadResource(workload Workload, obj interface{}, scheme *runtime.Scheme) (*WorkloadResource, error) {
+	accessor, err := meta.Accessor(obj)
+
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := common.NewGroupVersionKind(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkloadResource{
+		ReferencedWorkloadName: workload.Name,
+		NamespacedNameReference: common.NamespacedNameReference{
+			Name:             accessor.GetName(),
+			Namespace:        accessor.GetNamespace(),
+			UID:              accessor.GetUID(),
+			GroupVersionKind: &gvk,
+		},
+	}, nil
+}
+
+// WorkloadStatus provides quick status to check if
+// workloads are working correctly
+type WorkloadStatus struct {
+	// Name of the workload, must be unique in a meter definition.
+	Name string `json:"name"`
+
+	// Metric is the label of the metric of the workload
+	Metric string `json:"metric"`
+
+	// CurrentMetricValue is the latest value of the metric
+	CurrentMetricValue string `json:"currentValue"`
+
+	// LastReadTime is the time of the latest value
+	LastReadTime metav1.Time `json:"lastReadTime"`
+}
+
+// MeterLabelQuery helps define a meter label to build and search for
+type MeterLabelQuery struct {
+	// Label is the name of the meter
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Label string `json:"label"`
+
+	// Query to use for the label
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// Aggregation to use with the query
+	// +kubebuilder:validation:Enum:=sum;min;max;avg
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:sum,urn:alm:descriptor:com.tectonic.ui:select:min,urn:alm:descriptor:com.tectonic.ui:select:max,urn:alm:descriptor:com.tectonic.ui:select:avg"
+	// +optional
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+// MeterDefinitionStatus defines the observed state of MeterDefinition
+// +k8s:openapi-gen=true
+// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+type MeterDefinitionStatus struct {
+
+	// Conditions represent the latest available observations of an object's state
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	// +optional
+	Conditions status.Conditions `json:"conditions,omitempty"`
+
+	// WorkloadResources is the list of resources discovered by
+	// this meter definition
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	WorkloadResources []WorkloadResource `json:"workloadResources,omitempty"`
+
+	// WorkloadStatus is the latest value of each metric of the workloads
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	WorkloadStatus []WorkloadStatus `json:"workloadStatus,omitempty"`
+}
+
+// MeterDefinition defines the meter workloads used to enable pay for
+// use billing.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=meterdefinitions,scope=Namespaced
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Meter Definitions"
+// +genclient
+type MeterDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeterDefinitionSpec   `json:"spec,omitempty"`
+	Status MeterDefinitionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionList contains a list of MeterDefinition
+type MeterDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeterDefinition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeterDefinition{}, &MeterDefinitionList{})
+}
+
+// Hub marks v1beta1 as the version MeterDefinitions are converted through.
+func (*MeterDefinition) Hub() {}
+
+func (meterdef *MeterDefinition) BuildMeterDefinitionFromString(meterdefString, name, namespace, nameLabel, namespaceLabel string) (*MeterDefinition, error) {
+	data := []byte(meterdefString)
+	err := json.Unmarshal(data, meterdef)
+	if err != nil {
+		return meterdef, err
+	}
+
+	meterdef.SetInstalledBy(name, namespace, nameLabel, namespaceLabel)
+
+	return meterdef, nil
+}
+
+// SetInstalledBy records the CSV that installed the meter definition in
+// its annotations and spec, and moves it to the CSV namespace.
+func (meterdef *MeterDefinition) SetInstalledBy(name, namespace, nameLabel, namespaceLabel string) {
+	csvInfo := make(map[string]string)
+	csvInfo[nameLabel] = name
+	csvInfo[namespaceLabel] = namespace
+	meterdef.SetAnnotations(csvInfo)
+
+	meterdef.Namespace = namespace
+	meterdef.Spec.InstalledBy = &common.NamespacedNameReference{
+		Name:      name,
+		Namespace: namespace,
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MeterDefinitionBindingSpec defines the desired state of MeterDefinitionBinding
+// +k8s:openapi-gen=true
+type MeterDefinitionBindingSpec struct {
+	// TemplateName is the name of the MeterDefinitionTemplate in the
+	// namespace of the binding
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	TemplateName string `json:"templateName"`
+
+	// Parameters are the values of the parameters of the template
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// MeterDefinitionBindingStatus defines the observed state of MeterDefinitionBinding
+// +k8s:openapi-gen=true
+type MeterDefinitionBindingStatus struct {
+	// Conditions represent the latest available observations of the binding
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	Conditions status.Conditions `json:"conditions,omitempty"`
+
+	// MeterDefinitionName is the name of the MeterDefinition instantiated
+	// from the template
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	MeterDefinitionName string `json:"meterDefinitionName,omitempty"`
+
+	// TemplateGeneration is the generation of the template the
+	// MeterDefinition was last instantiated from
+	// +optional
+	TemplateGeneration int64 `json:"templateGeneration,omitempty"`
+}
+
+const (
+	BindingConditionTypeInstantiated       status.ConditionType   = "Instantiated"
+	BindingConditionReasonInstantiated     status.ConditionReason = "Instantiated"
+	BindingConditionReasonTemplateNotFound status.ConditionReason = "TemplateNotFound"
+	BindingConditionReasonTemplateInvalid  status.ConditionReason = "TemplateInvalid"
+)
+
+var (
+	BindingConditionInstantiated = status.Condition{
+		Type:    BindingConditionTypeInstantiated,
+		Status:  corev1.ConditionTrue,
+		Reason:  BindingConditionReasonInstantiated,
+		Message: "MeterDefinition has been instantiated from the template",
+	}
+	BindingConditionTemplateNotFound = status.Condition{
+		Type:    BindingConditionTypeInstantiated,
+		Status:  corev1.ConditionFalse,
+		Reason:  BindingConditionReasonTemplateNotFound,
+		Message: "MeterDefinitionTemplate was not found",
+	}
+	BindingConditionTemplateInvalid = status.Condition{
+		Type:   BindingConditionTypeInstantiated,
+		Status: corev1.ConditionFalse,
+		Reason: BindingConditionReasonTemplateInvalid,
+	}
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionBinding is the Schema for the meterdefinitionbindings API
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.templateName"
+// +kubebuilder:printcolumn:name="MeterDefinition",type="string",JSONPath=".status.meterDefinitionName"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Instantiated\")].reason"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=meterdefinitionbindings,scope=Namespaced
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Meter Definition Bindings"
+type MeterDefinitionBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeterDefinitionBindingSpec   `json:"spec,omitempty"`
+	Status MeterDefinitionBindingStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionBindingList contains a list of MeterDefinitionBinding
+type MeterDefinitionBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeterDefinitionBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeterDefinitionBinding{}, &MeterDefinitionBindingList{})
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MeterDefinitionRevisionUIDLabel is the label with the UID of the meter
+// definition of a revision.
+const MeterDefinitionRevisionUIDLabel = "marketplace.redhat.com/meterDefinition.uid"
+
+// MeterDefinitionRevisionDeletedAnnotation is the annotation with the time the
+// meter definition of a revision was found deleted.
+const MeterDefinitionRevisionDeletedAnnotation = "marketplace.redhat.com/meterDefinition.deletedTime"
+
+// MeterDefinitionRevisionSpec is the snapshot of the spec of a meter
+// definition
+// +k8s:openapi-gen=true
+type MeterDefinitionRevisionSpec struct {
+	// MeterDefinition is the meter definition the revision is of. The
+	// namespace is empty for a ClusterMeterDefinition.
+	MeterDefinition common.NamespacedNameReference `json:"meterDefinition"`
+
+	// Revision is the number of the revision, starting at 1.
+	Revision int64 `json:"revision"`
+
+	// Hash identifies the meter rules of the revision. Reports are stamped
+	// with it.
+	Hash string `json:"hash"`
+
+	// EffectiveTime is when the revision started to be used for metering.
+	EffectiveTime metav1.Time `json:"effectiveTime"`
+
+	// MeterDefinitionSpec is the spec of the meter definition at the revision.
+	MeterDefinitionSpec MeterDefinitionSpec `json:"meterDefinitionSpec"`
+
+	// ExcludedNamespaces are the excluded namespaces of a
+	// ClusterMeterDefinition at the revision.
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+}
+
+// MeterDefinitionRevision is an immutable snapshot of the spec of a
+// MeterDefinition or ClusterMeterDefinition. A revision is recorded every time
+// the spec changes and is kept after the meter definition is deleted, so the
+// meter rules used for any report can be proven later. Revisions are deleted
+// once no report kept by the MeterBase retention can be metered with them.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="MeterDefinition",type="string",JSONPath=".spec.meterDefinition.name"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.meterDefinition.namespace"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".spec.revision"
+// +kubebuilder:printcolumn:name="Hash",type="string",JSONPath=".spec.hash"
+// +kubebuilder:printcolumn:name="Effective",type="string",format="date-time",JSONPath=".spec.effectiveTime"
+// +kubebuilder:resource:path=meterdefinitionrevisions,scope=Cluster
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Meter Definition Revisions"
+type MeterDefinitionRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MeterDefinitionRevisionSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionRevisionList contains a list of MeterDefinitionRevision
+type MeterDefinitionRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeterDefinitionRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeterDefinitionRevision{}, &MeterDefinitionRevisionList{})
+}
+
+// MeterDefinitionRevisionName is the name of a revision of the meter
+// definition with the UID. UIDs are unique across namespaces so revisions
+// of meter definitions with the same name do not collide.
+func MeterDefinitionRevisionName(uid types.UID, revision int64) string {
+	return fmt.Sprintf("%s-%d", uid, revision)
+}
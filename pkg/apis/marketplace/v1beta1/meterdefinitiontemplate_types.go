@@ -0,0 +1,79 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MeterDefinitionTemplateSpec defines the desired state of MeterDefinitionTemplate
+// +k8s:openapi-gen=true
+type MeterDefinitionTemplateSpec struct {
+	// Parameters are the parameters bindings pass to the template
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+
+	// Template is a Go template of a MeterDefinition in YAML or JSON. The
+	// name and namespace of the binding are available as .Name and .Namespace
+	// and its parameters as .Parameters. Values are inserted as is, use quote
+	// to insert them as YAML strings, for example {{ quote .Parameters.kind }}.
+	// If the MeterDefinition has no name, the name of the binding is used.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	Template string `json:"template"`
+}
+
+// TemplateParameter is a parameter of a MeterDefinitionTemplate.
+type TemplateParameter struct {
+	// Name of the parameter
+	Name string `json:"name"`
+
+	// Description of the parameter
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Default value used when a binding does not set the parameter
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Required parameters must be set by each binding
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionTemplate is the Schema for the meterdefinitiontemplates API
+// +kubebuilder:resource:path=meterdefinitiontemplates,scope=Namespaced
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Meter Definition Templates"
+type MeterDefinitionTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MeterDefinitionTemplateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MeterDefinitionTemplateList contains a list of MeterDefinitionTemplate
+type MeterDefinitionTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeterDefinitionTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeterDefinitionTemplate{}, &MeterDefinitionTemplateList{})
+}
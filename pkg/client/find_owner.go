@@ -16,62 +16,101 @@ package client
 
 import (
 	"context"
-	"strings"
 
 	"emperror.dev/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 )
 
-
+// FindOwnerHelper looks up the owners of owner references. Each kind of
+// owner looked up starts an informer so repeated lookups are served from its
+// cache; until the informer has synced the API server is used instead. Only
+// the metadata of the owners is fetched and cached.
 type FindOwnerHelper struct {
-	client     dynamic.Interface
+	ctx        context.Context
+	client     metadata.Interface
 	restMapper meta.RESTMapper
+	informers  metadatainformer.SharedInformerFactory
 }
 
 func NewFindOwnerHelper(
-	inClient dynamic.Interface,
+	ctx context.Context,
+	inClient metadata.Interface,
 	restMapper meta.RESTMapper,
 ) *FindOwnerHelper {
 	return &FindOwnerHelper{
+		ctx:        ctx,
 		client:     inClient,
 		restMapper: restMapper,
+		informers:  metadatainformer.NewSharedInformerFactory(inClient, 0),
 	}
 }
 
+// FindOwner returns the controller of the owner.
 func (f *FindOwnerHelper) FindOwner(name, namespace string, lookupOwner *metav1.OwnerReference) (owner *metav1.OwnerReference, err error) {
-	apiVersionSplit := strings.Split(lookupOwner.APIVersion, "/")
-	var group, version string
+	o, err := f.getOwner(name, namespace, lookupOwner)
 
-	if len(apiVersionSplit) == 1 {
-		version = lookupOwner.APIVersion
-	} else {
-		group = apiVersionSplit[0]
-		version = apiVersionSplit[1]
+	if err != nil {
+		return nil, err
+	}
+
+	owner = metav1.GetControllerOf(o)
+	return owner, nil
+}
+
+// FindOwners returns all the owner references of the owner.
+func (f *FindOwnerHelper) FindOwners(name, namespace string, lookupOwner *metav1.OwnerReference) ([]metav1.OwnerReference, error) {
+	o, err := f.getOwner(name, namespace, lookupOwner)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.GetOwnerReferences(), nil
+}
+
+func (f *FindOwnerHelper) getOwner(name, namespace string, lookupOwner *metav1.OwnerReference) (metav1.Object, error) {
+	gv, err := schema.ParseGroupVersion(lookupOwner.APIVersion)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse apiVersion")
 	}
 
 	mapping, err := f.restMapper.RESTMapping(schema.GroupKind{
-		Group: group,
+		Group: gv.Group,
 		Kind:  lookupOwner.Kind,
-	}, version)
+	}, gv.Version)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get mapping")
 	}
 
-	result, err := f.client.Resource(mapping.Resource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
 
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get resource")
+	informer := f.informers.ForResource(mapping.Resource)
+	f.informers.Start(f.ctx.Done())
+
+	var result runtime.Object
+
+	if informer.Informer().HasSynced() {
+		if namespace == "" {
+			result, err = informer.Lister().Get(name)
+		} else {
+			result, err = informer.Lister().ByNamespace(namespace).Get(name)
+		}
+	} else {
+		result, err = f.client.Resource(mapping.Resource).Namespace(namespace).Get(f.ctx, name, metav1.GetOptions{})
 	}
 
-	o, err := meta.Accessor(result)
 	if err != nil {
-		return
+		return nil, errors.Wrap(err, "failed to get resource")
 	}
 
-	owner = metav1.GetControllerOf(o)
-	return owner, nil
+	return meta.Accessor(result)
 }
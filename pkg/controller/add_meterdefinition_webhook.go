@@ -0,0 +1,76 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"os"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// MeterDefinitionWebhook registers the conversion webhook between the
+// MeterDefinition versions and the validating webhooks of MeterDefinitions,
+// ClusterMeterDefinitions and MeterDefinitionRevisions. Set
+// ENABLE_WEBHOOKS=false to run without it, for example when running the
+// operator locally without serving certs.
+type MeterDefinitionWebhook struct {
+	*baseDefinition
+}
+
+func ProvideMeterDefinitionWebhook() *MeterDefinitionWebhook {
+	return &MeterDefinitionWebhook{
+		baseDefinition: &baseDefinition{
+			AddFunc: func(mgr manager.Manager) error {
+				if os.Getenv("ENABLE_WEBHOOKS") == "false" {
+					return nil
+				}
+
+				err := builder.WebhookManagedBy(mgr).
+					For(&marketplacev1beta1.MeterDefinition{}).
+					Complete()
+				if err != nil {
+					return err
+				}
+
+				mgr.GetWebhookServer().Register(
+					meter_definition.MeterDefinitionValidatingWebhookPath,
+					&webhook.Admission{Handler: &meter_definition.MeterDefinitionValidator{
+						Mapper: mgr.GetRESTMapper(),
+					}},
+				)
+
+				mgr.GetWebhookServer().Register(
+					meter_definition.ClusterMeterDefinitionValidatingWebhookPath,
+					&webhook.Admission{Handler: &meter_definition.ClusterMeterDefinitionValidator{
+						Mapper: mgr.GetRESTMapper(),
+					}},
+				)
+
+				mgr.GetWebhookServer().Register(
+					meter_definition.MeterDefinitionRevisionValidatingWebhookPath,
+					&webhook.Admission{Handler: &meter_definition.MeterDefinitionRevisionValidator{}},
+				)
+
+				return nil
+			},
+			FlagSetFunc: func() *pflag.FlagSet { return nil },
+		},
+	}
+}
@@ -18,8 +18,11 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"time"
 
+	merrors "emperror.dev/errors"
+
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
@@ -28,6 +31,7 @@ import (
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	utils "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -83,7 +87,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				}
 
 				_, olmOk := ann["olm.copiedFrom"]
-				_, annOk := ann[utils.CSV_METERDEFINITION_ANNOTATION]
+				annOk := hasMeterDefinitionAnnotation(ann)
 
 				if annOk && !olmOk {
 					return true
@@ -109,7 +113,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				}
 
 				_, olmOk := ann["olm.copiedFrom"]
-				_, annOk := ann[utils.CSV_METERDEFINITION_ANNOTATION]
+				annOk := hasMeterDefinitionAnnotation(ann)
 
 				if annOk && !olmOk {
 					return true
@@ -133,7 +137,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				}
 
 				_, olmOk := ann["olm.copiedFrom"]
-				_, annOk := ann[utils.CSV_METERDEFINITION_ANNOTATION]
+				annOk := hasMeterDefinitionAnnotation(ann)
 
 				if annOk && !olmOk {
 					return true
@@ -148,7 +152,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				}
 
 				_, olmOk := ann["olm.copiedFrom"]
-				_, annOk := ann[utils.CSV_METERDEFINITION_ANNOTATION]
+				annOk := hasMeterDefinitionAnnotation(ann)
 
 				if annOk && !olmOk {
 					return true
@@ -165,7 +169,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &marketplacev1alpha1.MeterDefinition{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &marketplacev1beta1.MeterDefinition{}}, &handler.EnqueueRequestForOwner{
 		IsController: false,
 		OwnerType: &olmv1alpha1.ClusterServiceVersion{},
 	})
@@ -214,14 +218,10 @@ func (r *ReconcileClusterServiceVersion) Reconcile(request reconcile.Request) (r
 	}
 
 	// check if CSV is being deleted
-	// if yes -> finalizer logic
+	// if yes -> finalizer logic, nothing is created for a deleted CSV
 	// if no -> do nothing
 	if !CSV.ObjectMeta.DeletionTimestamp.IsZero() {
-		//Run finalization logic for the CSV.
-		result, isReconcile, err := r.finalizeCSV(CSV)
-		if isReconcile {
-			return result, err
-		}
+		return r.finalizeCSV(CSV)
 	}
 
 	result, isRequeue, err := r.reconcileMeterDefAnnotation(CSV, annotations)
@@ -301,175 +301,279 @@ func (r *ReconcileClusterServiceVersion) Reconcile(request reconcile.Request) (r
 	return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
 }
 
-func (r *ReconcileClusterServiceVersion) finalizeCSV(CSV *olmv1alpha1.ClusterServiceVersion) (reconcile.Result, bool, error) {
+// finalizeCSV removes the finalizer earlier versions added to the CSV once its
+// MeterDefinitions are deleted. The CSV isn't given the finalizer anymore, the
+// MeterDefinitions are garbage collected through their owner reference, so the
+// uninstall of an operator is never blocked on this operator.
+func (r *ReconcileClusterServiceVersion) finalizeCSV(CSV *olmv1alpha1.ClusterServiceVersion) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Name", CSV.GetName(), "Request.Namespace", CSV.GetNamespace())
 
+	if !utils.Contains(CSV.GetFinalizers(), utils.CSV_FINALIZER) {
+		reqLogger.Info("csv is being deleted")
+		return reconcile.Result{}, nil
+	}
+
 	reqLogger.Info("deleting csv")
 	if err := r.deleteExternalResources(CSV); err != nil {
 		reqLogger.Error(err, "unable to delete csv")
-		return reconcile.Result{}, false, err
+		return reconcile.Result{}, err
 	}
 
-	// Stop reconciliation as the item is being deleted
-	return reconcile.Result{}, true, nil
+	CSV.SetFinalizers(utils.RemoveKey(CSV.GetFinalizers(), utils.CSV_FINALIZER))
+	if err := r.client.Update(context.TODO(), CSV); err != nil {
+		reqLogger.Error(err, "Failed to remove finalizer from clusterserviceversion")
+		return reconcile.Result{}, err
+	}
 
+	// Stop reconciliation as the item is being deleted
+	return reconcile.Result{}, nil
 }
 
-// deleteExternalResources searches for the MeterDefinition created by the CSV, if it's found delete it
+// deleteExternalResources searches for the MeterDefinitions created by the CSV and deletes them
 func (r *ReconcileClusterServiceVersion) deleteExternalResources(CSV *olmv1alpha1.ClusterServiceVersion) error {
 	reqLogger := log.WithValues("Request.Name", CSV.GetName(), "Request.Namespace", CSV.GetNamespace())
 	reqLogger.Info("deleting csv")
-	var err error
 
-	annotations := CSV.GetAnnotations()
-	if annotations == nil {
-		reqLogger.Info("No annotations for this CSV")
-		return nil
+	list := &marketplacev1beta1.MeterDefinitionList{}
+	if err := r.client.List(context.TODO(), list, client.InNamespace(CSV.GetNamespace())); err != nil {
+		reqLogger.Error(err, "Could not retrieve the existing MeterDefinitions")
+		return err
 	}
 
-	meterDefinitionString, ok := annotations[utils.CSV_METERDEFINITION_ANNOTATION]
-	if !ok {
-		reqLogger.Info("No value for ", "key: ", utils.CSV_METERDEFINITION_ANNOTATION)
-		return nil
-	}
+	for i := range list.Items {
+		meterDefinition := &list.Items[i]
 
-	meterDefinition := &marketplacev1alpha1.MeterDefinition{}
-	_, err = meterDefinition.BuildMeterDefinitionFromString(meterDefinitionString, CSV.GetName(), CSV.GetNamespace(), utils.CSV_ANNOTATION_NAME, utils.CSV_ANNOTATION_NAMESPACE)
-	if err != nil {
-		reqLogger.Error(err, "Could not build a local copy of the MeterDefinition")
-		return err
-	}
+		if !isInstalledBy(meterDefinition, CSV) {
+			continue
+		}
 
-	err = r.client.Delete(context.TODO(), meterDefinition, client.PropagationPolicy(metav1.DeletePropagationForeground))
-	if err != nil && errors.IsNotFound(err) {
-		return err
+		err := r.client.Delete(context.TODO(), meterDefinition, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		reqLogger.Info("found and deleted MeterDefinition", "name", meterDefinition.Name)
 	}
-	reqLogger.Info("found and deleted MeterDefinition")
-	return nil
 
+	return nil
 }
 
 // reconcileMeterDefAnnotation checks the Annotations for the rhm CSV
-// If the CSV is new, we tag it and create a MeterDefinition
-// If the CSV is old, we check if the actual MeterDefinition matches the CSV (json) MeterDefinition
+// If the CSV is new, we tag it
+// The MeterDefinitions in the annotations are created or updated by name and the
+// MeterDefinitions installed by the CSV that are no longer in the annotations are deleted
 func (r *ReconcileClusterServiceVersion) reconcileMeterDefAnnotation(CSV *olmv1alpha1.ClusterServiceVersion, annotations map[string]string) (reconcile.Result, bool, error) {
-	var err error
 	reqLogger := log.WithValues("CSV.Name", CSV.Name, "CSV.Namespace", CSV.Namespace)
 
-	// checks if it is possible to build MeterDefinition from annotations of CSV
-	reqLogger.Info("retrieving MeterDefinition string from csv")
-	meterDefinitionString, ok := annotations[utils.CSV_METERDEFINITION_ANNOTATION]
-	if !ok {
-		reqLogger.Info("No value for ", "key: ", utils.CSV_METERDEFINITION_ANNOTATION)
+	// checks if it is possible to build MeterDefinitions from annotations of CSV
+	reqLogger.Info("retrieving MeterDefinition strings from csv")
+	if !hasMeterDefinitionAnnotation(annotations) {
+		reqLogger.Info("No value for ", "keys: ", []string{utils.CSV_METERDEFINITION_ANNOTATION, utils.CSV_METERDEFINITIONS_ANNOTATION})
 		return reconcile.Result{}, false, nil
 	}
 
-	// builds a meterdefinition from our string (from the annotation)
+	// builds the meterdefinitions from our strings (from the annotations)
 	reqLogger.Info("retrieval successful")
-	meterDefinition := &marketplacev1alpha1.MeterDefinition{}
-	_, err = meterDefinition.BuildMeterDefinitionFromString(
-		meterDefinitionString,
-		CSV.GetName(),
-		CSV.GetNamespace(),
-		utils.CSV_ANNOTATION_NAME,
-		utils.CSV_ANNOTATION_NAMESPACE)
+	meterDefinitions, err := buildMeterDefinitions(annotations, CSV)
+	if err != nil {
+		reqLogger.Error(err, "Could not build a local copy of the MeterDefinitions")
+		return reconcile.Result{}, true, err
+	}
+
+	// Case 1: The CSV is new: we must track it
+	if annotations[trackMeterTag] != "true" {
+		reqLogger.Info("csv is new")
+		annotations[trackMeterTag] = "true"
+		CSV.SetAnnotations(annotations)
+
+		if err := r.client.Update(context.TODO(), CSV); err != nil {
+			reqLogger.Error(err, "Failed to patch clusterserviceversion with trackMeter Tag")
+			return reconcile.Result{}, true, err
+		}
+		reqLogger.Info("Patched clusterserviceversion with trackMeter tag")
+	}
+
+	// Case 2: compare the actual MeterDefinitions vs. the expected MeterDefinitions
+	list := &marketplacev1beta1.MeterDefinitionList{}
+	err = r.client.List(context.TODO(), list, client.InNamespace(CSV.GetNamespace()))
 
 	if err != nil {
-		reqLogger.Error(err, "Could not build a local copy of the MeterDefinition")
+		reqLogger.Error(err, "Could not retrieve the existing MeterDefinitions")
 		return reconcile.Result{}, true, err
 	}
 
-	// Case 1: The CSV is old: compare vs. expected MeterDefinition
-	list := &marketplacev1alpha1.MeterDefinitionList{}
-	err = r.client.List(context.TODO(), list, client.InNamespace(meterDefinition.GetNamespace()))
+	// Find the meterdefs, we're use the InstalledBy field
+	actualMeterDefinitions := make(map[string]*marketplacev1beta1.MeterDefinition)
+	for i := range list.Items {
+		meterDef := &list.Items[i]
+		if isInstalledBy(meterDef, CSV) {
+			actualMeterDefinitions[meterDef.Name] = meterDef
+		}
+	}
 
+	gvk, err := apiutil.GVKForObject(CSV, r.scheme)
 	if err != nil {
-		reqLogger.Error(err, "Could not retrieve the existing MeterDefinition")
 		return reconcile.Result{}, true, err
 	}
 
-	var actualMeterDefinition *marketplacev1alpha1.MeterDefinition
+	ref := metav1.OwnerReference{
+		APIVersion:         gvk.GroupVersion().String(),
+		Kind:               gvk.Kind,
+		Name:               CSV.GetName(),
+		UID:                CSV.GetUID(),
+		BlockOwnerDeletion: pointer.BoolPtr(false),
+		Controller:         pointer.BoolPtr(false),
+	}
+
+	changed := false
+
+	for _, meterDefinition := range meterDefinitions {
+		actualMeterDefinition, ok := actualMeterDefinitions[meterDefinition.Name]
 
-	// Find the meterdef, we're use the InstalledBy field
-	for _, meterDef := range list.Items {
-		if meterDef.Spec.InstalledBy != nil &&
-			meterDef.Spec.InstalledBy.Namespace == CSV.Namespace &&
-			meterDef.Spec.InstalledBy.Name == CSV.Name {
-			actualMeterDefinition = &meterDef
-			reqLogger.Info("Found meterDef", "meterDef", meterDef)
-			break
+		// If nil, we create
+		if !ok {
+			reqLogger.Info("creating MeterDefinition", "name", meterDefinition.Name)
+			meterDefinition.ObjectMeta.OwnerReferences = append(meterDefinition.ObjectMeta.OwnerReferences, ref)
+
+			err = r.client.Create(context.TODO(), meterDefinition)
+			if err != nil {
+				reqLogger.Error(err, "Could not create MeterDefinition", "name", meterDefinition.Name)
+				return reconcile.Result{Requeue: true}, true, err
+			}
+
+			changed = true
+			continue
 		}
-	}
 
-	// Check if the name has changed
-	if actualMeterDefinition != nil && actualMeterDefinition.Name != meterDefinition.Name {
-		reqLogger.Info("Discovered name change", "name", actualMeterDefinition.Name, "newName", meterDefinition.Name)
-		err := r.client.Delete(context.TODO(), actualMeterDefinition)
+		delete(actualMeterDefinitions, meterDefinition.Name)
+
+		// If not nil, we update
+		if reflect.DeepEqual(meterDefinition.Spec, actualMeterDefinition.Spec) {
+			reqLogger.Info("meter definition matches", "name", meterDefinition.Name)
+			continue
+		}
+
+		reqLogger.Info("The actual meterdefinition is different from the expected meterdefinition", "name", meterDefinition.Name)
 
+		patch, err := json.Marshal(meterDefinition)
+		if err != nil {
+			return reconcile.Result{}, true, err
+		}
+		err = r.client.Patch(context.TODO(), meterDefinition, client.RawPatch(types.MergePatchType, patch))
 		if err != nil {
+			return reconcile.Result{Requeue: true}, true, err
+		}
+		reqLogger.Info("Patch to update MeterDefinition successful", "name", meterDefinition.Name)
+		changed = true
+	}
+
+	// The remaining meterdefs were removed from the annotations or renamed
+	for _, actualMeterDefinition := range actualMeterDefinitions {
+		reqLogger.Info("Deleting MeterDefinition no longer in the annotations", "name", actualMeterDefinition.Name)
+
+		err := r.client.Delete(context.TODO(), actualMeterDefinition)
+		if err != nil && !errors.IsNotFound(err) {
 			return reconcile.Result{}, true, err
 		}
 
-		actualMeterDefinition = nil
+		changed = true
 	}
 
-	// If not nil, we update
-	if actualMeterDefinition != nil {
-		if !reflect.DeepEqual(meterDefinition.Spec, actualMeterDefinition.Spec) && !reflect.DeepEqual(meterDefinition.ObjectMeta, actualMeterDefinition.ObjectMeta) {
-			reqLogger.Info("The actual meterdefinition is different from the expected meterdefinition")
+	if changed {
+		reqLogger.Info("MeterDefinitions changed. Requeuing")
+		return reconcile.Result{Requeue: true}, true, nil
+	}
 
-			patch, err := json.Marshal(meterDefinition)
-			if err != nil {
-				return reconcile.Result{}, true, err
+	return reconcile.Result{}, false, nil
+}
+
+// hasMeterDefinitionAnnotation returns true if the CSV has a MeterDefinition annotation.
+func hasMeterDefinitionAnnotation(annotations map[string]string) bool {
+	_, ok := annotations[utils.CSV_METERDEFINITION_ANNOTATION]
+	_, listOk := annotations[utils.CSV_METERDEFINITIONS_ANNOTATION]
+	return ok || listOk
+}
+
+// isInstalledBy returns true if the MeterDefinition was installed by the CSV.
+func isInstalledBy(meterDefinition *marketplacev1beta1.MeterDefinition, CSV *olmv1alpha1.ClusterServiceVersion) bool {
+	return meterDefinition.Spec.InstalledBy != nil &&
+		meterDefinition.Spec.InstalledBy.Namespace == CSV.Namespace &&
+		meterDefinition.Spec.InstalledBy.Name == CSV.Name
+}
+
+// buildMeterDefinitions builds the MeterDefinitions from the meterDefinition and
+// meterDefinitions annotations of the CSV. Either annotation may be a single
+// MeterDefinition or a JSON array of them. Names must be unique.
+func buildMeterDefinitions(annotations map[string]string, CSV *olmv1alpha1.ClusterServiceVersion) ([]*marketplacev1beta1.MeterDefinition, error) {
+	meterDefinitions := []*marketplacev1beta1.MeterDefinition{}
+	names := make(map[string]bool)
+
+	for _, key := range []string{utils.CSV_METERDEFINITION_ANNOTATION, utils.CSV_METERDEFINITIONS_ANNOTATION} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+
+		var meterDefinitionStrings []json.RawMessage
+
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &meterDefinitionStrings); err != nil {
+				return nil, merrors.Wrapf(err, "failed to parse annotation %s", key)
 			}
-			err = r.client.Patch(context.TODO(), meterDefinition, client.RawPatch(types.MergePatchType, patch))
+		} else {
+			meterDefinitionStrings = []json.RawMessage{json.RawMessage(value)}
+		}
+
+		for _, meterDefinitionString := range meterDefinitionStrings {
+			meterDefinition, err := buildMeterDefinition(string(meterDefinitionString), CSV)
 			if err != nil {
-				return reconcile.Result{Requeue: true}, true, err
+				return nil, merrors.Wrapf(err, "failed to build MeterDefinition from annotation %s", key)
 			}
-			reqLogger.Info("Patch to update MeterDefinition successful. Requeuing")
-			return reconcile.Result{Requeue: true}, true, nil
-		}
-		reqLogger.Info("meter definition matches")
-		return reconcile.Result{}, false, nil
-	}
 
-	// Case 2: The CSV is new: we must track it & we must create the Meter Definition
-	reqLogger.Info("csv is new")
-	annotations[trackMeterTag] = "true"
-	CSV.SetAnnotations(annotations)
-	if err := r.client.Update(context.TODO(), CSV); err != nil {
-		reqLogger.Error(err, "Failed to patch clusterserviceversion with trackMeter Tag")
-		return reconcile.Result{}, true, err
-	}
-	reqLogger.Info("Patched clusterserviceversion with trackMeter tag")
+			if names[meterDefinition.Name] {
+				return nil, merrors.Errorf("duplicate MeterDefinition %s in annotations", meterDefinition.Name)
+			}
 
-	gvk, err := apiutil.GVKForObject(CSV, r.scheme)
-	if err != nil {
-		return reconcile.Result{}, true, err
+			names[meterDefinition.Name] = true
+			meterDefinitions = append(meterDefinitions, meterDefinition)
+		}
 	}
 
-	ref := metav1.OwnerReference{
-		APIVersion:         gvk.GroupVersion().String(),
-		Kind:               gvk.Kind,
-		Name:               CSV.GetName(),
-		UID:                CSV.GetUID(),
-		BlockOwnerDeletion: pointer.BoolPtr(false),
-		Controller:         pointer.BoolPtr(false),
+	return meterDefinitions, nil
+}
+
+// buildMeterDefinition builds the MeterDefinition from the annotation of the CSV.
+// Annotations without a v1beta1 apiVersion are read as v1alpha1 and converted.
+func buildMeterDefinition(meterDefinitionString string, CSV *olmv1alpha1.ClusterServiceVersion) (*marketplacev1beta1.MeterDefinition, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal([]byte(meterDefinitionString), &typeMeta); err != nil {
+		return nil, err
 	}
 
-	meterDefinition.ObjectMeta.OwnerReferences = append(meterDefinition.ObjectMeta.OwnerReferences, ref)
+	meterDefinition := &marketplacev1beta1.MeterDefinition{}
 
-	if err != nil {
-		reqLogger.Error(err, "Failed to create.", "obj", meterDefinition)
-		return reconcile.Result{}, true, err
+	if typeMeta.APIVersion == marketplacev1beta1.SchemeGroupVersion.String() {
+		return meterDefinition.BuildMeterDefinitionFromString(
+			meterDefinitionString,
+			CSV.GetName(),
+			CSV.GetNamespace(),
+			utils.CSV_ANNOTATION_NAME,
+			utils.CSV_ANNOTATION_NAMESPACE)
 	}
 
-	err = r.client.Create(context.TODO(), meterDefinition)
+	oldMeterDefinition := &marketplacev1alpha1.MeterDefinition{}
+	_, err := oldMeterDefinition.BuildMeterDefinitionFromString(
+		meterDefinitionString,
+		CSV.GetName(),
+		CSV.GetNamespace(),
+		utils.CSV_ANNOTATION_NAME,
+		utils.CSV_ANNOTATION_NAMESPACE)
 	if err != nil {
-		reqLogger.Error(err, "Could not create MeterDefinition")
-		return reconcile.Result{Requeue: true}, true, err
+		return nil, err
 	}
 
-	return reconcile.Result{}, true, nil
+	if err := oldMeterDefinition.ConvertTo(meterDefinition); err != nil {
+		return nil, err
+	}
 
+	return meterDefinition, nil
 }
@@ -15,6 +15,7 @@
 package clusterserviceversion
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gotidy/ptr"
@@ -22,7 +23,9 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	utils "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -209,3 +212,285 @@ func TestBuildMeterDefinitionFromString(t *testing.T) {
 		t.Errorf("Failed to build MeterDefinition CR: %v", err)
 	}
 }
+
+func TestBuildMeterDefinitionFromAnnotation(t *testing.T) {
+	csv := &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      csvName,
+			Namespace: namespace,
+		},
+	}
+
+	alphaMeter := &marketplacev1alpha1.MeterDefinition{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: marketplacev1alpha1.SchemeGroupVersion.String(),
+			Kind:       "MeterDefinition",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: "example-meterdefinition",
+		},
+		Spec: marketplacev1alpha1.MeterDefinitionSpec{
+			Group:   "partner.metering.com",
+			Version: ptr.String("v1alpha"),
+			Kind:    "App",
+		},
+	}
+
+	alphaStr, _ := json.Marshal(alphaMeter)
+	meter, err := buildMeterDefinition(string(alphaStr), csv)
+	assert.NoError(t, err)
+	assert.Equal(t, "partner.metering.com", meter.Spec.Group)
+	assert.Equal(t, namespace, meter.Namespace)
+	assert.Equal(t, csvName, meter.Spec.InstalledBy.Name)
+	assert.Contains(t, meter.Annotations, marketplacev1alpha1.MeterDefinitionDeprecatedFieldsAnnotation)
+
+	betaMeter := &marketplacev1beta1.MeterDefinition{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: marketplacev1beta1.SchemeGroupVersion.String(),
+			Kind:       "MeterDefinition",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: "example-meterdefinition",
+		},
+		Spec: marketplacev1beta1.MeterDefinitionSpec{
+			Group: "partner.metering.com",
+			Kind:  "App",
+		},
+	}
+
+	betaStr, _ := json.Marshal(betaMeter)
+	meter, err = buildMeterDefinition(string(betaStr), csv)
+	assert.NoError(t, err)
+	assert.Equal(t, "App", meter.Spec.Kind)
+	assert.Equal(t, csvName, meter.Spec.InstalledBy.Name)
+	assert.Equal(t, csvName, meter.Annotations[utils.CSV_ANNOTATION_NAME])
+}
+
+func newAnnotationMeterDefinition(name, kind string) *marketplacev1beta1.MeterDefinition {
+	return &marketplacev1beta1.MeterDefinition{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: marketplacev1beta1.SchemeGroupVersion.String(),
+			Kind:       "MeterDefinition",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: name,
+		},
+		Spec: marketplacev1beta1.MeterDefinitionSpec{
+			Group: "partner.metering.com",
+			Kind:  kind,
+		},
+	}
+}
+
+func TestBuildMeterDefinitions(t *testing.T) {
+	csv := &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      csvName,
+			Namespace: namespace,
+		},
+	}
+
+	single, _ := json.Marshal(newAnnotationMeterDefinition("app", "App"))
+	list, _ := json.Marshal([]*marketplacev1beta1.MeterDefinition{
+		newAnnotationMeterDefinition("database", "Database"),
+		newAnnotationMeterDefinition("cache", "Cache"),
+	})
+
+	meterDefinitions, err := buildMeterDefinitions(map[string]string{
+		utils.CSV_METERDEFINITION_ANNOTATION:  string(single),
+		utils.CSV_METERDEFINITIONS_ANNOTATION: string(list),
+	}, csv)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, meterDefinition := range meterDefinitions {
+		names = append(names, meterDefinition.Name)
+		assert.Equal(t, namespace, meterDefinition.Namespace)
+		assert.Equal(t, csvName, meterDefinition.Spec.InstalledBy.Name)
+	}
+	assert.Equal(t, []string{"app", "database", "cache"}, names)
+
+	meterDefinitions, err = buildMeterDefinitions(map[string]string{
+		utils.CSV_METERDEFINITION_ANNOTATION: string(list),
+	}, csv)
+	assert.NoError(t, err)
+	assert.Len(t, meterDefinitions, 2)
+
+	duplicate, _ := json.Marshal(newAnnotationMeterDefinition("cache", "App"))
+	_, err = buildMeterDefinitions(map[string]string{
+		utils.CSV_METERDEFINITION_ANNOTATION:  string(duplicate),
+		utils.CSV_METERDEFINITIONS_ANNOTATION: string(list),
+	}, csv)
+	assert.Error(t, err)
+}
+
+func TestReconcileMeterDefAnnotation(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, olmv1alpha1.AddToScheme(testScheme))
+	assert.NoError(t, marketplacev1beta1.AddToScheme(testScheme))
+
+	list, _ := json.Marshal([]*marketplacev1beta1.MeterDefinition{
+		newAnnotationMeterDefinition("database", "Database"),
+		newAnnotationMeterDefinition("cache", "Cache"),
+	})
+
+	csv := &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      csvName,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				utils.CSV_METERDEFINITIONS_ANNOTATION: string(list),
+			},
+		},
+	}
+
+	// cache is out of date and app was removed from the annotation
+	installedBy := &common.NamespacedNameReference{Name: csvName, Namespace: namespace}
+	staleCache := newAnnotationMeterDefinition("cache", "App")
+	staleCache.Namespace = namespace
+	staleCache.Spec.InstalledBy = installedBy
+	removedApp := newAnnotationMeterDefinition("app", "App")
+	removedApp.Namespace = namespace
+	removedApp.Spec.InstalledBy = installedBy
+	other := newAnnotationMeterDefinition("other", "App")
+	other.Namespace = namespace
+
+	r := &ReconcileClusterServiceVersion{
+		client: fake.NewFakeClientWithScheme(testScheme, csv, staleCache, removedApp, other),
+		scheme: testScheme,
+	}
+
+	_, isRequeue, err := r.reconcileMeterDefAnnotation(csv, csv.GetAnnotations())
+	assert.NoError(t, err)
+	assert.True(t, isRequeue)
+	assert.Equal(t, "true", csv.GetAnnotations()[trackMeterTag])
+	assert.NotContains(t, csv.GetFinalizers(), utils.CSV_FINALIZER, "uninstall is not blocked on a finalizer")
+
+	meterDefinitions := &marketplacev1beta1.MeterDefinitionList{}
+	assert.NoError(t, r.client.List(context.TODO(), meterDefinitions, client.InNamespace(namespace)))
+
+	kinds := map[string]string{}
+	for _, meterDefinition := range meterDefinitions.Items {
+		kinds[meterDefinition.Name] = meterDefinition.Spec.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"database": "Database",
+		"cache":    "Cache",
+		"other":    "App",
+	}, kinds)
+
+	_, isRequeue, err = r.reconcileMeterDefAnnotation(csv, csv.GetAnnotations())
+	assert.NoError(t, err)
+	assert.False(t, isRequeue)
+
+	assert.NoError(t, r.deleteExternalResources(csv))
+	assert.NoError(t, r.client.List(context.TODO(), meterDefinitions, client.InNamespace(namespace)))
+	assert.Len(t, meterDefinitions.Items, 1)
+}
+
+func newDeletedCSV(list []byte) *olmv1alpha1.ClusterServiceVersion {
+	now := v1.Now()
+
+	return &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: v1.ObjectMeta{
+			Name:              csvName,
+			Namespace:         namespace,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{utils.CSV_FINALIZER},
+			Annotations: map[string]string{
+				utils.CSV_METERDEFINITIONS_ANNOTATION: string(list),
+			},
+		},
+	}
+}
+
+func TestReconcileDeletedCSV(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, olmv1alpha1.AddToScheme(testScheme))
+	assert.NoError(t, marketplacev1beta1.AddToScheme(testScheme))
+
+	list, _ := json.Marshal([]*marketplacev1beta1.MeterDefinition{
+		newAnnotationMeterDefinition("database", "Database"),
+		newAnnotationMeterDefinition("cache", "Cache"),
+	})
+
+	installed := newAnnotationMeterDefinition("database", "Database")
+	installed.Namespace = namespace
+	installed.Spec.InstalledBy = &common.NamespacedNameReference{Name: csvName, Namespace: namespace}
+	other := newAnnotationMeterDefinition("other", "App")
+	other.Namespace = namespace
+
+	r := &ReconcileClusterServiceVersion{
+		client: fake.NewFakeClientWithScheme(testScheme, newDeletedCSV(list), installed, other),
+		scheme: testScheme,
+	}
+
+	result, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	csv := &olmv1alpha1.ClusterServiceVersion{}
+	assert.NoError(t, r.client.Get(context.TODO(), req.NamespacedName, csv))
+	assert.NotContains(t, csv.GetFinalizers(), utils.CSV_FINALIZER)
+	assert.NotContains(t, csv.GetAnnotations(), trackMeterTag, "the deleted csv is not tracked")
+
+	// the installed meterdefinition is deleted and none are created
+	meterDefinitions := &marketplacev1beta1.MeterDefinitionList{}
+	assert.NoError(t, r.client.List(context.TODO(), meterDefinitions, client.InNamespace(namespace)))
+	assert.Len(t, meterDefinitions.Items, 1)
+	assert.Equal(t, "other", meterDefinitions.Items[0].Name)
+}
+
+func TestReconcileDeletedCSVFinalizeFailure(t *testing.T) {
+	// meterdefinitions can't be listed without their types
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, olmv1alpha1.AddToScheme(testScheme))
+
+	list, _ := json.Marshal([]*marketplacev1beta1.MeterDefinition{
+		newAnnotationMeterDefinition("database", "Database"),
+	})
+
+	r := &ReconcileClusterServiceVersion{
+		client: fake.NewFakeClientWithScheme(testScheme, newDeletedCSV(list)),
+		scheme: testScheme,
+	}
+
+	_, err := r.Reconcile(req)
+	assert.Error(t, err)
+
+	csv := &olmv1alpha1.ClusterServiceVersion{}
+	assert.NoError(t, r.client.Get(context.TODO(), req.NamespacedName, csv))
+	assert.Contains(t, csv.GetFinalizers(), utils.CSV_FINALIZER, "the finalizer is kept until the meterdefinitions are deleted")
+	assert.NotContains(t, csv.GetAnnotations(), trackMeterTag, "the deleted csv is not tracked")
+}
+
+func TestReconcileDeletedCSVWithoutFinalizer(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, olmv1alpha1.AddToScheme(testScheme))
+	assert.NoError(t, marketplacev1beta1.AddToScheme(testScheme))
+
+	list, _ := json.Marshal([]*marketplacev1beta1.MeterDefinition{
+		newAnnotationMeterDefinition("database", "Database"),
+	})
+
+	deleted := newDeletedCSV(list)
+	deleted.Finalizers = []string{"other.finalizer"}
+
+	r := &ReconcileClusterServiceVersion{
+		client: fake.NewFakeClientWithScheme(testScheme, deleted),
+		scheme: testScheme,
+	}
+
+	result, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	csv := &olmv1alpha1.ClusterServiceVersion{}
+	assert.NoError(t, r.client.Get(context.TODO(), req.NamespacedName, csv))
+	assert.Equal(t, []string{"other.finalizer"}, csv.GetFinalizers())
+	assert.NotContains(t, csv.GetAnnotations(), trackMeterTag)
+
+	meterDefinitions := &marketplacev1beta1.MeterDefinitionList{}
+	assert.NoError(t, r.client.List(context.TODO(), meterDefinitions, client.InNamespace(namespace)))
+	assert.Empty(t, meterDefinitions.Items)
+}
@@ -56,8 +56,11 @@ var ControllerSet = wire.NewSet(
 	ProvideMeterbaseController,
 	ProvideRazeeDeployController,
 	ProvideMeterDefinitionController,
+	ProvideMeterDefinitionWebhook,
 	ProvideOlmSubscriptionController,
 	ProvideMeterReportController,
+	ProvideMeterReportSummaryController,
+	ProvideMeterDefinitionBindingController,
 	ProvideControllerList,
 	ProvideNodeController,
 	ProvideOlmClusterServiceVersionController,
@@ -68,9 +71,12 @@ func ProvideControllerList(
 	myController *MarketplaceController,
 	meterbaseC *MeterbaseController,
 	meterDefinitionC *MeterDefinitionController,
+	meterDefinitionWebhook *MeterDefinitionWebhook,
 	razeeC *RazeeDeployController,
 	olmSubscriptionC *OlmSubscriptionController,
 	meterReport *MeterReportController,
+	meterReportSummary *MeterReportSummaryController,
+	meterDefinitionBinding *MeterDefinitionBindingController,
 	olmClusterServiceVersionC *OlmClusterServiceVersionController,
 	remoteResourceS3C *RemoteResourceS3Controller,
 	nodeC *NodeController,
@@ -79,9 +85,12 @@ func ProvideControllerList(
 		myController,
 		meterbaseC,
 		meterDefinitionC,
+		meterDefinitionWebhook,
 		razeeC,
 		olmSubscriptionC,
 		meterReport,
+		meterReportSummary,
+		meterDefinitionBinding,
 		olmClusterServiceVersionC,
 		remoteResourceS3C,
 		nodeC,
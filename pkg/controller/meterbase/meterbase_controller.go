@@ -40,6 +40,7 @@ import (
 	"github.com/spf13/pflag"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -292,7 +293,7 @@ func (r *ReconcileMeterBase) Reconcile(request reconcile.Request) (reconcile.Res
 	prometheus := &monitoringv1.Prometheus{}
 	if result, _ := cc.Do(context.TODO(),
 		Do(r.reconcilePrometheusOperator(instance, factory)...),
-		Do(r.installMetricStateDeployment(instance, factory)...),
+		Do(r.installMetricState(instance, factory)...),
 		Do(r.reconcileAdditionalConfigSecret(cc, instance, prometheus, factory, cfg)...),
 		Do(r.reconcilePrometheus(instance, prometheus, factory, cfg)...),
 	); !result.Is(Continue) {
@@ -382,29 +383,56 @@ func (r *ReconcileMeterBase) Reconcile(request reconcile.Request) (reconcile.Res
 		HandleResult(
 			ListAction(meterReportList, client.InNamespace(request.Namespace)),
 			OnContinue(Call(func() (ClientAction, error) {
-				loc := time.UTC
-				dateRangeInDays := -30
+				schedule, err := newReportSchedule(instance)
+				if err != nil {
+					return nil, err
+				}
 
-				meterReportNames := r.sortMeterReports(meterReportList)
+				now := time.Now()
+				meterReports := r.sortMeterReports(meterReportList)
 
 				// prune old reports
-				meterReportNames, err := r.removeOldReports(meterReportNames, loc, dateRangeInDays, request)
+				meterReports, err = r.removeOldReports(meterReports, schedule, now, request)
 				if err != nil {
 					reqLogger.Error(err, err.Error())
 				}
 
 				// fill in gaps of missing reports
-				// we want the min date to be install date - 1 day
-				endDate := time.Now().In(loc)
+				// we want the min date to be the install date or the configured start date
+				minDate := now
+				if schedule.startDate != nil {
+					minDate = *schedule.startDate
+				}
 
-				minDate := instance.ObjectMeta.CreationTimestamp.Time.In(loc)
-				minDate = utils.TruncateTime(minDate, loc)
+				expectedStartDates := r.generateExpectedDates(now, schedule, minDate)
 
-				expectedCreatedDates := r.generateExpectedDates(endDate, loc, dateRangeInDays, minDate)
-				foundCreatedDates := r.generateFoundCreatedDates(meterReportNames)
+				log.Info("report dates", "expected", len(expectedStartDates), "found", len(meterReports), "min", minDate, "period", schedule.period, "timezone", schedule.loc)
+				err = r.createReportIfNotFound(expectedStartDates, meterReports, schedule, request, instance)
+				if err != nil {
+					return nil, err
+				}
 
-				log.Info("report dates", "expected", expectedCreatedDates, "found", foundCreatedDates, "min", minDate)
-				err = r.createReportIfNotFound(expectedCreatedDates, foundCreatedDates, request, instance)
+				err = r.createSummaryIfNotFound(now, schedule, request)
+				if err != nil {
+					return nil, err
+				}
+
+				err = r.removeOldSummaries(now, schedule, request)
+				if err != nil {
+					return nil, err
+				}
+
+				// revisions are kept for every report that may still be disputed,
+				// including the ad-hoc ones
+				var adHocReports []marketplacev1alpha1.MeterReport
+				for _, report := range meterReportList.Items {
+					if report.IsAdHoc() {
+						adHocReports = append(adHocReports, report)
+					}
+				}
+
+				limit := revisionLimit(revisionLimit(schedule.retentionLimit(now), meterReports), adHocReports)
+				err = r.removeOldRevisions(limit, now, request)
 
 				return nil, err
 			})),
@@ -427,19 +455,34 @@ func (r *ReconcileMeterBase) Reconcile(request reconcile.Request) (reconcile.Res
 
 const promServiceName = "rhm-prometheus-meterbase"
 
-func (r *ReconcileMeterBase) createReportIfNotFound(expectedCreatedDates []string, foundCreatedDates []string, request reconcile.Request, instance *marketplacev1alpha1.MeterBase) error {
+// meterReportSummaryRetention is the number of billing months before the
+// current one that MeterReportSummaries are kept for.
+const meterReportSummaryRetention = 12
+
+func (r *ReconcileMeterBase) createReportIfNotFound(expectedStartDates []time.Time, meterReports []marketplacev1alpha1.MeterReport, schedule *reportSchedule, request reconcile.Request, instance *marketplacev1alpha1.MeterBase) error {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 
-	// find the diff between the dates we expect and the dates found on the cluster and create any missing reports
-	missingReports := utils.FindDiff(expectedCreatedDates, foundCreatedDates)
-	for _, missingReportDateString := range missingReports {
-		missingReportName := r.newMeterReportNameFromString(missingReportDateString)
-		missingReportStartDate, _ := time.Parse(utils.DATE_FORMAT, missingReportDateString)
-		missingReportEndDate := missingReportStartDate.AddDate(0, 0, 1)
+	// a report is found if it covers exactly the expected period
+	found := make(map[string]bool)
+	for _, report := range meterReports {
+		found[reportPeriodKey(report.Spec.StartTime.Time, report.Spec.EndTime.Time)] = true
+	}
+
+	for _, missingReportStartDate := range expectedStartDates {
+		missingReportEndDate := schedule.nextPeriod(missingReportStartDate)
+
+		if found[reportPeriodKey(missingReportStartDate, missingReportEndDate)] {
+			continue
+		}
 
+		missingReportName := schedule.reportName(missingReportStartDate)
 		missingMeterReport := r.newMeterReport(request.Namespace, missingReportStartDate, missingReportEndDate, missingReportName, instance, promServiceName)
 		err := r.client.Create(context.TODO(), missingMeterReport)
 		if err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				reqLogger.Info("Report already exists for a different period", "Resource", missingReportName)
+				continue
+			}
 			return err
 		}
 		reqLogger.Info("Created Missing Report", "Resource", missingReportName)
@@ -448,28 +491,131 @@ func (r *ReconcileMeterBase) createReportIfNotFound(expectedCreatedDates []strin
 	return nil
 }
 
-func (r *ReconcileMeterBase) removeOldReports(meterReportNames []string, loc *time.Location, dateRange int, request reconcile.Request) ([]string, error) {
+// createSummaryIfNotFound creates the MeterReportSummary of the current
+// billing month, and of the previous month if reporting started before the
+// current month.
+func (r *ReconcileMeterBase) createSummaryIfNotFound(now time.Time, schedule *reportSchedule, request reconcile.Request) error {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
-	limit := utils.TruncateTime(time.Now(), loc).AddDate(0, 0, dateRange)
-	for _, reportName := range meterReportNames {
-		dateCreated, _ := r.retrieveCreatedDate(reportName)
-		if dateCreated.Before(limit) {
-			reqLogger.Info("Deleting Report", "Resource", reportName)
-			meterReportNames = utils.RemoveKey(meterReportNames, reportName)
-			deleteReport := &marketplacev1alpha1.MeterReport{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      reportName,
-					Namespace: request.Namespace,
-				},
+
+	local := now.In(schedule.loc)
+	current := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, schedule.loc)
+	months := []time.Time{current}
+
+	if schedule.startDate != nil && schedule.startDate.Before(current) {
+		months = append([]time.Time{current.AddDate(0, -1, 0)}, months...)
+	}
+
+	for _, month := range months {
+		summary := newMeterReportSummary(request.Namespace, month, schedule)
+		err := r.client.Create(context.TODO(), summary)
+		if err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				continue
 			}
-			err := r.client.Delete(context.TODO(), deleteReport)
-			if err != nil {
-				return nil, err
+			return err
+		}
+		reqLogger.Info("Created Report Summary", "Resource", summary.Name)
+	}
+
+	return nil
+}
+
+// removeOldSummaries deletes the MeterReportSummaries of billing months that
+// started more than meterReportSummaryRetention months before the current one.
+func (r *ReconcileMeterBase) removeOldSummaries(now time.Time, schedule *reportSchedule, request reconcile.Request) error {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	local := now.In(schedule.loc)
+	limit := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, schedule.loc).AddDate(0, -meterReportSummaryRetention, 0)
+
+	summaryList := &marketplacev1alpha1.MeterReportSummaryList{}
+	err := r.client.List(context.TODO(), summaryList, client.InNamespace(request.Namespace))
+	if err != nil {
+		return err
+	}
+
+	for i := range summaryList.Items {
+		summary := &summaryList.Items[i]
+		if !summary.Spec.StartTime.Time.Before(limit) {
+			continue
+		}
+
+		reqLogger.Info("Deleting Report Summary", "Resource", summary.Name)
+		err := r.client.Delete(context.TODO(), summary)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newMeterReportSummary(namespace string, month time.Time, schedule *reportSchedule) *marketplacev1alpha1.MeterReportSummary {
+	return &marketplacev1alpha1.MeterReportSummary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s%s", utils.METER_REPORT_SUMMARY_PREFIX, month.Format("2006-01")),
+			Namespace: namespace,
+		},
+		Spec: marketplacev1alpha1.MeterReportSummarySpec{
+			StartTime: metav1.NewTime(month),
+			EndTime:   metav1.NewTime(month.AddDate(0, 1, 0)),
+			Timezone:  schedule.loc.String(),
+		},
+	}
+}
+
+// removeOldReports deletes the reports that started before the retention
+// limit. Reports overlapping the billing period of a summary that is not
+// finalized yet are kept, the summary totals the reports that exist so
+// pruning them would lower its totals.
+func (r *ReconcileMeterBase) removeOldReports(meterReports []marketplacev1alpha1.MeterReport, schedule *reportSchedule, now time.Time, request reconcile.Request) ([]marketplacev1alpha1.MeterReport, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	limit := schedule.retentionLimit(now)
+
+	summaryList := &marketplacev1alpha1.MeterReportSummaryList{}
+	err := r.client.List(context.TODO(), summaryList, client.InNamespace(request.Namespace))
+	if err != nil {
+		return meterReports, err
+	}
+
+	var openSummaries []marketplacev1alpha1.MeterReportSummary
+	for _, summary := range summaryList.Items {
+		if summary.Status.FinalizedTime == nil {
+			openSummaries = append(openSummaries, summary)
+		}
+	}
+
+	inOpenSummary := func(report *marketplacev1alpha1.MeterReport) bool {
+		for _, summary := range openSummaries {
+			if report.Spec.StartTime.Time.Before(summary.Spec.EndTime.Time) &&
+				report.Spec.EndTime.Time.After(summary.Spec.StartTime.Time) {
+				return true
 			}
 		}
+		return false
+	}
+
+	var retained []marketplacev1alpha1.MeterReport
+	for i, report := range meterReports {
+		if !report.Spec.StartTime.Time.Before(limit) || inOpenSummary(&report) {
+			retained = append(retained, report)
+			continue
+		}
+
+		reqLogger.Info("Deleting Report", "Resource", report.Name)
+		deleteReport := &marketplacev1alpha1.MeterReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      report.Name,
+				Namespace: request.Namespace,
+			},
+		}
+		err := r.client.Delete(context.TODO(), deleteReport)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return append(retained, meterReports[i:]...), err
+		}
 	}
 
-	return meterReportNames, nil
+	return retained, nil
 }
 
 // configPath: /etc/config/prometheus.yml
@@ -483,59 +629,52 @@ type MeterbaseOpts struct {
 	corev1.PullPolicy
 }
 
-func (r *ReconcileMeterBase) sortMeterReports(meterReportList *marketplacev1alpha1.MeterReportList) []string {
-
-	var meterReportNames []string
+func (r *ReconcileMeterBase) sortMeterReports(meterReportList *marketplacev1alpha1.MeterReportList) []marketplacev1alpha1.MeterReport {
+	// ad-hoc reports are not part of the schedule
+	var meterReports []marketplacev1alpha1.MeterReport
 	for _, report := range meterReportList.Items {
-		meterReportNames = append(meterReportNames, report.Name)
+		if report.IsAdHoc() {
+			continue
+		}
+		meterReports = append(meterReports, report)
 	}
 
-	sort.Strings(meterReportNames)
-	return meterReportNames
-}
-
-func (r *ReconcileMeterBase) retrieveCreatedDate(reportName string) (time.Time, error) {
-	dateString := strings.SplitN(reportName, "-", 3)[2:]
-	return time.Parse(utils.DATE_FORMAT, strings.Join(dateString, ""))
-}
-
-func (r *ReconcileMeterBase) newMeterReportNameFromDate(date time.Time) string {
-	dateSuffix := strings.Join(strings.Fields(date.String())[:1], "")
-	return fmt.Sprintf("%s%s", utils.METER_REPORT_PREFIX, dateSuffix)
-}
+	sort.Slice(meterReports, func(i, j int) bool {
+		if meterReports[i].Spec.StartTime.Equal(&meterReports[j].Spec.StartTime) {
+			return meterReports[i].Name < meterReports[j].Name
+		}
+		return meterReports[i].Spec.StartTime.Before(&meterReports[j].Spec.StartTime)
+	})
 
-func (r *ReconcileMeterBase) newMeterReportNameFromString(dateString string) string {
-	dateSuffix := dateString
-	return fmt.Sprintf("%s%s", utils.METER_REPORT_PREFIX, dateSuffix)
+	return meterReports
 }
 
-func (r *ReconcileMeterBase) generateFoundCreatedDates(meterReportNames []string) []string {
-	var foundCreatedDates []string
-	for _, reportName := range meterReportNames {
-		dateString := strings.SplitN(reportName, "-", 3)[2:]
-		foundCreatedDates = append(foundCreatedDates, strings.Join(dateString, ""))
-	}
-	return foundCreatedDates
+// reportPeriodKey identifies a report period independent of the timezone
+// the times are expressed in.
+func reportPeriodKey(start, end time.Time) string {
+	return fmt.Sprintf("%d-%d", start.Unix(), end.Unix())
 }
 
-func (r *ReconcileMeterBase) generateExpectedDates(endTime time.Time, loc *time.Location, dateRange int, minDate time.Time) []string {
+// generateExpectedDates returns the start of every report period between the
+// retention limit, or minDate if later, and the period containing endTime.
+func (r *ReconcileMeterBase) generateExpectedDates(endTime time.Time, schedule *reportSchedule, minDate time.Time) []time.Time {
 	// set start date
-	startDate := utils.TruncateTime(endTime, loc).AddDate(0, 0, dateRange)
+	startDate := schedule.retentionLimit(endTime)
 
 	if minDate.After(startDate) {
-		startDate = utils.TruncateTime(minDate, loc)
+		startDate = schedule.periodStart(minDate)
 	}
 
 	// set end date
-	endDate := utils.TruncateTime(endTime, loc)
+	endDate := schedule.periodStart(endTime)
 
 	// loop through the range of dates we expect
-	var expectedCreatedDates []string
-	for d := startDate; d.After(endDate) == false; d = d.AddDate(0, 0, 1) {
-		expectedCreatedDates = append(expectedCreatedDates, d.Format(utils.DATE_FORMAT))
+	var expectedStartDates []time.Time
+	for d := startDate; !d.After(endDate); d = schedule.nextPeriod(d) {
+		expectedStartDates = append(expectedStartDates, d)
 	}
 
-	return expectedCreatedDates
+	return expectedStartDates
 }
 
 func (r *ReconcileMeterBase) newMeterReport(namespace string, startTime time.Time, endTime time.Time, meterReportName string, instance *marketplacev1alpha1.MeterBase, prometheusServiceName string) *marketplacev1alpha1.MeterReport {
@@ -547,6 +686,7 @@ func (r *ReconcileMeterBase) newMeterReport(namespace string, startTime time.Tim
 		Spec: marketplacev1alpha1.MeterReportSpec{
 			StartTime: metav1.NewTime(startTime),
 			EndTime:   metav1.NewTime(endTime),
+			Type:      marketplacev1alpha1.MeterReportTypeScheduled,
 			PrometheusService: &common.ServiceReference{
 				Name:       prometheusServiceName,
 				Namespace:  instance.Namespace,
@@ -646,24 +786,40 @@ func (r *ReconcileMeterBase) reconcilePrometheusOperator(
 	}
 }
 
-func (r *ReconcileMeterBase) installMetricStateDeployment(
+// metricStateShards returns the number of shards of the metric state.
+func metricStateShards(instance *marketplacev1alpha1.MeterBase) int32 {
+	if instance.Spec.MetricState != nil && instance.Spec.MetricState.Shards != nil {
+		return *instance.Spec.MetricState.Shards
+	}
+
+	return 1
+}
+
+func (r *ReconcileMeterBase) installMetricState(
 	instance *marketplacev1alpha1.MeterBase,
 	factory *manifests.Factory,
 ) []ClientAction {
-	deployment := &appsv1.Deployment{}
+	statefulSet := &appsv1.StatefulSet{}
 	service := &corev1.Service{}
 	serviceMonitor := &monitoringv1.ServiceMonitor{}
 
+	// the metric state used to be a deployment with the same name
+	legacyDeployment := &appsv1.Deployment{}
+	legacy, _ := factory.MetricStateStatefulSet(1)
+
 	args := manifests.CreateOrUpdateFactoryItemArgs{
 		Owner:   instance,
 		Patcher: r.patcher,
 	}
 
 	return []ClientAction{
+		HandleResult(
+			GetAction(types.NamespacedName{Namespace: legacy.Namespace, Name: legacy.Name}, legacyDeployment),
+			OnContinue(DeleteAction(legacyDeployment))),
 		manifests.CreateOrUpdateFactoryItemAction(
-			deployment,
+			statefulSet,
 			func() (runtime.Object, error) {
-				return factory.MetricStateDeployment()
+				return factory.MetricStateStatefulSet(metricStateShards(instance))
 			},
 			args,
 		),
@@ -963,9 +1119,10 @@ func (r *ReconcileMeterBase) uninstallMetricState(
 	instance *marketplacev1alpha1.MeterBase,
 	factory *manifests.Factory,
 ) []ClientAction {
-	deployment, _ := factory.MetricStateDeployment()
+	statefulSet, _ := factory.MetricStateStatefulSet(metricStateShards(instance))
 	service, _ := factory.MetricStateService()
 	sm, _ := factory.MetricStateServiceMonitor()
+	legacyDeployment := &appsv1.Deployment{}
 
 	return []ClientAction{
 		HandleResult(
@@ -975,8 +1132,11 @@ func (r *ReconcileMeterBase) uninstallMetricState(
 			GetAction(types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, service),
 			OnContinue(DeleteAction(service))),
 		HandleResult(
-			GetAction(types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}, deployment),
-			OnContinue(DeleteAction(deployment))),
+			GetAction(types.NamespacedName{Namespace: statefulSet.Namespace, Name: statefulSet.Name}, statefulSet),
+			OnContinue(DeleteAction(statefulSet))),
+		HandleResult(
+			GetAction(types.NamespacedName{Namespace: statefulSet.Namespace, Name: statefulSet.Name}, legacyDeployment),
+			OnContinue(DeleteAction(legacyDeployment))),
 	}
 }
 
@@ -992,7 +1152,7 @@ func (r *ReconcileMeterBase) uninstallPrometheus(
 	secrets := []*corev1.Secret{secret0, secret1, secret2, secret3}
 	prom, _ := r.newPrometheusOperator(instance, factory, nil)
 	service, _ := factory.PrometheusService(instance.Name)
-	deployment, _ := factory.MetricStateDeployment()
+	statefulSet, _ := factory.MetricStateStatefulSet(metricStateShards(instance))
 	service2, _ := factory.MetricStateService()
 	sm, _ := factory.MetricStateServiceMonitor()
 
@@ -1015,14 +1175,14 @@ func (r *ReconcileMeterBase) uninstallPrometheus(
 			GetAction(types.NamespacedName{Namespace: sm.Namespace, Name: sm.Name}, sm),
 			OnContinue(DeleteAction(sm))),
 		HandleResult(
-			GetAction(types.NamespacedName{Namespace: service2.Namespace, Name: service2.Name}, deployment),
+			GetAction(types.NamespacedName{Namespace: service2.Namespace, Name: service2.Name}, service2),
 			OnContinue(DeleteAction(service2))),
 		HandleResult(
 			GetAction(types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, service),
 			OnContinue(DeleteAction(service))),
 		HandleResult(
-			GetAction(types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}, deployment),
-			OnContinue(DeleteAction(deployment))),
+			GetAction(types.NamespacedName{Namespace: statefulSet.Namespace, Name: statefulSet.Name}, statefulSet),
+			OnContinue(DeleteAction(statefulSet))),
 		HandleResult(
 			GetAction(types.NamespacedName{Namespace: prom.Namespace, Name: prom.Name}, prom),
 			OnContinue(DeleteAction(prom))),
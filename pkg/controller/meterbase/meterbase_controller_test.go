@@ -15,10 +15,22 @@
 package meterbase
 
 import (
+	"context"
+	"sort"
 	"time"
 
+	"github.com/gotidy/ptr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/manifests"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var _ = Describe("MeterbaseController", func() {
@@ -31,11 +43,15 @@ var _ = Describe("MeterbaseController", func() {
 
 	Describe("check date functions", func() {
 		var (
-			ctrl *ReconcileMeterBase
+			ctrl     *ReconcileMeterBase
+			schedule *reportSchedule
 		)
 
 		BeforeEach(func() {
+			var err error
 			ctrl = &ReconcileMeterBase{}
+			schedule, err = newReportSchedule(&marketplacev1alpha1.MeterBase{})
+			Expect(err).To(Succeed())
 		})
 
 		It("reports should calculate the correct dates to create", func() {
@@ -43,13 +59,320 @@ var _ = Describe("MeterbaseController", func() {
 			endDate = endDate.AddDate(0, 0, 0)
 			minDate := endDate.AddDate(0, 0, 0)
 
-			exp := ctrl.generateExpectedDates(endDate, time.UTC, -30, minDate)
+			exp := ctrl.generateExpectedDates(endDate, schedule, minDate)
 			Expect(exp).To(HaveLen(1))
 
 			minDate = endDate.AddDate(0, 0, -2)
 
-			exp = ctrl.generateExpectedDates(endDate, time.UTC, -30, minDate)
+			exp = ctrl.generateExpectedDates(endDate, schedule, minDate)
 			Expect(exp).To(HaveLen(3))
 		})
+
+		It("should keep the daily report name format", func() {
+			start := time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)
+			Expect(schedule.reportName(start)).To(Equal("meter-report-2020-06-15"))
+			Expect(schedule.nextPeriod(start)).To(Equal(start.AddDate(0, 0, 1)))
+		})
+
+		It("should calculate monthly periods in the billing timezone", func() {
+			schedule, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Period:         marketplacev1alpha1.ReportingPeriodMonthly,
+						Timezone:       "America/New_York",
+						RetentionCount: ptr.Int32(3),
+						StartDate:      &metav1.Time{Time: time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)},
+					},
+				},
+			})
+			Expect(err).To(Succeed())
+
+			loc, _ := time.LoadLocation("America/New_York")
+			// midnight utc on the first is still the previous month in new york
+			endDate := time.Date(2020, 7, 1, 2, 0, 0, 0, time.UTC)
+
+			exp := ctrl.generateExpectedDates(endDate, schedule, *schedule.startDate)
+			Expect(exp).To(Equal([]time.Time{
+				time.Date(2020, 4, 1, 0, 0, 0, 0, loc),
+				time.Date(2020, 5, 1, 0, 0, 0, 0, loc),
+				time.Date(2020, 6, 1, 0, 0, 0, 0, loc),
+			}))
+			Expect(schedule.reportName(exp[2])).To(Equal("meter-report-monthly-2020-06"))
+			Expect(schedule.nextPeriod(exp[2])).To(Equal(time.Date(2020, 7, 1, 0, 0, 0, 0, loc)))
+		})
+
+		It("should start weekly periods on monday", func() {
+			schedule, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Period:            marketplacev1alpha1.ReportingPeriodWeekly,
+						RetentionDuration: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+					},
+				},
+			})
+			Expect(err).To(Succeed())
+
+			// a sunday
+			endDate := time.Date(2020, 6, 21, 12, 0, 0, 0, time.UTC)
+
+			exp := ctrl.generateExpectedDates(endDate, schedule, endDate.AddDate(-1, 0, 0))
+			Expect(exp).To(Equal([]time.Time{
+				time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC),
+			}))
+			Expect(schedule.reportName(exp[0])).To(Equal("meter-report-weekly-2020-06-01"))
+		})
+
+		It("should apply the shorter retention", func() {
+			schedule, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Period:            marketplacev1alpha1.ReportingPeriodHourly,
+						RetentionCount:    ptr.Int32(48),
+						RetentionDuration: &metav1.Duration{Duration: 6 * time.Hour},
+					},
+				},
+			})
+			Expect(err).To(Succeed())
+
+			now := time.Date(2020, 6, 21, 12, 30, 0, 0, time.UTC)
+			Expect(schedule.retentionLimit(now)).To(Equal(time.Date(2020, 6, 21, 6, 0, 0, 0, time.UTC)))
+			Expect(schedule.reportName(now)).To(Equal("meter-report-hourly-2020-06-21-12"))
+		})
+
+		It("should name the repeated hour of a daylight saving fall-back", func() {
+			schedule, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Period:   marketplacev1alpha1.ReportingPeriodHourly,
+						Timezone: "America/New_York",
+					},
+				},
+			})
+			Expect(err).To(Succeed())
+
+			// 01:30 EDT and 01:30 EST on 2020-11-01
+			first := schedule.periodStart(time.Date(2020, 11, 1, 5, 30, 0, 0, time.UTC))
+			second := schedule.periodStart(time.Date(2020, 11, 1, 6, 30, 0, 0, time.UTC))
+
+			Expect(first.Equal(time.Date(2020, 11, 1, 5, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(second.Equal(time.Date(2020, 11, 1, 6, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(schedule.nextPeriod(first).Equal(second)).To(BeTrue())
+			Expect(schedule.reportName(first)).To(Equal("meter-report-hourly-2020-11-01-05"))
+			Expect(schedule.reportName(second)).To(Equal("meter-report-hourly-2020-11-01-06"))
+		})
+
+		It("should ignore ad-hoc reports", func() {
+			day := time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC)
+			list := &marketplacev1alpha1.MeterReportList{
+				Items: []marketplacev1alpha1.MeterReport{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "meter-report-2020-06-22"},
+						Spec: marketplacev1alpha1.MeterReportSpec{
+							StartTime: metav1.NewTime(day.AddDate(0, 0, 1)),
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "adhoc"},
+						Spec: marketplacev1alpha1.MeterReportSpec{
+							StartTime: metav1.NewTime(day.AddDate(-1, 0, 0)),
+							Type:      marketplacev1alpha1.MeterReportTypeAdHoc,
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "meter-report-2020-06-21"},
+						Spec: marketplacev1alpha1.MeterReportSpec{
+							StartTime: metav1.NewTime(day),
+						},
+					},
+				},
+			}
+
+			reports := ctrl.sortMeterReports(list)
+			Expect(reports).To(HaveLen(2))
+			Expect(reports[0].Name).To(Equal("meter-report-2020-06-21"))
+			Expect(reports[1].Name).To(Equal("meter-report-2020-06-22"))
+		})
+
+		It("should reject an unknown timezone", func() {
+			_, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Timezone: "Not/AZone",
+					},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should name summaries by billing month", func() {
+			schedule, err := newReportSchedule(&marketplacev1alpha1.MeterBase{
+				Spec: marketplacev1alpha1.MeterBaseSpec{
+					Reporting: &marketplacev1alpha1.ReportingSpec{
+						Timezone: "America/New_York",
+					},
+				},
+			})
+			Expect(err).To(Succeed())
+
+			month := time.Date(2020, time.December, 1, 0, 0, 0, 0, schedule.loc)
+			summary := newMeterReportSummary("ns", month, schedule)
+			Expect(summary.Name).To(Equal("meter-report-summary-2020-12"))
+			Expect(summary.Spec.EndTime.Time).To(Equal(time.Date(2021, time.January, 1, 0, 0, 0, 0, schedule.loc)))
+			Expect(summary.Spec.Timezone).To(Equal("America/New_York"))
+		})
+
+		It("should remove summaries past the retention", func() {
+			now := time.Date(2021, time.June, 15, 0, 0, 0, 0, time.UTC)
+			kept := newMeterReportSummary("ns", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC), schedule)
+			removed := newMeterReportSummary("ns", time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC), schedule)
+
+			s := runtime.NewScheme()
+			Expect(marketplacev1alpha1.AddToScheme(s)).To(Succeed())
+			ctrl.client = fake.NewFakeClientWithScheme(s, kept, removed)
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "rhm-marketplaceconfig-meterbase", Namespace: "ns"}}
+			Expect(ctrl.removeOldSummaries(now, schedule, request)).To(Succeed())
+
+			summaries := &marketplacev1alpha1.MeterReportSummaryList{}
+			Expect(ctrl.client.List(context.TODO(), summaries)).To(Succeed())
+			Expect(summaries.Items).To(HaveLen(1))
+			Expect(summaries.Items[0].Name).To(Equal("meter-report-summary-2020-06"))
+		})
+
+		It("should keep the reports of summaries that are not finalized", func() {
+			now := time.Date(2020, time.August, 2, 12, 0, 0, 0, time.UTC)
+
+			newReport := func(day time.Time) *marketplacev1alpha1.MeterReport {
+				return &marketplacev1alpha1.MeterReport{
+					ObjectMeta: metav1.ObjectMeta{Name: schedule.reportName(day), Namespace: "ns"},
+					Spec: marketplacev1alpha1.MeterReportSpec{
+						StartTime: metav1.NewTime(day),
+						EndTime:   metav1.NewTime(day.AddDate(0, 0, 1)),
+					},
+				}
+			}
+
+			finalizedTime := metav1.NewTime(time.Date(2020, time.July, 3, 0, 0, 0, 0, time.UTC))
+			june := newMeterReportSummary("ns", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC), schedule)
+			june.Status.FinalizedTime = &finalizedTime
+			july := newMeterReportSummary("ns", time.Date(2020, time.July, 1, 0, 0, 0, 0, time.UTC), schedule)
+
+			objs := []runtime.Object{june, july}
+			list := &marketplacev1alpha1.MeterReportList{}
+			for day := time.Date(2020, time.June, 29, 0, 0, 0, 0, time.UTC); day.Before(now); day = day.AddDate(0, 0, 1) {
+				report := newReport(day)
+				objs = append(objs, report)
+				list.Items = append(list.Items, *report)
+			}
+
+			s := runtime.NewScheme()
+			Expect(marketplacev1alpha1.AddToScheme(s)).To(Succeed())
+			ctrl.client = fake.NewFakeClientWithScheme(s, objs...)
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "rhm-marketplaceconfig-meterbase", Namespace: "ns"}}
+			retained, err := ctrl.removeOldReports(ctrl.sortMeterReports(list), schedule, now, request)
+			Expect(err).To(Succeed())
+
+			// the reports of the finalized june summary are past the
+			// retention, the july summary is still open
+			reports := &marketplacev1alpha1.MeterReportList{}
+			Expect(ctrl.client.List(context.TODO(), reports)).To(Succeed())
+			Expect(reports.Items).To(HaveLen(len(retained)))
+			Expect(retained[0].Name).To(Equal("meter-report-2020-07-01"))
+			Expect(retained).To(HaveLen(33))
+
+			// once july is finalized its reports are pruned too
+			Expect(ctrl.client.Get(context.TODO(), types.NamespacedName{Name: july.Name, Namespace: "ns"}, july)).To(Succeed())
+			july.Status.FinalizedTime = &finalizedTime
+			Expect(ctrl.client.Update(context.TODO(), july)).To(Succeed())
+
+			retained, err = ctrl.removeOldReports(retained, schedule, now, request)
+			Expect(err).To(Succeed())
+			Expect(retained[0].Spec.StartTime.Time.Before(schedule.retentionLimit(now))).To(BeFalse())
+		})
+
+		It("should remove the revisions no kept report is metered with", func() {
+			day := func(month time.Month, day int) time.Time {
+				return time.Date(2020, month, day, 0, 0, 0, 0, time.UTC)
+			}
+
+			newRevision := func(uid types.UID, name string, revision int64, effective time.Time) *marketplacev1beta1.MeterDefinitionRevision {
+				return &marketplacev1beta1.MeterDefinitionRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: marketplacev1beta1.MeterDefinitionRevisionName(uid, revision)},
+					Spec: marketplacev1beta1.MeterDefinitionRevisionSpec{
+						MeterDefinition: common.NamespacedNameReference{UID: uid, Name: name, Namespace: "apps"},
+						Revision:        revision,
+						EffectiveTime:   metav1.NewTime(effective),
+					},
+				}
+			}
+
+			meterdef := &marketplacev1beta1.MeterDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "apps", UID: "a-uid"},
+			}
+
+			s := runtime.NewScheme()
+			Expect(marketplacev1beta1.AddToScheme(s)).To(Succeed())
+			ctrl.client = fake.NewFakeClientWithScheme(s,
+				meterdef,
+				newRevision("a-uid", "a", 1, day(time.June, 1)),
+				newRevision("a-uid", "a", 2, day(time.June, 20)),
+				newRevision("a-uid", "a", 3, day(time.July, 10)),
+				newRevision("a-uid", "a", 4, day(time.August, 1)),
+				newRevision("b-uid", "b", 1, day(time.June, 1)),
+			)
+
+			listRevisions := func() []string {
+				revisions := &marketplacev1beta1.MeterDefinitionRevisionList{}
+				Expect(ctrl.client.List(context.TODO(), revisions)).To(Succeed())
+
+				names := []string{}
+				for _, revision := range revisions.Items {
+					names = append(names, revision.Name)
+				}
+				sort.Strings(names)
+				return names
+			}
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "rhm-marketplaceconfig-meterbase", Namespace: "ns"}}
+
+			// a report from july 15 on may still be disputed, the revisions
+			// replaced before then are not needed
+			Expect(ctrl.removeOldRevisions(day(time.July, 15), day(time.August, 2), request)).To(Succeed())
+			Expect(listRevisions()).To(Equal([]string{"a-uid-3", "a-uid-4", "b-uid-1"}))
+
+			// the meter definition of b is gone, its revisions are kept until
+			// the reports of the time it was deleted are past the retention
+			deleted := &marketplacev1beta1.MeterDefinitionRevision{}
+			Expect(ctrl.client.Get(context.TODO(), types.NamespacedName{Name: "b-uid-1"}, deleted)).To(Succeed())
+			Expect(deleted.Annotations).To(HaveKeyWithValue(marketplacev1beta1.MeterDefinitionRevisionDeletedAnnotation, "2020-08-02T00:00:00Z"))
+
+			Expect(ctrl.removeOldRevisions(day(time.August, 1), day(time.August, 20), request)).To(Succeed())
+			Expect(listRevisions()).To(Equal([]string{"a-uid-3", "a-uid-4", "b-uid-1"}))
+
+			Expect(ctrl.removeOldRevisions(day(time.August, 3), day(time.August, 21), request)).To(Succeed())
+			Expect(listRevisions()).To(Equal([]string{"a-uid-4"}))
+		})
+	})
+
+	Describe("metric state", func() {
+		It("should run a replica for each shard", func() {
+			base := &marketplacev1alpha1.MeterBase{}
+			Expect(metricStateShards(base)).To(Equal(int32(1)))
+
+			base.Spec.MetricState = &marketplacev1alpha1.MetricStateSpec{Shards: ptr.Int32(3)}
+			Expect(metricStateShards(base)).To(Equal(int32(3)))
+
+			factory := manifests.NewFactory("ns", &manifests.Config{})
+			statefulSet, err := factory.MetricStateStatefulSet(metricStateShards(base))
+			Expect(err).To(Succeed())
+			Expect(statefulSet.Namespace).To(Equal("ns"))
+			Expect(*statefulSet.Spec.Replicas).To(Equal(int32(3)))
+			Expect(statefulSet.Spec.Template.Annotations).To(HaveKeyWithValue(manifests.MetricStateShardsAnnotation, "3"))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--pod=$(POD_NAME)"))
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Path).To(Equal("/readyz"))
+		})
 	})
 })
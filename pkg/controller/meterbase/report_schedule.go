@@ -0,0 +1,195 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterbase
+
+import (
+	"fmt"
+	"time"
+
+	merrors "emperror.dev/errors"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
+)
+
+const defaultRetentionDuration = 30 * 24 * time.Hour
+
+// reportSchedule is the resolved form of a MeterBase ReportingSpec. It
+// calculates the periods MeterReports are expected for.
+type reportSchedule struct {
+	period            marketplacev1alpha1.ReportingPeriod
+	loc               *time.Location
+	retentionCount    *int32
+	retentionDuration *time.Duration
+	startDate         *time.Time
+}
+
+// newReportSchedule resolves the reporting spec of the meterbase, applying
+// the defaults of daily reports in UTC kept for 30 days.
+func newReportSchedule(instance *marketplacev1alpha1.MeterBase) (*reportSchedule, error) {
+	schedule := &reportSchedule{
+		period: marketplacev1alpha1.ReportingPeriodDaily,
+		loc:    time.UTC,
+	}
+
+	spec := instance.Spec.Reporting
+	if spec == nil {
+		spec = &marketplacev1alpha1.ReportingSpec{}
+	}
+
+	if spec.Period != "" {
+		switch spec.Period {
+		case marketplacev1alpha1.ReportingPeriodHourly,
+			marketplacev1alpha1.ReportingPeriodDaily,
+			marketplacev1alpha1.ReportingPeriodWeekly,
+			marketplacev1alpha1.ReportingPeriodMonthly:
+			schedule.period = spec.Period
+		default:
+			return nil, merrors.Errorf("unsupported reporting period %q", spec.Period)
+		}
+	}
+
+	if spec.Timezone != "" {
+		loc, err := time.LoadLocation(spec.Timezone)
+		if err != nil {
+			return nil, merrors.Wrapf(err, "failed to load reporting timezone %q", spec.Timezone)
+		}
+		schedule.loc = loc
+	}
+
+	if spec.RetentionCount != nil {
+		if *spec.RetentionCount < 1 {
+			return nil, merrors.Errorf("reporting retentionCount must be at least 1, got %d", *spec.RetentionCount)
+		}
+		count := *spec.RetentionCount
+		schedule.retentionCount = &count
+	}
+
+	if spec.RetentionDuration != nil {
+		duration := spec.RetentionDuration.Duration
+		schedule.retentionDuration = &duration
+	}
+
+	if schedule.retentionCount == nil && schedule.retentionDuration == nil {
+		duration := defaultRetentionDuration
+		schedule.retentionDuration = &duration
+	}
+
+	startDate := instance.ObjectMeta.CreationTimestamp.Time
+	if spec.StartDate != nil {
+		startDate = spec.StartDate.Time
+	}
+	if !startDate.IsZero() {
+		startDate = startDate.In(schedule.loc)
+		schedule.startDate = &startDate
+	}
+
+	return schedule, nil
+}
+
+// periodStart returns the start of the report period containing t.
+func (s *reportSchedule) periodStart(t time.Time) time.Time {
+	t = t.In(s.loc)
+
+	switch s.period {
+	case marketplacev1alpha1.ReportingPeriodHourly:
+		// subtract the elapsed part of the local hour so the repeated hour
+		// of a daylight saving fall-back keeps its own start
+		elapsed := time.Duration(t.Minute())*time.Minute +
+			time.Duration(t.Second())*time.Second +
+			time.Duration(t.Nanosecond())
+		return t.Add(-elapsed)
+	case marketplacev1alpha1.ReportingPeriodWeekly:
+		day := utils.TruncateTime(t, s.loc)
+		// weeks start on monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case marketplacev1alpha1.ReportingPeriodMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.loc)
+	default:
+		return utils.TruncateTime(t, s.loc)
+	}
+}
+
+// nextPeriod returns the start of the report period following the period
+// that begins at start.
+func (s *reportSchedule) nextPeriod(start time.Time) time.Time {
+	switch s.period {
+	case marketplacev1alpha1.ReportingPeriodHourly:
+		return start.Add(time.Hour)
+	case marketplacev1alpha1.ReportingPeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case marketplacev1alpha1.ReportingPeriodMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// previousPeriod returns the start of the report period preceding the period
+// that begins at start.
+func (s *reportSchedule) previousPeriod(start time.Time) time.Time {
+	switch s.period {
+	case marketplacev1alpha1.ReportingPeriodHourly:
+		return start.Add(-time.Hour)
+	case marketplacev1alpha1.ReportingPeriodWeekly:
+		return start.AddDate(0, 0, -7)
+	case marketplacev1alpha1.ReportingPeriodMonthly:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+// retentionLimit returns the start of the oldest report period that is
+// retained at now. Reports that start before the limit are pruned.
+func (s *reportSchedule) retentionLimit(now time.Time) time.Time {
+	var limit time.Time
+
+	if s.retentionDuration != nil {
+		limit = s.periodStart(now.Add(-*s.retentionDuration))
+	}
+
+	if s.retentionCount != nil {
+		countLimit := s.periodStart(now)
+		for i := int32(1); i < *s.retentionCount; i++ {
+			countLimit = s.previousPeriod(countLimit)
+		}
+
+		if countLimit.After(limit) {
+			limit = countLimit
+		}
+	}
+
+	return limit
+}
+
+// reportName returns the name of the MeterReport for the period beginning at
+// start. Daily reports keep the original meter-report-<date> format. Hourly
+// reports are named by the UTC hour since local hours repeat at a daylight
+// saving fall-back.
+func (s *reportSchedule) reportName(start time.Time) string {
+	start = start.In(s.loc)
+
+	switch s.period {
+	case marketplacev1alpha1.ReportingPeriodHourly:
+		return fmt.Sprintf("%s%s-%s", utils.METER_REPORT_PREFIX, "hourly", start.UTC().Format("2006-01-02-15"))
+	case marketplacev1alpha1.ReportingPeriodWeekly:
+		return fmt.Sprintf("%s%s-%s", utils.METER_REPORT_PREFIX, "weekly", start.Format(utils.DATE_FORMAT))
+	case marketplacev1alpha1.ReportingPeriodMonthly:
+		return fmt.Sprintf("%s%s-%s", utils.METER_REPORT_PREFIX, "monthly", start.Format("2006-01"))
+	default:
+		return fmt.Sprintf("%s%s", utils.METER_REPORT_PREFIX, start.Format(utils.DATE_FORMAT))
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterbase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// revisionLimit returns the start of the oldest report the revisions are
+// needed for: the retention limit, or the start of an older report that is
+// kept.
+func revisionLimit(limit time.Time, meterReports []marketplacev1alpha1.MeterReport) time.Time {
+	for _, report := range meterReports {
+		if report.Spec.StartTime.Time.Before(limit) {
+			limit = report.Spec.StartTime.Time
+		}
+	}
+	return limit
+}
+
+// removeOldRevisions deletes the MeterDefinitionRevisions no report from the
+// limit on can be metered with, those whose successor became effective
+// before the limit. The revisions of a deleted meter definition are stamped
+// with the time it was found deleted and removed once that time is before
+// the limit.
+func (r *ReconcileMeterBase) removeOldRevisions(limit, now time.Time, request reconcile.Request) error {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	revisionList := &v1beta1.MeterDefinitionRevisionList{}
+	err := r.client.List(context.TODO(), revisionList)
+	if err != nil {
+		return err
+	}
+
+	revisionsByUID := map[types.UID][]*v1beta1.MeterDefinitionRevision{}
+	for i := range revisionList.Items {
+		revision := &revisionList.Items[i]
+		uid := revision.Spec.MeterDefinition.UID
+		revisionsByUID[uid] = append(revisionsByUID[uid], revision)
+	}
+
+	deleteRevision := func(revision *v1beta1.MeterDefinitionRevision) error {
+		reqLogger.Info("Deleting MeterDefinition Revision", "Resource", revision.Name)
+		err := r.client.Delete(context.TODO(), revision)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	for _, revisions := range revisionsByUID {
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+		})
+
+		latest := revisions[len(revisions)-1]
+
+		deletedTime, err := r.meterDefinitionDeletedTime(latest, now)
+		if err != nil {
+			return err
+		}
+
+		if deletedTime != nil && deletedTime.Before(limit) {
+			for _, revision := range revisions {
+				if err := deleteRevision(revision); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for i := 0; i < len(revisions)-1; i++ {
+			if !revisions[i+1].Spec.EffectiveTime.Time.Before(limit) {
+				break
+			}
+
+			if err := deleteRevision(revisions[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// meterDefinitionDeletedTime returns when the meter definition of the latest
+// revision was found deleted, or nil if it exists. The first time it is found
+// deleted the revision is annotated with now.
+func (r *ReconcileMeterBase) meterDefinitionDeletedTime(latest *v1beta1.MeterDefinitionRevision, now time.Time) (*time.Time, error) {
+	if value, ok := latest.Annotations[v1beta1.MeterDefinitionRevisionDeletedAnnotation]; ok {
+		deletedTime, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			return &deletedTime, nil
+		}
+	}
+
+	ref := latest.Spec.MeterDefinition
+	key := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+
+	var meterdef runtime.Object = &v1beta1.MeterDefinition{}
+	if ref.Namespace == "" {
+		meterdef = &v1beta1.ClusterMeterDefinition{}
+	}
+
+	err := r.client.Get(context.TODO(), key, meterdef)
+	switch {
+	case kerrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	case meterdef.(metav1.Object).GetUID() == ref.UID:
+		return nil, nil
+	}
+
+	if latest.Annotations == nil {
+		latest.Annotations = map[string]string{}
+	}
+	latest.Annotations[v1beta1.MeterDefinitionRevisionDeletedAnnotation] = now.UTC().Format(time.RFC3339)
+
+	err = r.client.Update(context.TODO(), latest)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return &now, nil
+}
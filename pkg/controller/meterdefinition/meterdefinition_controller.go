@@ -21,11 +21,12 @@ import (
 	"time"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
-	v1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/patch"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -78,7 +79,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource MeterDefinition
-	err = c.Watch(&source.Kind{Type: &v1alpha1.MeterDefinition{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &v1beta1.MeterDefinition{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ClusterMeterDefinition, its
+	// requests have no namespace
+	err = c.Watch(&source.Kind{Type: &v1beta1.ClusterMeterDefinition{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
 		return err
 	}
@@ -110,8 +118,20 @@ func (r *ReconcileMeterDefinition) Reconcile(request reconcile.Request) (reconci
 
 	cc := r.ccprovider.NewCommandRunner(r.client, r.scheme, reqLogger)
 
-	// Fetch the MeterDefinition instance
-	instance := &v1alpha1.MeterDefinition{}
+	// Fetch the MeterDefinition or ClusterMeterDefinition instance
+	var (
+		instance        runtime.Object
+		instanceStatus  *v1beta1.MeterDefinitionStatus
+		meterdef        = &v1beta1.MeterDefinition{}
+		clusterMeterdef = &v1beta1.ClusterMeterDefinition{}
+	)
+
+	if request.Namespace == "" {
+		instance, instanceStatus = clusterMeterdef, &clusterMeterdef.Status
+	} else {
+		instance, instanceStatus = meterdef, &meterdef.Status
+	}
+
 	result, _ := cc.Do(context.TODO(), GetAction(request.NamespacedName, instance))
 
 	if !result.Is(Continue) {
@@ -127,24 +147,29 @@ func (r *ReconcileMeterDefinition) Reconcile(request reconcile.Request) (reconci
 		return result.Return()
 	}
 
-	reqLogger.Info("Found instance", "instance", instance.Name)
+	reqLogger.Info("Found instance", "instance", request.Name)
 
-	var queue bool
-
-	if instance.Spec.ServiceMeterLabels != nil {
-		instance.Spec.ServiceMeterLabels = nil
+	revisionKind, excludedNamespaces := "MeterDefinition", []string(nil)
+	if request.Namespace == "" {
+		meterdef = clusterMeterdef.ToMeterDefinition()
+		revisionKind, excludedNamespaces = "ClusterMeterDefinition", clusterMeterdef.Spec.ExcludedNamespaces
 	}
-	if instance.Spec.PodMeterLabels != nil {
-		instance.Spec.PodMeterLabels = nil
+
+	result, _ = cc.Do(context.TODO(), recordRevision(meterdef, revisionKind, excludedNamespaces, metav1.Now()))
+	if result.Is(Error) {
+		reqLogger.Error(result.GetError(), "Failed to record revision.")
+		return result.Return()
 	}
 
+	var queue bool
+
 	switch {
-	case instance.Status.Conditions.IsUnknownFor(v1alpha1.MeterDefConditionTypeHasResult):
+	case instanceStatus.Conditions.IsUnknownFor(v1beta1.MeterDefConditionTypeHasResult):
 		fallthrough
-	case len(instance.Status.WorkloadResources) == 0:
-		queue = instance.Status.Conditions.SetCondition(v1alpha1.MeterDefConditionNoResults)
-	case len(instance.Status.WorkloadResources) > 0:
-		queue = instance.Status.Conditions.SetCondition(v1alpha1.MeterDefConditionHasResults)
+	case len(instanceStatus.WorkloadResources) == 0:
+		queue = instanceStatus.Conditions.SetCondition(v1beta1.MeterDefConditionNoResults)
+	case len(instanceStatus.WorkloadResources) > 0:
+		queue = instanceStatus.Conditions.SetCondition(v1beta1.MeterDefConditionHasResults)
 	}
 
 	result, _ = cc.Do(
@@ -165,7 +190,7 @@ func (r *ReconcileMeterDefinition) Reconcile(request reconcile.Request) (reconci
 	return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
 }
 
-func (r *ReconcileMeterDefinition) finalizeMeterDefinition(req *v1alpha1.MeterDefinition) (reconcile.Result, error) {
+func (r *ReconcileMeterDefinition) finalizeMeterDefinition(req *v1beta1.MeterDefinition) (reconcile.Result, error) {
 	var err error
 
 	// TODO: add finalizers
@@ -179,7 +204,7 @@ func (r *ReconcileMeterDefinition) finalizeMeterDefinition(req *v1alpha1.MeterDe
 }
 
 // addFinalizer adds finalizers to the MeterDefinition CR
-func (r *ReconcileMeterDefinition) addFinalizer(instance *v1alpha1.MeterDefinition) error {
+func (r *ReconcileMeterDefinition) addFinalizer(instance *v1beta1.MeterDefinition) error {
 	log.Info("Adding Finalizer to %s/%s", instance.Name, instance.Namespace)
 	instance.SetFinalizers(append(instance.GetFinalizers(), meterDefinitionFinalizer))
 
@@ -15,14 +15,21 @@
 package meterdefinition
 
 import (
+	"context"
+	"sort"
+	"time"
+
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	. "github.com/onsi/ginkgo"
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	. "github.com/onsi/gomega"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/test/rectest"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -32,6 +39,58 @@ var _ = Describe("Testing with Ginkgo", func() {
 
 		testNoServiceMonitors(GinkgoT())
 	})
+
+	It("records a revision when the meter rules change", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(marketplacev1beta1.AddToScheme(s)).To(Succeed())
+
+		created := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		instance := meterdefinition.DeepCopy()
+		instance.UID = "meterdef-uid"
+		instance.CreationTimestamp = created
+
+		k8sClient := fake.NewFakeClientWithScheme(s, instance)
+		r := &ReconcileMeterDefinition{client: k8sClient, scheme: s, ccprovider: &reconcileutils.DefaultCommandRunnerProvider{}}
+
+		listRevisions := func() []marketplacev1beta1.MeterDefinitionRevision {
+			revisions := &marketplacev1beta1.MeterDefinitionRevisionList{}
+			Expect(k8sClient.List(context.TODO(), revisions, client.MatchingLabels{
+				marketplacev1beta1.MeterDefinitionRevisionUIDLabel: "meterdef-uid",
+			})).To(Succeed())
+			sort.Slice(revisions.Items, func(i, j int) bool {
+				return revisions.Items[i].Spec.Revision < revisions.Items[j].Spec.Revision
+			})
+			return revisions.Items
+		}
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(Succeed())
+		_, err = r.Reconcile(req)
+		Expect(err).To(Succeed())
+
+		revisions := listRevisions()
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Name).To(Equal("meterdef-uid-1"))
+		Expect(revisions[0].Spec.Revision).To(Equal(int64(1)))
+		Expect(revisions[0].Spec.Hash).ToNot(BeEmpty())
+		Expect(revisions[0].Spec.EffectiveTime.Equal(&created)).To(BeTrue())
+		Expect(revisions[0].Spec.MeterDefinitionSpec.Kind).To(Equal("App"))
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, instance)).To(Succeed())
+		instance.Spec.Kind = "App2"
+		Expect(k8sClient.Update(context.TODO(), instance)).To(Succeed())
+
+		_, err = r.Reconcile(req)
+		Expect(err).To(Succeed())
+
+		revisions = listRevisions()
+		Expect(revisions).To(HaveLen(2))
+		Expect(revisions[1].Name).To(Equal("meterdef-uid-2"))
+		Expect(revisions[1].Spec.Hash).ToNot(Equal(revisions[0].Spec.Hash))
+		Expect(revisions[1].Spec.EffectiveTime.After(created.Time)).To(BeTrue())
+		Expect(revisions[1].Spec.MeterDefinitionSpec.Kind).To(Equal("App2"))
+	})
 })
 
 var (
@@ -47,12 +106,12 @@ var (
 	opts = []StepOption{
 		WithRequest(req),
 	}
-	meterdefinition = &marketplacev1alpha1.MeterDefinition{
+	meterdefinition = &marketplacev1beta1.MeterDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 		},
-		Spec: marketplacev1alpha1.MeterDefinitionSpec{
+		Spec: marketplacev1beta1.MeterDefinitionSpec{
 			Group:   "apps.partner.metering.com",
 			Kind:    "App",
 		},
@@ -62,7 +121,7 @@ var (
 func setup(r *ReconcilerTest) error {
 	s := scheme.Scheme
 	_ = monitoringv1.AddToScheme(s)
-	s.AddKnownTypes(marketplacev1alpha1.SchemeGroupVersion, meterdefinition)
+	_ = marketplacev1beta1.AddToScheme(s)
 
 	r.Client = fake.NewFakeClient(r.GetGetObjects()...)
 	r.Reconciler = &ReconcileMeterDefinition{client: r.Client, scheme: s, ccprovider: &reconcileutils.DefaultCommandRunnerProvider{}}
@@ -0,0 +1,90 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterdefinition
+
+import (
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordRevision creates a MeterDefinitionRevision when the meter rules of
+// the meter definition differ from its latest revision. The first revision
+// is effective from the creation of the meter definition, later ones from
+// now. Revisions are not owned by the meter definition so they outlive it.
+func recordRevision(
+	meterdef *v1beta1.MeterDefinition,
+	kind string,
+	excludedNamespaces []string,
+	now metav1.Time,
+) ClientAction {
+	revisions := &v1beta1.MeterDefinitionRevisionList{}
+
+	return HandleResult(
+		ListAction(revisions, client.MatchingLabels{
+			v1beta1.MeterDefinitionRevisionUIDLabel: string(meterdef.UID),
+		}),
+		OnContinue(Call(func() (ClientAction, error) {
+			hash, err := meter_definition.MeterDefinitionHash(meterdef, excludedNamespaces)
+			if err != nil {
+				return nil, err
+			}
+
+			var latest *v1beta1.MeterDefinitionRevision
+			for i := range revisions.Items {
+				if latest == nil || revisions.Items[i].Spec.Revision > latest.Spec.Revision {
+					latest = &revisions.Items[i]
+				}
+			}
+
+			revision, effectiveTime := int64(1), meterdef.CreationTimestamp
+			if latest != nil {
+				if latest.Spec.Hash == hash {
+					return nil, nil
+				}
+
+				revision, effectiveTime = latest.Spec.Revision+1, now
+			}
+
+			return CreateAction(&v1beta1.MeterDefinitionRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: v1beta1.MeterDefinitionRevisionName(meterdef.UID, revision),
+					Labels: map[string]string{
+						v1beta1.MeterDefinitionRevisionUIDLabel: string(meterdef.UID),
+					},
+				},
+				Spec: v1beta1.MeterDefinitionRevisionSpec{
+					MeterDefinition: common.NamespacedNameReference{
+						UID:       meterdef.UID,
+						Name:      meterdef.Name,
+						Namespace: meterdef.Namespace,
+						GroupVersionKind: &common.GroupVersionKind{
+							APIVersion: v1beta1.SchemeGroupVersion.String(),
+							Kind:       kind,
+						},
+					},
+					Revision:            revision,
+					Hash:                hash,
+					EffectiveTime:       effectiveTime,
+					MeterDefinitionSpec: *meterdef.Spec.DeepCopy(),
+					ExcludedNamespaces:  excludedNamespaces,
+				},
+			}), nil
+		})),
+	)
+}
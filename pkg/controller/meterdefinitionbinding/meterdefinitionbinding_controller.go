@@ -0,0 +1,289 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterdefinitionbinding
+
+import (
+	"context"
+	"reflect"
+
+	merrors "emperror.dev/errors"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_meterdefinitionbinding")
+
+// ErrNotOwned is returned when a MeterDefinition with the name from the
+// template exists and was not instantiated by the binding.
+var ErrNotOwned = merrors.Sentinel("already exists and is not owned by the binding")
+
+// Add creates a new MeterDefinitionBinding Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(
+	mgr manager.Manager,
+	ccprovider ClientCommandRunnerProvider,
+) error {
+	return add(mgr, newReconciler(mgr, ccprovider))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(
+	mgr manager.Manager,
+	ccprovider ClientCommandRunnerProvider,
+) reconcile.Reconciler {
+	return &ReconcileMeterDefinitionBinding{
+		client:     mgr.GetClient(),
+		scheme:     mgr.GetScheme(),
+		ccprovider: ccprovider,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("meterdefinitionbinding-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource MeterDefinitionBinding
+	err = c.Watch(&source.Kind{Type: &marketplacev1beta1.MeterDefinitionBinding{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	k8sClient := mgr.GetClient()
+
+	// Requeue the bindings of a template so their MeterDefinitions are regenerated
+	mapFn := handler.ToRequestsFunc(
+		func(a handler.MapObject) []reconcile.Request {
+			bindingList := &marketplacev1beta1.MeterDefinitionBindingList{}
+			if err := k8sClient.List(context.TODO(), bindingList, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+				log.Error(err, "failed to list meterdefinitionbindings")
+				return nil
+			}
+
+			var requests []reconcile.Request
+			for _, binding := range bindingList.Items {
+				if binding.Spec.TemplateName != a.Meta.GetName() {
+					continue
+				}
+
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      binding.Name,
+						Namespace: binding.Namespace,
+					},
+				})
+			}
+
+			return requests
+		})
+
+	err = c.Watch(
+		&source.Kind{Type: &marketplacev1beta1.MeterDefinitionTemplate{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: mapFn,
+		})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the MeterDefinitions instantiated by a binding
+	err = c.Watch(&source.Kind{Type: &marketplacev1beta1.MeterDefinition{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &marketplacev1beta1.MeterDefinitionBinding{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileMeterDefinitionBinding implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileMeterDefinitionBinding{}
+
+// ReconcileMeterDefinitionBinding reconciles a MeterDefinitionBinding object
+type ReconcileMeterDefinitionBinding struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client     client.Client
+	scheme     *runtime.Scheme
+	ccprovider ClientCommandRunnerProvider
+}
+
+// Reconcile instantiates the MeterDefinition of a MeterDefinitionBinding from
+// its MeterDefinitionTemplate and keeps it up to date with the template.
+func (r *ReconcileMeterDefinitionBinding) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling MeterDefinitionBinding")
+
+	cc := r.ccprovider.NewCommandRunner(r.client, r.scheme, reqLogger)
+
+	// Fetch the MeterDefinitionBinding instance
+	instance := &marketplacev1beta1.MeterDefinitionBinding{}
+
+	if result, _ := cc.Do(context.TODO(), GetAction(request.NamespacedName, instance)); !result.Is(Continue) {
+		if result.Is(NotFound) {
+			reqLogger.Info("MeterDefinitionBinding resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+
+		if result.Is(Error) {
+			reqLogger.Error(result.GetError(), "Failed to get MeterDefinitionBinding.")
+		}
+
+		return result.Return()
+	}
+
+	// Fetch the MeterDefinitionTemplate of the binding
+	meterDefinitionTemplate := &marketplacev1beta1.MeterDefinitionTemplate{}
+	templateKey := types.NamespacedName{Name: instance.Spec.TemplateName, Namespace: instance.Namespace}
+
+	if result, _ := cc.Do(context.TODO(), GetAction(templateKey, meterDefinitionTemplate)); !result.Is(Continue) {
+		if result.Is(NotFound) {
+			reqLogger.Info("MeterDefinitionTemplate not found", "template", instance.Spec.TemplateName)
+			result, _ = cc.Do(context.TODO(),
+				UpdateStatusCondition(instance, &instance.Status.Conditions, marketplacev1beta1.BindingConditionTemplateNotFound))
+
+			if result.Is(Error) {
+				return result.ReturnWithError(merrors.Wrap(result.GetError(), "error updating status"))
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		if result.Is(Error) {
+			reqLogger.Error(result.GetError(), "Failed to get MeterDefinitionTemplate.")
+		}
+
+		return result.Return()
+	}
+
+	meterDefinition, err := instantiateMeterDefinition(meterDefinitionTemplate, instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to instantiate MeterDefinition")
+		return r.updateInvalidCondition(cc, instance, err)
+	}
+
+	if err := controllerutil.SetControllerReference(instance, meterDefinition, r.scheme); err != nil {
+		return reconcile.Result{}, merrors.Wrap(err, "error adding owner")
+	}
+
+	// The template changed the name of the MeterDefinition so the old one is removed
+	if oldName := instance.Status.MeterDefinitionName; oldName != "" && oldName != meterDefinition.Name {
+		oldMeterDefinition := &marketplacev1beta1.MeterDefinition{}
+
+		if result, err := cc.Do(
+			context.TODO(),
+			HandleResult(
+				GetAction(types.NamespacedName{Name: oldName, Namespace: instance.Namespace}, oldMeterDefinition),
+				OnContinue(Call(func() (ClientAction, error) {
+					if !metav1.IsControlledBy(oldMeterDefinition, instance) {
+						return nil, nil
+					}
+
+					reqLogger.Info("deleting renamed MeterDefinition", "name", oldName)
+					return DeleteAction(oldMeterDefinition), nil
+				})),
+			),
+		); result.Is(Error) {
+			return result.ReturnWithError(merrors.Wrap(err, "error deleting renamed meterdefinition"))
+		}
+	}
+
+	actualMeterDefinition := &marketplacev1beta1.MeterDefinition{}
+
+	result, err := cc.Do(
+		context.TODO(),
+		HandleResult(
+			GetAction(types.NamespacedName{Name: meterDefinition.Name, Namespace: meterDefinition.Namespace}, actualMeterDefinition),
+			OnNotFound(CreateAction(meterDefinition)),
+			OnContinue(Call(func() (ClientAction, error) {
+				if !metav1.IsControlledBy(actualMeterDefinition, instance) {
+					return nil, merrors.Wrapf(ErrNotOwned, "meterdefinition %s", meterDefinition.Name)
+				}
+
+				if reflect.DeepEqual(actualMeterDefinition.Spec, meterDefinition.Spec) &&
+					reflect.DeepEqual(actualMeterDefinition.Labels, meterDefinition.Labels) &&
+					reflect.DeepEqual(actualMeterDefinition.Annotations, meterDefinition.Annotations) {
+					return nil, nil
+				}
+
+				reqLogger.Info("updating MeterDefinition from template", "name", meterDefinition.Name)
+				actualMeterDefinition.Labels = meterDefinition.Labels
+				actualMeterDefinition.Annotations = meterDefinition.Annotations
+				actualMeterDefinition.Spec = meterDefinition.Spec
+
+				return UpdateAction(actualMeterDefinition), nil
+			})),
+		),
+	)
+
+	if result.Is(Error) {
+		if merrors.Is(err, ErrNotOwned) {
+			reqLogger.Error(err, "Failed to instantiate MeterDefinition")
+			return r.updateInvalidCondition(cc, instance, err)
+		}
+
+		return result.ReturnWithError(merrors.Wrap(err, "error instantiating meterdefinition"))
+	}
+
+	changed := instance.Status.Conditions.SetCondition(marketplacev1beta1.BindingConditionInstantiated) ||
+		instance.Status.MeterDefinitionName != meterDefinition.Name ||
+		instance.Status.TemplateGeneration != meterDefinitionTemplate.Generation
+
+	if changed {
+		instance.Status.MeterDefinitionName = meterDefinition.Name
+		instance.Status.TemplateGeneration = meterDefinitionTemplate.Generation
+
+		if result, err := cc.Do(context.TODO(), UpdateAction(instance, UpdateStatusOnly(true))); result.Is(Error) {
+			return result.ReturnWithError(merrors.Wrap(err, "error updating status"))
+		}
+	}
+
+	reqLogger.Info("reconcilation complete")
+	return result.Return()
+}
+
+// updateInvalidCondition records why the template could not be instantiated.
+// The binding is reconciled again when it or its template changes.
+func (r *ReconcileMeterDefinitionBinding) updateInvalidCondition(
+	cc ClientCommandRunner,
+	instance *marketplacev1beta1.MeterDefinitionBinding,
+	err error,
+) (reconcile.Result, error) {
+	condition := marketplacev1beta1.BindingConditionTemplateInvalid
+	condition.Message = err.Error()
+
+	if result, err := cc.Do(context.TODO(),
+		UpdateStatusCondition(instance, &instance.Status.Conditions, condition)); result.Is(Error) {
+		return result.ReturnWithError(merrors.Wrap(err, "error updating status"))
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterdefinitionbinding
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const meterDefinitionTemplateText = `
+apiVersion: marketplace.redhat.com/v1beta1
+kind: MeterDefinition
+spec:
+  group: partner.metering.com
+  kind: {{ quote .Parameters.kind }}
+  workloadVertexType: Namespace
+  workloads:
+    - name: {{ .Name }}-pods
+      type: Pod
+      labelSelector:
+        matchLabels:
+          app: {{ quote .Parameters.app }}
+      metricLabels:
+        - label: {{ quote .Parameters.metric }}
+          aggregation: sum
+`
+
+var _ = Describe("MeterDefinitionBinding", func() {
+	var (
+		meterDefinitionTemplate *marketplacev1beta1.MeterDefinitionTemplate
+		binding                 *marketplacev1beta1.MeterDefinitionBinding
+	)
+
+	BeforeEach(func() {
+		meterDefinitionTemplate = &marketplacev1beta1.MeterDefinitionTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-template", Namespace: "ns", Generation: 2},
+			Spec: marketplacev1beta1.MeterDefinitionTemplateSpec{
+				Parameters: []marketplacev1beta1.TemplateParameter{
+					{Name: "kind", Required: true},
+					{Name: "app", Default: "example"},
+					{Name: "metric", Default: "container_spec_cpu_shares"},
+				},
+				Template: meterDefinitionTemplateText,
+			},
+		}
+
+		binding = &marketplacev1beta1.MeterDefinitionBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-binding", Namespace: "ns", UID: "binding-uid"},
+			Spec: marketplacev1beta1.MeterDefinitionBindingSpec{
+				TemplateName: "app-template",
+				Parameters: map[string]string{
+					"kind": "App",
+					"app":  "my-app",
+				},
+			},
+		}
+	})
+
+	Context("instantiating the template", func() {
+		It("should render the parameters and defaults", func() {
+			meterDefinition, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(Succeed())
+
+			Expect(meterDefinition.Name).To(Equal("app-binding"))
+			Expect(meterDefinition.Namespace).To(Equal("ns"))
+			Expect(meterDefinition.Annotations).To(HaveKeyWithValue(utils.BINDING_ANNOTATION_NAME, "app-binding"))
+			Expect(meterDefinition.Spec.Kind).To(Equal("App"))
+			Expect(meterDefinition.Spec.Workloads).To(HaveLen(1))
+
+			workload := meterDefinition.Spec.Workloads[0]
+			Expect(workload.Name).To(Equal("app-binding-pods"))
+			Expect(workload.LabelSelector.MatchLabels).To(HaveKeyWithValue("app", "my-app"))
+			Expect(workload.MetricLabels[0].Label).To(Equal("container_spec_cpu_shares"))
+		})
+
+		It("should keep the annotations of the template and not set installedBy", func() {
+			meterDefinitionTemplate.Spec.Template = strings.Replace(meterDefinitionTemplateText, "kind: MeterDefinition\n", `kind: MeterDefinition
+metadata:
+  annotations:
+    team: metering
+`, 1)
+
+			meterDefinition, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(Succeed())
+
+			Expect(meterDefinition.Spec.InstalledBy).To(BeNil())
+			Expect(meterDefinition.Annotations).To(Equal(map[string]string{
+				"team":                             "metering",
+				utils.BINDING_ANNOTATION_NAME:      "app-binding",
+				utils.BINDING_ANNOTATION_NAMESPACE: "ns",
+			}))
+		})
+
+		It("should quote the parameter values", func() {
+			binding.Spec.Parameters["app"] = "my-app\n          other: label"
+
+			meterDefinition, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(Succeed())
+
+			Expect(meterDefinition.Spec.Workloads[0].LabelSelector.MatchLabels).To(Equal(map[string]string{
+				"app": "my-app\n          other: label",
+			}))
+		})
+
+		It("should fail when a required parameter is missing", func() {
+			delete(binding.Spec.Parameters, "kind")
+
+			_, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(MatchError(ContainSubstring("parameter kind is required")))
+		})
+
+		It("should fail on a parameter the template does not define", func() {
+			binding.Spec.Parameters["unknown"] = "value"
+
+			_, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(MatchError(ContainSubstring("parameter unknown is not a parameter")))
+		})
+
+		It("should fail when the template references an undefined parameter", func() {
+			meterDefinitionTemplate.Spec.Template = "kind: {{ .Parameters.missing }}"
+
+			_, err := instantiateMeterDefinition(meterDefinitionTemplate, binding)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("reconciling", func() {
+		var (
+			k8sClient  client.Client
+			reconciler *ReconcileMeterDefinitionBinding
+			request    reconcile.Request
+		)
+
+		setup := func(objs ...runtime.Object) {
+			testScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+			Expect(marketplacev1beta1.AddToScheme(testScheme)).To(Succeed())
+
+			k8sClient = fake.NewFakeClientWithScheme(testScheme, objs...)
+			reconciler = &ReconcileMeterDefinitionBinding{
+				client:     k8sClient,
+				scheme:     testScheme,
+				ccprovider: &DefaultCommandRunnerProvider{},
+			}
+		}
+
+		BeforeEach(func() {
+			request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "app-binding", Namespace: "ns"}}
+		})
+
+		It("should create the meterdefinition owned by the binding", func() {
+			setup(meterDefinitionTemplate, binding)
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(Succeed())
+
+			meterDefinition := &marketplacev1beta1.MeterDefinition{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "app-binding", Namespace: "ns"}, meterDefinition)).To(Succeed())
+			Expect(metav1.IsControlledBy(meterDefinition, binding)).To(BeTrue())
+			Expect(meterDefinition.Spec.Kind).To(Equal("App"))
+
+			Expect(k8sClient.Get(context.TODO(), request.NamespacedName, binding)).To(Succeed())
+			Expect(binding.Status.MeterDefinitionName).To(Equal("app-binding"))
+			Expect(binding.Status.TemplateGeneration).To(Equal(int64(2)))
+			Expect(binding.Status.Conditions.IsTrueFor(marketplacev1beta1.BindingConditionTypeInstantiated)).To(BeTrue())
+		})
+
+		It("should update the meterdefinition when the binding changes", func() {
+			setup(meterDefinitionTemplate, binding)
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), request.NamespacedName, binding)).To(Succeed())
+			binding.Spec.Parameters["metric"] = "container_memory_usage_bytes"
+			Expect(k8sClient.Update(context.TODO(), binding)).To(Succeed())
+
+			_, err = reconciler.Reconcile(request)
+			Expect(err).To(Succeed())
+
+			meterDefinition := &marketplacev1beta1.MeterDefinition{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "app-binding", Namespace: "ns"}, meterDefinition)).To(Succeed())
+			Expect(meterDefinition.Spec.Workloads[0].MetricLabels[0].Label).To(Equal("container_memory_usage_bytes"))
+		})
+
+		It("should report a missing template", func() {
+			setup(binding)
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), request.NamespacedName, binding)).To(Succeed())
+			cond := binding.Status.Conditions.GetCondition(marketplacev1beta1.BindingConditionTypeInstantiated)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(marketplacev1beta1.BindingConditionReasonTemplateNotFound))
+		})
+
+		It("should not take over a meterdefinition it does not own", func() {
+			existing := &marketplacev1beta1.MeterDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-binding", Namespace: "ns"},
+				Spec: marketplacev1beta1.MeterDefinitionSpec{
+					Group: "other.metering.com",
+					Kind:  "Other",
+				},
+			}
+			setup(meterDefinitionTemplate, binding, existing)
+
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), request.NamespacedName, binding)).To(Succeed())
+			cond := binding.Status.Conditions.GetCondition(marketplacev1beta1.BindingConditionTypeInstantiated)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Reason).To(Equal(marketplacev1beta1.BindingConditionReasonTemplateInvalid))
+
+			meterDefinition := &marketplacev1beta1.MeterDefinition{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "app-binding", Namespace: "ns"}, meterDefinition)).To(Succeed())
+			Expect(meterDefinition.Spec.Kind).To(Equal("Other"))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterdefinitionbinding
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+
+	merrors "emperror.dev/errors"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// templateData is passed to the template of a MeterDefinitionTemplate.
+type templateData struct {
+	Name       string
+	Namespace  string
+	Parameters map[string]string
+}
+
+// instantiateMeterDefinition renders the template with the parameters of the
+// binding and builds the MeterDefinition from it.
+func instantiateMeterDefinition(
+	meterDefinitionTemplate *marketplacev1beta1.MeterDefinitionTemplate,
+	binding *marketplacev1beta1.MeterDefinitionBinding,
+) (*marketplacev1beta1.MeterDefinition, error) {
+	parameters := make(map[string]string)
+
+	for _, parameter := range meterDefinitionTemplate.Spec.Parameters {
+		value, ok := binding.Spec.Parameters[parameter.Name]
+
+		switch {
+		case ok:
+			parameters[parameter.Name] = value
+		case parameter.Required:
+			return nil, merrors.Errorf("parameter %s is required", parameter.Name)
+		default:
+			parameters[parameter.Name] = parameter.Default
+		}
+	}
+
+	for name := range binding.Spec.Parameters {
+		if _, ok := parameters[name]; !ok {
+			return nil, merrors.Errorf("parameter %s is not a parameter of template %s", name, meterDefinitionTemplate.Name)
+		}
+	}
+
+	tmpl, err := template.New(meterDefinitionTemplate.Name).
+		Option("missingkey=error").
+		Funcs(templateFuncs).
+		Parse(meterDefinitionTemplate.Spec.Template)
+	if err != nil {
+		return nil, merrors.Wrap(err, "failed to parse template")
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, templateData{
+		Name:       binding.Name,
+		Namespace:  binding.Namespace,
+		Parameters: parameters,
+	})
+	if err != nil {
+		return nil, merrors.Wrap(err, "failed to execute template")
+	}
+
+	data, err := yaml.ToJSON(buf.Bytes())
+	if err != nil {
+		return nil, merrors.Wrap(err, "failed to convert template to json")
+	}
+
+	meterDefinition := &marketplacev1beta1.MeterDefinition{}
+	err = json.Unmarshal(data, meterDefinition)
+	if err != nil {
+		return nil, merrors.Wrap(err, "failed to build MeterDefinition")
+	}
+
+	// the instance is identified by its controller reference to the binding,
+	// InstalledBy is a reference to a CSV
+	meterDefinition.Namespace = binding.Namespace
+	meterDefinition.Spec.InstalledBy = nil
+
+	annotations := meterDefinition.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[utils.BINDING_ANNOTATION_NAME] = binding.Name
+	annotations[utils.BINDING_ANNOTATION_NAMESPACE] = binding.Namespace
+	meterDefinition.SetAnnotations(annotations)
+
+	if meterDefinition.Name == "" {
+		meterDefinition.Name = binding.Name
+	}
+
+	meterDefinition.APIVersion = marketplacev1beta1.SchemeGroupVersion.String()
+	meterDefinition.Kind = "MeterDefinition"
+
+	return meterDefinition, nil
+}
+
+// templateFuncs are the functions available to templates. Parameter values
+// are inserted as is, quote inserts them as YAML strings so they cannot
+// change the structure of the MeterDefinition.
+var templateFuncs = template.FuncMap{
+	"quote":  toJSON,
+	"toJson": toJSON,
+}
+
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -31,6 +31,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -147,6 +148,48 @@ func (r *ReconcileMeterReport) Reconcile(request reconcile.Request) (reconcile.R
 		instance.Status.Conditions = &conds
 	}
 
+	if runRequest := instance.GetRunRequest(); runRequest != instance.Status.LastRunRequest {
+		reqLogger.Info("run requested", "runRequest", runRequest, "lastRunRequest", instance.Status.LastRunRequest)
+
+		result, _ := cc.Do(
+			context.TODO(),
+			Call(func() (ClientAction, error) {
+				if instance.Status.AssociatedJob == nil {
+					return nil, nil
+				}
+
+				oldJob := &batchv1.Job{}
+				return HandleResult(
+					GetAction(instance.Status.AssociatedJob.NamespacedName(), oldJob),
+					OnContinue(DeleteAction(oldJob, DeleteWithDeleteOptions(client.PropagationPolicy(metav1.DeletePropagationBackground)))),
+				), nil
+			}),
+			Call(func() (ClientAction, error) {
+				if instance.Status.AssociatedJob != nil {
+					instance.ArchiveRun(metav1.Now())
+				}
+
+				instance.Status.LastRunRequest = runRequest
+				return UpdateAction(instance, UpdateStatusOnly(true)), nil
+			}),
+		)
+
+		if result.Is(Error) {
+			reqLogger.Error(result.GetError(), "Failed to archive previous run.")
+			return result.Return()
+		}
+
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// the job of a finished run may have been removed by its ttl, it is
+	// only created again on a run request
+	if cond := instance.Status.Conditions.GetCondition(marketplacev1alpha1.ReportConditionTypeJobRunning); cond != nil &&
+		cond.Reason == marketplacev1alpha1.ReportConditionReasonJobFinished {
+		reqLogger.Info("report run has finished")
+		return reconcile.Result{}, nil
+	}
+
 	job := &batchv1.Job{}
 
 	c := manifests.NewOperatorConfig(r.cfg)
@@ -181,13 +224,23 @@ func (r *ReconcileMeterReport) Reconcile(request reconcile.Request) (reconcile.R
 
 	}
 
+	// the meterbase is optional, the job defaults are used if it isn't found
+	meterBase := &marketplacev1alpha1.MeterBase{}
+	if result, _ := cc.Do(
+		context.TODO(),
+		GetAction(types.NamespacedName{Name: utils.METERBASE_NAME, Namespace: instance.Namespace}, meterBase),
+	); result.Is(Error) {
+		reqLogger.Error(result.GetError(), "Failed to get MeterBase.")
+		return result.Return()
+	}
+
 	result, _ := cc.Do(
 		context.TODO(),
 		HandleResult(
 			manifests.CreateIfNotExistsFactoryItem(
 				job,
 				func() (runtime.Object, error) {
-					return factory.ReporterJob(instance)
+					return factory.ReporterJob(instance, meterBase.Spec.ReporterJobTemplate)
 				}, CreateWithAddOwner(instance),
 			),
 			OnRequeue(UpdateStatusCondition(instance, instance.Status.Conditions, marketplacev1alpha1.ReportConditionJobSubmitted)),
@@ -202,6 +255,12 @@ func (r *ReconcileMeterReport) Reconcile(request reconcile.Request) (reconcile.R
 		return result.Return()
 	}
 
+	// the job of an archived run may still be in the cache
+	if job.GetDeletionTimestamp() != nil || isArchivedJob(instance, job) {
+		reqLogger.Info("waiting for the job of the previous run to be deleted")
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	jr := &common.JobReference{}
 	jr.SetFromJob(job)
 
@@ -265,3 +324,20 @@ func (r *ReconcileMeterReport) Reconcile(request reconcile.Request) (reconcile.R
 	reqLogger.Info("reconcile finished")
 	return reconcile.Result{}, nil
 }
+
+// isArchivedJob returns true if the job belongs to an archived run. Jobs
+// without a UID are never matched since runs without a job UID recorded
+// would all match them.
+func isArchivedJob(instance *marketplacev1alpha1.MeterReport, job *batchv1.Job) bool {
+	if job.UID == "" {
+		return false
+	}
+
+	for _, run := range instance.Status.RunHistory {
+		if run.AssociatedJob != nil && run.AssociatedJob.UID != "" && run.AssociatedJob.UID == job.UID {
+			return true
+		}
+	}
+
+	return false
+}
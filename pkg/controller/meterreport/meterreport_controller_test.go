@@ -0,0 +1,181 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterreport
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/config"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/patch"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("MeterReportController", func() {
+	var (
+		namespace = "redhat-marketplace-operator"
+		req       = reconcile.Request{NamespacedName: types.NamespacedName{Name: "meter-report-2020-06-01", Namespace: namespace}}
+		start     = time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+		instance *marketplacev1alpha1.MeterReport
+		job      *batchv1.Job
+	)
+
+	newReconciler := func(objs ...runtime.Object) (*ReconcileMeterReport, client.Client) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(marketplacev1alpha1.AddToScheme(s)).To(Succeed())
+
+		k8sClient := fake.NewFakeClientWithScheme(s, objs...)
+		return &ReconcileMeterReport{
+			client:     k8sClient,
+			scheme:     s,
+			ccprovider: &reconcileutils.DefaultCommandRunnerProvider{},
+			patcher:    patch.RHMDefaultPatcher,
+			cfg:        config.OperatorConfig{},
+		}, k8sClient
+	}
+
+	BeforeEach(func() {
+		conds := status.NewConditions(marketplacev1alpha1.ReportConditionJobFinished)
+		instance = &marketplacev1alpha1.MeterReport{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace},
+			Spec: marketplacev1alpha1.MeterReportSpec{
+				StartTime: metav1.NewTime(start),
+				EndTime:   metav1.NewTime(start.AddDate(0, 0, 1)),
+			},
+			Status: marketplacev1alpha1.MeterReportStatus{
+				Conditions:     &conds,
+				LastRunRequest: "1",
+				AssociatedJob: &common.JobReference{
+					Namespace: namespace,
+					Name:      req.Name,
+					UID:       "job-uid",
+					Succeeded: 1,
+				},
+				ReportID: "report-id",
+			},
+		}
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace, UID: "job-uid"},
+		}
+	})
+
+	It("should archive the current run", func() {
+		now := metav1.NewTime(start.AddDate(0, 0, 2))
+		instance.ArchiveRun(now)
+
+		Expect(instance.Status.RunHistory).To(HaveLen(1))
+		run := instance.Status.RunHistory[0]
+		Expect(run.RunRequest).To(Equal("1"))
+		Expect(run.ArchivedTime).To(Equal(now))
+		Expect(run.ReportID).To(Equal("report-id"))
+		Expect(run.AssociatedJob.UID).To(Equal(types.UID("job-uid")))
+		Expect(run.Conditions.GetCondition(marketplacev1alpha1.ReportConditionTypeJobRunning).Reason).
+			To(Equal(marketplacev1alpha1.ReportConditionJobFinished.Reason))
+
+		Expect(instance.Status.AssociatedJob).To(BeNil())
+		Expect(instance.Status.ReportID).To(BeEmpty())
+		Expect(instance.Status.Conditions.GetCondition(marketplacev1alpha1.ReportConditionTypeJobRunning).Reason).
+			To(Equal(marketplacev1alpha1.ReportConditionJobNotStarted.Reason))
+	})
+
+	It("should keep a bounded run history", func() {
+		for i := 0; i < marketplacev1alpha1.MeterReportMaxRunHistory+2; i++ {
+			instance.Status.LastRunRequest = string(rune('a' + i))
+			instance.ArchiveRun(metav1.Now())
+		}
+
+		Expect(instance.Status.RunHistory).To(HaveLen(marketplacev1alpha1.MeterReportMaxRunHistory))
+		Expect(instance.Status.RunHistory[0].RunRequest).To(Equal("c"))
+	})
+
+	It("should only match archived jobs by a recorded UID", func() {
+		instance.Status.RunHistory = []marketplacev1alpha1.MeterReportRun{
+			{AssociatedJob: &common.JobReference{Namespace: namespace, Name: req.Name}},
+		}
+
+		Expect(isArchivedJob(instance, &batchv1.Job{})).To(BeFalse())
+		Expect(isArchivedJob(instance, job)).To(BeFalse())
+
+		instance.Status.RunHistory[0].AssociatedJob.UID = "job-uid"
+		Expect(isArchivedJob(instance, job)).To(BeTrue())
+	})
+
+	It("should archive the run and delete its job on a run request", func() {
+		instance.Spec.RunRequest = "2"
+		r, k8sClient := newReconciler(instance, job)
+
+		result, err := r.Reconcile(req)
+		Expect(err).To(Succeed())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, &batchv1.Job{})).To(
+			WithTransform(errors.IsNotFound, BeTrue()))
+
+		updated := &marketplacev1alpha1.MeterReport{}
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.LastRunRequest).To(Equal("2"))
+		Expect(updated.Status.AssociatedJob).To(BeNil())
+		Expect(updated.Status.RunHistory).To(HaveLen(1))
+		Expect(updated.Status.RunHistory[0].RunRequest).To(Equal("1"))
+		Expect(updated.Status.RunHistory[0].AssociatedJob.UID).To(Equal(types.UID("job-uid")))
+	})
+
+	It("should not recreate the job of a finished run after it is deleted", func() {
+		instance.Spec.RunRequest = "1"
+		r, k8sClient := newReconciler(instance)
+
+		result, err := r.Reconcile(req)
+		Expect(err).To(Succeed())
+		Expect(result).To(Equal(reconcile.Result{}))
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, &batchv1.Job{})).To(
+			WithTransform(errors.IsNotFound, BeTrue()))
+
+		updated := &marketplacev1alpha1.MeterReport{}
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.AssociatedJob.UID).To(Equal(types.UID("job-uid")))
+		Expect(updated.Status.ReportID).To(Equal("report-id"))
+	})
+
+	It("should not archive a run that has no job", func() {
+		instance.Spec.RunRequest = "2"
+		instance.Status.AssociatedJob = nil
+		r, k8sClient := newReconciler(instance)
+
+		_, err := r.Reconcile(req)
+		Expect(err).To(Succeed())
+
+		updated := &marketplacev1alpha1.MeterReport{}
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.LastRunRequest).To(Equal("2"))
+		Expect(updated.Status.RunHistory).To(BeEmpty())
+	})
+})
@@ -0,0 +1,257 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterreportsummary
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	merrors "emperror.dev/errors"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_meterreportsummary")
+
+const (
+	defaultFinalizeAfter = 48 * time.Hour
+	maxRequeueAfter      = time.Hour
+)
+
+// Add creates a new MeterReportSummary Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(
+	mgr manager.Manager,
+	ccprovider ClientCommandRunnerProvider,
+) error {
+	return add(mgr, newReconciler(mgr, ccprovider))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(
+	mgr manager.Manager,
+	ccprovider ClientCommandRunnerProvider,
+) reconcile.Reconciler {
+	return &ReconcileMeterReportSummary{
+		client:     mgr.GetClient(),
+		scheme:     mgr.GetScheme(),
+		ccprovider: ccprovider,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("meterreportsummary-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource MeterReportSummary
+	err = c.Watch(&source.Kind{Type: &marketplacev1alpha1.MeterReportSummary{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	k8sClient := mgr.GetClient()
+
+	// Requeue the open summaries of the billing periods a report overlaps
+	mapFn := handler.ToRequestsFunc(
+		func(a handler.MapObject) []reconcile.Request {
+			report, ok := a.Object.(*marketplacev1alpha1.MeterReport)
+			if !ok {
+				return nil
+			}
+
+			summaryList := &marketplacev1alpha1.MeterReportSummaryList{}
+			if err := k8sClient.List(context.TODO(), summaryList, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+				log.Error(err, "failed to list meterreportsummaries")
+				return nil
+			}
+
+			var requests []reconcile.Request
+			for _, summary := range summaryList.Items {
+				if summary.IsFinalized() ||
+					!report.Spec.StartTime.Time.Before(summary.Spec.EndTime.Time) ||
+					!report.Spec.EndTime.Time.After(summary.Spec.StartTime.Time) {
+					continue
+				}
+
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      summary.Name,
+						Namespace: summary.Namespace,
+					},
+				})
+			}
+
+			return requests
+		})
+
+	err = c.Watch(
+		&source.Kind{Type: &marketplacev1alpha1.MeterReport{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: mapFn,
+		})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileMeterReportSummary implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileMeterReportSummary{}
+
+// ReconcileMeterReportSummary reconciles a MeterReportSummary object
+type ReconcileMeterReportSummary struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client     client.Client
+	scheme     *runtime.Scheme
+	ccprovider ClientCommandRunnerProvider
+}
+
+// Reconcile totals the MeterReports of the billing period of a MeterReportSummary
+// and finalizes the summary once the period has closed.
+func (r *ReconcileMeterReportSummary) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling MeterReportSummary")
+
+	cc := r.ccprovider.NewCommandRunner(r.client, r.scheme, reqLogger)
+
+	// Fetch the MeterReportSummary instance
+	instance := &marketplacev1alpha1.MeterReportSummary{}
+
+	if result, _ := cc.Do(context.TODO(), GetAction(request.NamespacedName, instance)); !result.Is(Continue) {
+		if result.Is(NotFound) {
+			reqLogger.Info("MeterReportSummary resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+
+		if result.Is(Error) {
+			reqLogger.Error(result.GetError(), "Failed to get MeterReportSummary.")
+		}
+
+		return result.Return()
+	}
+
+	if instance.IsFinalized() {
+		reqLogger.Info("MeterReportSummary is finalized")
+		return reconcile.Result{}, nil
+	}
+
+	var requeueAfter time.Duration
+	meterReportList := &marketplacev1alpha1.MeterReportList{}
+
+	if result, err := cc.Do(
+		context.TODO(),
+		HandleResult(
+			ListAction(meterReportList, client.InNamespace(request.Namespace)),
+			OnContinue(Call(func() (ClientAction, error) {
+				now := time.Now()
+
+				summary, err := summarize(instance, meterReportList.Items, now)
+				if err != nil {
+					return nil, err
+				}
+
+				changed := summary.apply(instance, now)
+				requeueAfter = summary.requeueAfter
+
+				if !changed {
+					return nil, nil
+				}
+
+				return UpdateAction(instance, UpdateStatusOnly(true)), nil
+			})),
+		),
+	); result.Is(Error) || result.Is(Requeue) {
+		if err != nil {
+			return result.ReturnWithError(merrors.Wrap(err, "error summarizing meterreports"))
+		}
+
+		return result.Return()
+	}
+
+	if instance.IsFinalized() {
+		reqLogger.Info("finalized MeterReportSummary")
+		return reconcile.Result{}, nil
+	}
+
+	reqLogger.Info("finished reconciling", "requeueAfter", requeueAfter)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// periodSummary is the state of a billing period calculated from its
+// MeterReports.
+type periodSummary struct {
+	totals      []marketplacev1alpha1.MeterTotal
+	reports     []string
+	missingDays []string
+	erroredDays []string
+	pendingDays []string
+	condition   status.Condition
+
+	finalize     bool
+	requeueAfter time.Duration
+}
+
+// apply sets the summary on the status of the instance and returns true if the
+// status changed.
+func (s *periodSummary) apply(instance *marketplacev1alpha1.MeterReportSummary, now time.Time) bool {
+	summaryStatus := &instance.Status
+
+	changed := !reflect.DeepEqual(summaryStatus.Totals, s.totals) ||
+		!reflect.DeepEqual(summaryStatus.Reports, s.reports) ||
+		!reflect.DeepEqual(summaryStatus.MissingDays, s.missingDays) ||
+		!reflect.DeepEqual(summaryStatus.ErroredDays, s.erroredDays) ||
+		!reflect.DeepEqual(summaryStatus.PendingDays, s.pendingDays)
+
+	summaryStatus.Totals = s.totals
+	summaryStatus.Reports = s.reports
+	summaryStatus.MissingDays = s.missingDays
+	summaryStatus.ErroredDays = s.erroredDays
+	summaryStatus.PendingDays = s.pendingDays
+
+	if summaryStatus.Conditions.SetCondition(s.condition) {
+		changed = true
+	}
+
+	if s.finalize {
+		finalizedTime := metav1.NewTime(now)
+		summaryStatus.FinalizedTime = &finalizedTime
+		changed = true
+	}
+
+	if changed {
+		lastUpdateTime := metav1.NewTime(now)
+		summaryStatus.LastUpdateTime = &lastUpdateTime
+	}
+
+	return changed
+}
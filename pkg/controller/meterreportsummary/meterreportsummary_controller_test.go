@@ -0,0 +1,174 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterreportsummary
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MeterReportSummary", func() {
+	var (
+		instance *marketplacev1alpha1.MeterReportSummary
+		reports  []marketplacev1alpha1.MeterReport
+		day      = func(d int) time.Time { return time.Date(2020, time.June, d, 0, 0, 0, 0, time.UTC) }
+		total    = func(workload, value string) marketplacev1alpha1.MeterTotal {
+			return marketplacev1alpha1.MeterTotal{
+				Domain:    "apps.partner.metering.com",
+				Kind:      "App",
+				Namespace: "ns",
+				Workload:  workload,
+				Metric:    "rpc_durations_seconds",
+				Value:     value,
+			}
+		}
+		report = func(name string, d int, cond status.Condition, totals ...marketplacev1alpha1.MeterTotal) marketplacev1alpha1.MeterReport {
+			conds := status.NewConditions(cond)
+			return marketplacev1alpha1.MeterReport{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+				Spec: marketplacev1alpha1.MeterReportSpec{
+					StartTime: metav1.NewTime(day(d)),
+					EndTime:   metav1.NewTime(day(d + 1)),
+					Type:      marketplacev1alpha1.MeterReportTypeScheduled,
+				},
+				Status: marketplacev1alpha1.MeterReportStatus{
+					Conditions:  &conds,
+					MeterTotals: totals,
+				},
+			}
+		}
+	)
+
+	BeforeEach(func() {
+		instance = &marketplacev1alpha1.MeterReportSummary{
+			ObjectMeta: metav1.ObjectMeta{Name: "meter-report-summary-2020-06", Namespace: "ns"},
+			Spec: marketplacev1alpha1.MeterReportSummarySpec{
+				StartTime: metav1.NewTime(day(1)),
+				EndTime:   metav1.NewTime(day(4)),
+			},
+		}
+
+		erroredReport := report("meter-report-2020-06-02", 2, marketplacev1alpha1.ReportConditionJobFinished,
+			total("app-a", "0.5"))
+		erroredReport.Status.QueryErrorList = []string{"query failed"}
+
+		adHocReport := report("adhoc", 1, marketplacev1alpha1.ReportConditionJobFinished, total("app-a", "100"))
+		adHocReport.Spec.Type = marketplacev1alpha1.MeterReportTypeAdHoc
+
+		reports = []marketplacev1alpha1.MeterReport{
+			report("meter-report-2020-06-01", 1, marketplacev1alpha1.ReportConditionJobFinished,
+				total("app-a", "1"), total("app-b", "2.5")),
+			erroredReport,
+			adHocReport,
+		}
+	})
+
+	It("should total reports and track missing and errored days", func() {
+		summary, err := summarize(instance, reports, day(3).Add(12*time.Hour))
+		Expect(err).To(Succeed())
+
+		Expect(summary.totals).To(Equal([]marketplacev1alpha1.MeterTotal{
+			total("app-a", "1.5"),
+			total("app-b", "2.5"),
+		}))
+		Expect(summary.reports).To(Equal([]string{"meter-report-2020-06-01", "meter-report-2020-06-02"}))
+		Expect(summary.missingDays).To(Equal([]string{"2020-06-03"}))
+		Expect(summary.erroredDays).To(Equal([]string{"2020-06-02"}))
+		Expect(summary.pendingDays).To(BeEmpty())
+		Expect(summary.condition).To(Equal(marketplacev1alpha1.SummaryConditionPeriodOpen))
+		Expect(summary.finalize).To(BeFalse())
+		Expect(summary.requeueAfter).To(Equal(maxRequeueAfter))
+	})
+
+	It("should wait for pending reports after the period closes", func() {
+		reports = append(reports, report("meter-report-2020-06-03", 3, marketplacev1alpha1.ReportConditionJobSubmitted))
+
+		summary, err := summarize(instance, reports, day(4).Add(time.Hour))
+		Expect(err).To(Succeed())
+
+		Expect(summary.missingDays).To(BeEmpty())
+		Expect(summary.pendingDays).To(Equal([]string{"2020-06-03"}))
+		Expect(summary.condition).To(Equal(marketplacev1alpha1.SummaryConditionReportsPending))
+		Expect(summary.finalize).To(BeFalse())
+
+		summary, err = summarize(instance, reports, day(6).Add(time.Hour))
+		Expect(err).To(Succeed())
+		Expect(summary.condition).To(Equal(marketplacev1alpha1.SummaryConditionFinalized))
+		Expect(summary.finalize).To(BeTrue())
+	})
+
+	It("should finalize once every report has finished", func() {
+		reports = append(reports, report("meter-report-2020-06-03", 3, marketplacev1alpha1.ReportConditionJobFinished,
+			total("app-b", "1")))
+
+		now := day(4).Add(time.Hour)
+		summary, err := summarize(instance, reports, now)
+		Expect(err).To(Succeed())
+
+		Expect(summary.totals).To(Equal([]marketplacev1alpha1.MeterTotal{
+			total("app-a", "1.5"),
+			total("app-b", "3.5"),
+		}))
+		Expect(summary.finalize).To(BeTrue())
+
+		Expect(summary.apply(instance, now)).To(BeTrue())
+		Expect(instance.IsFinalized()).To(BeTrue())
+		Expect(instance.Status.Conditions.IsTrueFor(marketplacev1alpha1.SummaryConditionTypeFinalized)).To(BeTrue())
+	})
+
+	It("should total a weekly report by the period it starts in", func() {
+		weekly := func(name string, d int, totals ...marketplacev1alpha1.MeterTotal) marketplacev1alpha1.MeterReport {
+			r := report(name, d, marketplacev1alpha1.ReportConditionJobFinished, totals...)
+			r.Spec.EndTime = metav1.NewTime(day(d + 7))
+			return r
+		}
+
+		reports = []marketplacev1alpha1.MeterReport{
+			weekly("meter-report-weekly-2020-05-29", -2, total("app-a", "7")),
+			weekly("meter-report-weekly-2020-06-03", 3, total("app-a", "3")),
+		}
+
+		summary, err := summarize(instance, reports, day(5))
+		Expect(err).To(Succeed())
+
+		Expect(summary.totals).To(Equal([]marketplacev1alpha1.MeterTotal{total("app-a", "3")}))
+		Expect(summary.reports).To(Equal([]string{"meter-report-weekly-2020-06-03"}))
+		Expect(summary.missingDays).To(BeEmpty())
+		Expect(summary.condition).To(Equal(marketplacev1alpha1.SummaryConditionPeriodOpen))
+		Expect(summary.finalize).To(BeFalse())
+
+		summary, err = summarize(instance, reports, day(10).Add(time.Hour))
+		Expect(err).To(Succeed())
+		Expect(summary.finalize).To(BeTrue())
+	})
+
+	It("should split days in the summary timezone", func() {
+		loc, err := time.LoadLocation("America/New_York")
+		Expect(err).To(Succeed())
+
+		instance.Spec.Timezone = "America/New_York"
+		instance.Spec.StartTime = metav1.NewTime(time.Date(2020, time.June, 1, 0, 0, 0, 0, loc))
+		instance.Spec.EndTime = metav1.NewTime(time.Date(2020, time.June, 3, 0, 0, 0, 0, loc))
+
+		summary, err := summarize(instance, nil, instance.Spec.EndTime.Add(time.Hour))
+		Expect(err).To(Succeed())
+		Expect(summary.missingDays).To(Equal([]string{"2020-06-01", "2020-06-02"}))
+	})
+})
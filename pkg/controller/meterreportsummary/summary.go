@@ -0,0 +1,242 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meterreportsummary
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	merrors "emperror.dev/errors"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
+)
+
+type reportState int
+
+const (
+	reportPending reportState = iota
+	reportFinished
+	reportErrored
+)
+
+// getReportState returns whether the report has finished, errored or is still
+// pending. A report that finished with query errors counts as errored but its
+// totals are still included.
+func getReportState(report *marketplacev1alpha1.MeterReport) reportState {
+	if report.Status.Conditions == nil {
+		return reportPending
+	}
+
+	cond := report.Status.Conditions.GetCondition(marketplacev1alpha1.ReportConditionTypeJobRunning)
+	if cond == nil {
+		return reportPending
+	}
+
+	switch cond.Reason {
+	case marketplacev1alpha1.ReportConditionReasonJobErrored:
+		return reportErrored
+	case marketplacev1alpha1.ReportConditionReasonJobFinished:
+		if len(report.Status.QueryErrorList) > 0 {
+			return reportErrored
+		}
+
+		for _, result := range report.Status.MeterDefinitionResults {
+			if len(result.Errors) > 0 {
+				return reportErrored
+			}
+		}
+
+		return reportFinished
+	default:
+		return reportPending
+	}
+}
+
+// summarize totals the scheduled reports that start inside the billing period
+// of the summary and checks each day of the period that has started by now for
+// missing, errored and pending reports. A report that crosses the end of the
+// period, like a weekly report, is totaled by the period it starts in and the
+// summary stays open until that report has ended.
+func summarize(
+	instance *marketplacev1alpha1.MeterReportSummary,
+	meterReports []marketplacev1alpha1.MeterReport,
+	now time.Time,
+) (*periodSummary, error) {
+	loc := time.UTC
+	if instance.Spec.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(instance.Spec.Timezone)
+		if err != nil {
+			return nil, merrors.Wrapf(err, "failed to load summary timezone %q", instance.Spec.Timezone)
+		}
+	}
+
+	finalizeAfter := defaultFinalizeAfter
+	if instance.Spec.FinalizeAfter != nil {
+		finalizeAfter = instance.Spec.FinalizeAfter.Duration
+	}
+
+	start := instance.Spec.StartTime.Time
+	end := instance.Spec.EndTime.Time
+
+	if !start.Before(end) {
+		return nil, merrors.Errorf("summary startTime %s must be before endTime %s", start, end)
+	}
+
+	// only scheduled reports overlapping the period count; a re-created report
+	// for the same window is only counted once
+	var reports []marketplacev1alpha1.MeterReport
+	seen := make(map[string]bool)
+	for _, report := range meterReports {
+		reportStart, reportEnd := report.Spec.StartTime.Time, report.Spec.EndTime.Time
+
+		if report.IsAdHoc() || !reportStart.Before(end) || !reportEnd.After(start) {
+			continue
+		}
+
+		key := strconv.FormatInt(reportStart.Unix(), 10) + "-" + strconv.FormatInt(reportEnd.Unix(), 10)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Spec.StartTime.Equal(&reports[j].Spec.StartTime) {
+			return reports[i].Name < reports[j].Name
+		}
+		return reports[i].Spec.StartTime.Before(&reports[j].Spec.StartTime)
+	})
+
+	summary := &periodSummary{}
+	sums := make(map[marketplacev1alpha1.MeterTotal]float64)
+	var keys []marketplacev1alpha1.MeterTotal
+
+	// the period closes once the last report it totals has ended
+	closeTime := end
+	reportsPending := false
+
+	for i := range reports {
+		report := &reports[i]
+
+		// reports that started in the previous period are totaled there
+		if report.Spec.StartTime.Time.Before(start) {
+			continue
+		}
+
+		if report.Spec.EndTime.Time.After(closeTime) {
+			closeTime = report.Spec.EndTime.Time
+		}
+
+		if getReportState(report) == reportPending {
+			reportsPending = true
+			continue
+		}
+
+		summary.reports = append(summary.reports, report.Name)
+
+		for _, total := range report.Status.MeterTotals {
+			value, err := strconv.ParseFloat(total.Value, 64)
+			if err != nil {
+				return nil, merrors.Wrapf(err, "failed to parse total of %s in report %s", total.Metric, report.Name)
+			}
+
+			key := total.Key()
+			if _, ok := sums[key]; !ok {
+				keys = append(keys, key)
+			}
+			sums[key] = sums[key] + value
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Less(keys[j])
+	})
+
+	for _, key := range keys {
+		key.Value = strconv.FormatFloat(sums[key], 'f', -1, 64)
+		summary.totals = append(summary.totals, key)
+	}
+
+	for day := utils.TruncateTime(start.In(loc), loc); day.Before(end) && !day.After(now); day = day.AddDate(0, 0, 1) {
+		dayStart, dayEnd := day, day.AddDate(0, 0, 1)
+		if dayStart.Before(start) {
+			dayStart = start
+		}
+		if dayEnd.After(end) {
+			dayEnd = end
+		}
+
+		name := day.Format(utils.DATE_FORMAT)
+		covered := dayStart
+		errored, pending := false, false
+
+		for i := range reports {
+			report := &reports[i]
+			reportStart, reportEnd := report.Spec.StartTime.Time, report.Spec.EndTime.Time
+
+			if !reportStart.Before(dayEnd) || !reportEnd.After(dayStart) {
+				continue
+			}
+
+			// reports are sorted by start so the day is covered while
+			// each report starts before the covered time
+			if !reportStart.After(covered) && reportEnd.After(covered) {
+				covered = reportEnd
+			}
+
+			switch getReportState(report) {
+			case reportErrored:
+				errored = true
+			case reportPending:
+				pending = true
+			}
+		}
+
+		if covered.Before(dayEnd) {
+			summary.missingDays = append(summary.missingDays, name)
+		}
+		if errored {
+			summary.erroredDays = append(summary.erroredDays, name)
+		}
+		if pending {
+			summary.pendingDays = append(summary.pendingDays, name)
+		}
+	}
+
+	deadline := closeTime.Add(finalizeAfter)
+
+	switch {
+	case now.Before(closeTime):
+		summary.condition = marketplacev1alpha1.SummaryConditionPeriodOpen
+		summary.requeueAfter = closeTime.Sub(now)
+	case !now.Before(deadline),
+		len(summary.pendingDays) == 0 && len(summary.missingDays) == 0 && !reportsPending:
+		summary.condition = marketplacev1alpha1.SummaryConditionFinalized
+		summary.finalize = true
+	default:
+		summary.condition = marketplacev1alpha1.SummaryConditionReportsPending
+		summary.requeueAfter = deadline.Sub(now)
+	}
+
+	if summary.requeueAfter > maxRequeueAfter {
+		summary.requeueAfter = maxRequeueAfter
+	}
+
+	return summary, nil
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -28,6 +29,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	MarketplaceV1alpha1() marketplacev1alpha1.MarketplaceV1alpha1Interface
+	MarketplaceV1beta1() marketplacev1beta1.MarketplaceV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -35,6 +37,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	marketplaceV1alpha1 *marketplacev1alpha1.MarketplaceV1alpha1Client
+	marketplaceV1beta1  *marketplacev1beta1.MarketplaceV1beta1Client
 }
 
 // MarketplaceV1alpha1 retrieves the MarketplaceV1alpha1Client
@@ -42,6 +45,11 @@ func (c *Clientset) MarketplaceV1alpha1() marketplacev1alpha1.MarketplaceV1alpha
 	return c.marketplaceV1alpha1
 }
 
+// MarketplaceV1beta1 retrieves the MarketplaceV1beta1Client
+func (c *Clientset) MarketplaceV1beta1() marketplacev1beta1.MarketplaceV1beta1Interface {
+	return c.marketplaceV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -67,6 +75,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.marketplaceV1beta1, err = marketplacev1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -80,6 +92,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.marketplaceV1alpha1 = marketplacev1alpha1.NewForConfigOrDie(c)
+	cs.marketplaceV1beta1 = marketplacev1beta1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -89,6 +102,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.marketplaceV1alpha1 = marketplacev1alpha1.New(c)
+	cs.marketplaceV1beta1 = marketplacev1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
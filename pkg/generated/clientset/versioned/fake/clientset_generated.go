@@ -20,6 +20,8 @@ import (
 	clientset "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
 	fakemarketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1/fake"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
+	fakemarketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -78,3 +80,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) MarketplaceV1alpha1() marketplacev1alpha1.MarketplaceV1alpha1Interface {
 	return &fakemarketplacev1alpha1.FakeMarketplaceV1alpha1{Fake: &c.Fake}
 }
+
+// MarketplaceV1beta1 retrieves the MarketplaceV1beta1Client
+func (c *Clientset) MarketplaceV1beta1() marketplacev1beta1.MarketplaceV1beta1Interface {
+	return &fakemarketplacev1beta1.FakeMarketplaceV1beta1{Fake: &c.Fake}
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	scheme "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterMeterDefinitionsGetter has a method to return a ClusterMeterDefinitionInterface.
+// A group's client should implement this interface.
+type ClusterMeterDefinitionsGetter interface {
+	ClusterMeterDefinitions() ClusterMeterDefinitionInterface
+}
+
+// ClusterMeterDefinitionInterface has methods to work with ClusterMeterDefinition resources.
+type ClusterMeterDefinitionInterface interface {
+	Create(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.CreateOptions) (*v1beta1.ClusterMeterDefinition, error)
+	Update(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (*v1beta1.ClusterMeterDefinition, error)
+	UpdateStatus(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (*v1beta1.ClusterMeterDefinition, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.ClusterMeterDefinition, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.ClusterMeterDefinitionList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.ClusterMeterDefinition, err error)
+	ClusterMeterDefinitionExpansion
+}
+
+// clusterMeterDefinitions implements ClusterMeterDefinitionInterface
+type clusterMeterDefinitions struct {
+	client rest.Interface
+}
+
+// newClusterMeterDefinitions returns a ClusterMeterDefinitions
+func newClusterMeterDefinitions(c *MarketplaceV1beta1Client) *clusterMeterDefinitions {
+	return &clusterMeterDefinitions{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterMeterDefinition, and returns the corresponding clusterMeterDefinition object, and an error if there is any.
+func (c *clusterMeterDefinitions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	result = &v1beta1.ClusterMeterDefinition{}
+	err = c.client.Get().
+		Resource("clustermeterdefinitions").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterMeterDefinitions that match those selectors.
+func (c *clusterMeterDefinitions) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ClusterMeterDefinitionList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.ClusterMeterDefinitionList{}
+	err = c.client.Get().
+		Resource("clustermeterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterMeterDefinitions.
+func (c *clusterMeterDefinitions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clustermeterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clusterMeterDefinition and creates it.  Returns the server's representation of the clusterMeterDefinition, and an error, if there is any.
+func (c *clusterMeterDefinitions) Create(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.CreateOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	result = &v1beta1.ClusterMeterDefinition{}
+	err = c.client.Post().
+		Resource("clustermeterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMeterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterMeterDefinition and updates it. Returns the server's representation of the clusterMeterDefinition, and an error, if there is any.
+func (c *clusterMeterDefinitions) Update(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	result = &v1beta1.ClusterMeterDefinition{}
+	err = c.client.Put().
+		Resource("clustermeterdefinitions").
+		Name(clusterMeterDefinition.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMeterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *clusterMeterDefinitions) UpdateStatus(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	result = &v1beta1.ClusterMeterDefinition{}
+	err = c.client.Put().
+		Resource("clustermeterdefinitions").
+		Name(clusterMeterDefinition.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMeterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterMeterDefinition and deletes it. Returns an error if one occurs.
+func (c *clusterMeterDefinitions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clustermeterdefinitions").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterMeterDefinitions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clustermeterdefinitions").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterMeterDefinition.
+func (c *clusterMeterDefinitions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.ClusterMeterDefinition, err error) {
+	result = &v1beta1.ClusterMeterDefinition{}
+	err = c.client.Patch(pt).
+		Resource("clustermeterdefinitions").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClusterMeterDefinitions implements ClusterMeterDefinitionInterface
+type FakeClusterMeterDefinitions struct {
+	Fake *FakeMarketplaceV1beta1
+}
+
+var clustermeterdefinitionsResource = schema.GroupVersionResource{Group: "marketplace.redhat.com", Version: "v1beta1", Resource: "clustermeterdefinitions"}
+
+var clustermeterdefinitionsKind = schema.GroupVersionKind{Group: "marketplace.redhat.com", Version: "v1beta1", Kind: "ClusterMeterDefinition"}
+
+// Get takes name of the clusterMeterDefinition, and returns the corresponding clusterMeterDefinition object, and an error if there is any.
+func (c *FakeClusterMeterDefinitions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clustermeterdefinitionsResource, name), &v1beta1.ClusterMeterDefinition{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterMeterDefinitions that match those selectors.
+func (c *FakeClusterMeterDefinitions) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ClusterMeterDefinitionList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clustermeterdefinitionsResource, clustermeterdefinitionsKind, opts), &v1beta1.ClusterMeterDefinitionList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.ClusterMeterDefinitionList{ListMeta: obj.(*v1beta1.ClusterMeterDefinitionList).ListMeta}
+	for _, item := range obj.(*v1beta1.ClusterMeterDefinitionList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterMeterDefinitions.
+func (c *FakeClusterMeterDefinitions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clustermeterdefinitionsResource, opts))
+}
+
+// Create takes the representation of a clusterMeterDefinition and creates it.  Returns the server's representation of the clusterMeterDefinition, and an error, if there is any.
+func (c *FakeClusterMeterDefinitions) Create(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.CreateOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clustermeterdefinitionsResource, clusterMeterDefinition), &v1beta1.ClusterMeterDefinition{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), err
+}
+
+// Update takes the representation of a clusterMeterDefinition and updates it. Returns the server's representation of the clusterMeterDefinition, and an error, if there is any.
+func (c *FakeClusterMeterDefinitions) Update(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (result *v1beta1.ClusterMeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clustermeterdefinitionsResource, clusterMeterDefinition), &v1beta1.ClusterMeterDefinition{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeClusterMeterDefinitions) UpdateStatus(ctx context.Context, clusterMeterDefinition *v1beta1.ClusterMeterDefinition, opts v1.UpdateOptions) (*v1beta1.ClusterMeterDefinition, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(clustermeterdefinitionsResource, "status", clusterMeterDefinition), &v1beta1.ClusterMeterDefinition{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), err
+}
+
+// Delete takes name of the clusterMeterDefinition and deletes it. Returns an error if one occurs.
+func (c *FakeClusterMeterDefinitions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(clustermeterdefinitionsResource, name), &v1beta1.ClusterMeterDefinition{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterMeterDefinitions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clustermeterdefinitionsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.ClusterMeterDefinitionList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterMeterDefinition.
+func (c *FakeClusterMeterDefinitions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.ClusterMeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clustermeterdefinitionsResource, name, pt, data, subresources...), &v1beta1.ClusterMeterDefinition{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), err
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeMeterDefinitions implements MeterDefinitionInterface
+type FakeMeterDefinitions struct {
+	Fake *FakeMarketplaceV1beta1
+	ns   string
+}
+
+var meterdefinitionsResource = schema.GroupVersionResource{Group: "marketplace.redhat.com", Version: "v1beta1", Resource: "meterdefinitions"}
+
+var meterdefinitionsKind = schema.GroupVersionKind{Group: "marketplace.redhat.com", Version: "v1beta1", Kind: "MeterDefinition"}
+
+// Get takes name of the meterDefinition, and returns the corresponding meterDefinition object, and an error if there is any.
+func (c *FakeMeterDefinitions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.MeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(meterdefinitionsResource, c.ns, name), &v1beta1.MeterDefinition{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.MeterDefinition), err
+}
+
+// List takes label and field selectors, and returns the list of MeterDefinitions that match those selectors.
+func (c *FakeMeterDefinitions) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.MeterDefinitionList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(meterdefinitionsResource, meterdefinitionsKind, c.ns, opts), &v1beta1.MeterDefinitionList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.MeterDefinitionList{ListMeta: obj.(*v1beta1.MeterDefinitionList).ListMeta}
+	for _, item := range obj.(*v1beta1.MeterDefinitionList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested meterDefinitions.
+func (c *FakeMeterDefinitions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(meterdefinitionsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a meterDefinition and creates it.  Returns the server's representation of the meterDefinition, and an error, if there is any.
+func (c *FakeMeterDefinitions) Create(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.CreateOptions) (result *v1beta1.MeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(meterdefinitionsResource, c.ns, meterDefinition), &v1beta1.MeterDefinition{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.MeterDefinition), err
+}
+
+// Update takes the representation of a meterDefinition and updates it. Returns the server's representation of the meterDefinition, and an error, if there is any.
+func (c *FakeMeterDefinitions) Update(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (result *v1beta1.MeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(meterdefinitionsResource, c.ns, meterDefinition), &v1beta1.MeterDefinition{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.MeterDefinition), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeMeterDefinitions) UpdateStatus(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (*v1beta1.MeterDefinition, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(meterdefinitionsResource, "status", c.ns, meterDefinition), &v1beta1.MeterDefinition{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.MeterDefinition), err
+}
+
+// Delete takes name of the meterDefinition and deletes it. Returns an error if one occurs.
+func (c *FakeMeterDefinitions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(meterdefinitionsResource, c.ns, name), &v1beta1.MeterDefinition{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeMeterDefinitions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(meterdefinitionsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.MeterDefinitionList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched meterDefinition.
+func (c *FakeMeterDefinitions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.MeterDefinition, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(meterdefinitionsResource, c.ns, name, pt, data, subresources...), &v1beta1.MeterDefinition{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.MeterDefinition), err
+}
@@ -0,0 +1,193 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	scheme "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// MeterDefinitionsGetter has a method to return a MeterDefinitionInterface.
+// A group's client should implement this interface.
+type MeterDefinitionsGetter interface {
+	MeterDefinitions(namespace string) MeterDefinitionInterface
+}
+
+// MeterDefinitionInterface has methods to work with MeterDefinition resources.
+type MeterDefinitionInterface interface {
+	Create(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.CreateOptions) (*v1beta1.MeterDefinition, error)
+	Update(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (*v1beta1.MeterDefinition, error)
+	UpdateStatus(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (*v1beta1.MeterDefinition, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.MeterDefinition, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.MeterDefinitionList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.MeterDefinition, err error)
+	MeterDefinitionExpansion
+}
+
+// meterDefinitions implements MeterDefinitionInterface
+type meterDefinitions struct {
+	client rest.Interface
+	ns     string
+}
+
+// newMeterDefinitions returns a MeterDefinitions
+func newMeterDefinitions(c *MarketplaceV1beta1Client, namespace string) *meterDefinitions {
+	return &meterDefinitions{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the meterDefinition, and returns the corresponding meterDefinition object, and an error if there is any.
+func (c *meterDefinitions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.MeterDefinition, err error) {
+	result = &v1beta1.MeterDefinition{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of MeterDefinitions that match those selectors.
+func (c *meterDefinitions) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.MeterDefinitionList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.MeterDefinitionList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested meterDefinitions.
+func (c *meterDefinitions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a meterDefinition and creates it.  Returns the server's representation of the meterDefinition, and an error, if there is any.
+func (c *meterDefinitions) Create(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.CreateOptions) (result *v1beta1.MeterDefinition, err error) {
+	result = &v1beta1.MeterDefinition{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(meterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a meterDefinition and updates it. Returns the server's representation of the meterDefinition, and an error, if there is any.
+func (c *meterDefinitions) Update(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (result *v1beta1.MeterDefinition, err error) {
+	result = &v1beta1.MeterDefinition{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		Name(meterDefinition.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(meterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *meterDefinitions) UpdateStatus(ctx context.Context, meterDefinition *v1beta1.MeterDefinition, opts v1.UpdateOptions) (result *v1beta1.MeterDefinition, err error) {
+	result = &v1beta1.MeterDefinition{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		Name(meterDefinition.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(meterDefinition).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the meterDefinition and deletes it. Returns an error if one occurs.
+func (c *meterDefinitions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *meterDefinitions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched meterDefinition.
+func (c *meterDefinitions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.MeterDefinition, err error) {
+	result = &v1beta1.MeterDefinition{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("meterdefinitions").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -54,6 +55,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("meterdefinitions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Marketplace().V1alpha1().MeterDefinitions().Informer()}, nil
 
+		// Group=marketplace.redhat.com, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("clustermeterdefinitions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Marketplace().V1beta1().ClusterMeterDefinitions().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("meterdefinitions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Marketplace().V1beta1().MeterDefinitions().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -0,0 +1,87 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	time "time"
+
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	versioned "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/informers/externalversions/internalinterfaces"
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/listers/marketplace/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ClusterMeterDefinitionInformer provides access to a shared informer and lister for
+// ClusterMeterDefinitions.
+type ClusterMeterDefinitionInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1beta1.ClusterMeterDefinitionLister
+}
+
+type clusterMeterDefinitionInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewClusterMeterDefinitionInformer constructs a new informer for ClusterMeterDefinition type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewClusterMeterDefinitionInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredClusterMeterDefinitionInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredClusterMeterDefinitionInformer constructs a new informer for ClusterMeterDefinition type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredClusterMeterDefinitionInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MarketplaceV1beta1().ClusterMeterDefinitions().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MarketplaceV1beta1().ClusterMeterDefinitions().Watch(context.TODO(), options)
+			},
+		},
+		&marketplacev1beta1.ClusterMeterDefinition{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *clusterMeterDefinitionInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredClusterMeterDefinitionInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *clusterMeterDefinitionInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&marketplacev1beta1.ClusterMeterDefinition{}, f.defaultInformer)
+}
+
+func (f *clusterMeterDefinitionInformer) Lister() v1beta1.ClusterMeterDefinitionLister {
+	return v1beta1.NewClusterMeterDefinitionLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	internalinterfaces "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// ClusterMeterDefinitions returns a ClusterMeterDefinitionInformer.
+	ClusterMeterDefinitions() ClusterMeterDefinitionInformer
+	// MeterDefinitions returns a MeterDefinitionInformer.
+	MeterDefinitions() MeterDefinitionInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ClusterMeterDefinitions returns a ClusterMeterDefinitionInformer.
+func (v *version) ClusterMeterDefinitions() ClusterMeterDefinitionInformer {
+	return &clusterMeterDefinitionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// MeterDefinitions returns a MeterDefinitionInformer.
+func (v *version) MeterDefinitions() MeterDefinitionInformer {
+	return &meterDefinitionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The redhat-marketplace-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterMeterDefinitionLister helps list ClusterMeterDefinitions.
+type ClusterMeterDefinitionLister interface {
+	// List lists all ClusterMeterDefinitions in the indexer.
+	List(selector labels.Selector) (ret []*v1beta1.ClusterMeterDefinition, err error)
+	// Get retrieves the ClusterMeterDefinition from the index for a given name.
+	Get(name string) (*v1beta1.ClusterMeterDefinition, error)
+	ClusterMeterDefinitionListerExpansion
+}
+
+// clusterMeterDefinitionLister implements the ClusterMeterDefinitionLister interface.
+type clusterMeterDefinitionLister struct {
+	indexer cache.Indexer
+}
+
+// NewClusterMeterDefinitionLister returns a new ClusterMeterDefinitionLister.
+func NewClusterMeterDefinitionLister(indexer cache.Indexer) ClusterMeterDefinitionLister {
+	return &clusterMeterDefinitionLister{indexer: indexer}
+}
+
+// List lists all ClusterMeterDefinitions in the indexer.
+func (s *clusterMeterDefinitionLister) List(selector labels.Selector) (ret []*v1beta1.ClusterMeterDefinition, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.ClusterMeterDefinition))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClusterMeterDefinition from the index for a given name.
+func (s *clusterMeterDefinitionLister) Get(name string) (*v1beta1.ClusterMeterDefinition, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("clustermeterdefinition"), name)
+	}
+	return obj.(*v1beta1.ClusterMeterDefinition), nil
+}
@@ -14,9 +14,9 @@
 
 // Code generated for package manifests by go-bindata DO NOT EDIT. (@generated)
 // sources:
-// ../../assets/metric-state/deployment.yaml
 // ../../assets/metric-state/service-monitor.yaml
 // ../../assets/metric-state/service.yaml
+// ../../assets/metric-state/statefulset.yaml
 // ../../assets/prometheus/additional-scrape-configs.yaml
 // ../../assets/prometheus/htpasswd-secret.yaml
 // ../../assets/prometheus/kube-rbac-proxy-secret.yaml
@@ -113,62 +113,62 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _assetsMetricStateDeploymentYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x57\x4d\x6f\xdb\x46\x10\xbd\xeb\x57\xec\x2d\x97\xae\x28\x2a\x70\xeb\x2c\xe0\x83\x2b\xab\x75\x01\xdb\x31\x2a\xa3\x3d\x0a\xa3\xe5\x48\x5a\x68\xbf\x32\x3b\x54\x4c\x14\xfd\xef\x05\x4d\x39\xa6\x28\xc9\x8a\x90\xc2\x40\x80\xec\xc9\xde\x7d\x33\xf3\xf8\x66\xf6\x89\x84\x68\xfe\x42\x4a\x26\x78\x25\x20\xc6\x94\xad\xf3\xde\xca\xf8\x42\x89\x2b\x8c\x36\x54\x0e\x3d\xf7\x1c\x32\x14\xc0\xa0\x7a\x42\x78\x70\xa8\x04\x2d\x9d\x74\xc8\x64\xb4\x4c\x0c\x8c\x3d\x21\x2c\xcc\xd0\xa6\x1a\x22\xea\x4c\xfd\x55\x39\x43\xf2\xc8\x98\xfa\x26\x64\x3a\xb8\x18\x3c\x7a\x56\x42\x07\xcf\x14\xac\x45\x3a\x80\x3d\x50\x22\x45\xd4\x75\x7a\xc2\x68\x8d\x86\xa4\x44\xde\x13\x22\xa1\x45\xcd\x81\x9a\xc2\x0e\x58\x2f\x6f\x5a\x4c\x4e\xe3\x72\x02\x1b\x21\x18\x5d\xb4\xc0\xb8\xa9\xdc\xd2\xa8\x5e\x76\x8b\xc4\xa9\x34\x4e\x22\x22\xc4\xb3\x34\xf5\xaa\x73\x81\xf1\x48\xad\xe2\x72\xd3\xb6\x9d\xc0\x66\x19\x07\x8b\x23\xa7\xf7\xa5\xb5\xf7\xc1\x1a\x5d\x29\x71\x69\x3f\x43\x95\x5a\x08\xc2\x14\x4a\xd2\xd8\xaa\xd8\x6c\x7f\x2a\x31\x71\x67\x57\x08\x1d\x4b\x25\xf2\xc1\xc0\x75\xf6\x1d\xba\x40\x95\x12\xf9\xd9\xe0\xd6\xb4\xce\x12\xea\x92\x0c\x57\xa3\xe0\x19\x1f\x59\x89\x7f\xfe\x6d\x9d\x32\x92\x33\x1e\xd8\x04\x7f\x8b\x29\xd5\x5c\x37\x3c\x7f\x03\x6b\x67\xa0\x57\x0f\xe1\x26\x2c\xd2\x47\x3f\x26\x0a\xd4\x8a\x8c\x81\xba\xe4\xe4\x8b\x7c\xf7\x81\x58\x89\xf3\xc1\xf9\xa0\x43\xb3\x91\xf2\x33\xce\x8e\x46\xe6\x7b\x23\x1b\x99\xdb\xfa\x01\x2d\xba\x3c\x5a\xad\xdb\x73\x2a\xa4\xb4\x61\xc1\x21\x71\x81\x44\x3b\x67\x4f\x82\xa1\xb4\x26\x31\x7a\x09\x45\x41\x98\xd2\x85\xfa\x30\xf8\x30\xdc\xc1\xb2\x4d\x52\x9b\xb8\x44\x92\xa9\x34\x8c\xe9\xe2\xe1\x66\x32\x1d\x8f\xae\xae\xc7\xd3\x3f\x27\x97\xd3\xbf\xff\x78\xb8\x9e\x5e\x8e\x27\xd3\x7c\x78\x3e\xfd\x7d\x74\x3b\x9d\x5c\x5f\x0e\xcf\x7e\xfe\xe9\x05\x35\x1e\x5d\x1d\xc1\xed\xe4\x19\xfd\x3a\xfa\xaa\x3c\x7b\x71\xaf\x64\xdb\x79\xba\x32\x26\x26\x04\x77\xb1\x64\x8e\x2a\xcb\xf2\xe1\x2f\xfd\x41\x7f\xd0\xcf\x55\xdd\xd8\x6c\xbf\x1a\x48\x2c\xe7\xc6\xe2\x45\x86\xac\x33\xb6\x29\x8b\x64\xd6\xc0\x58\xff\xdd\xd7\xc4\x7b\xc3\x36\x18\xb9\xc2\xea\x95\xe8\x15\x56\x07\x49\x4a\x0d\xad\x48\x1d\xfc\xdc\x2c\x1c\xc4\x94\x25\xa4\xb5\xf1\x8b\x86\x99\x06\x39\x2b\x7d\x61\xb1\xd9\xd6\x28\x35\x74\x48\x6d\xee\xf3\xa7\x12\xaa\xc6\x68\x08\x43\xca\x6a\x37\x91\x34\x03\x2d\x23\x85\xc7\x4a\xad\x07\xfd\xb3\x7e\x7b\xb8\x9b\xf1\xec\xc0\x64\x7e\xe2\x8d\xd9\x99\xb2\xe7\xc4\x75\x0b\xbe\xdd\x35\x0e\x99\xc6\xf0\x6d\x3c\x63\x1d\x6c\xe9\xf0\x36\x94\x7e\x57\x08\x57\xef\xde\x03\x2f\x95\xe8\xf6\x7e\xaf\x20\x5d\x2b\xaf\xc7\xa8\x03\x24\x84\xe2\xa3\xb7\x95\x12\x73\xb0\x09\x8f\x14\x3c\x3a\x32\x7b\x69\xb4\xa1\xe9\x20\xf6\x10\x93\xff\xd7\x9f\xde\xff\xf0\xa7\x17\x7f\xca\xbf\x47\x7f\x4a\x6f\x6e\x50\xc3\xd3\x0d\xea\xfd\x61\x83\x92\xbb\x3f\xcf\x3f\x8c\xea\x8d\x8c\xea\xb0\xfb\x7c\xbb\x53\xf9\x50\xe0\x64\xeb\x5b\xa1\x5e\x33\x64\xe8\xbc\x66\x87\xa4\x84\x35\xbe\x7c\xfc\x02\xaa\x43\x25\x05\x8b\x1d\xa4\x83\xc4\x48\x4a\xbc\x7b\xb7\x81\x46\x32\xe1\xa9\x97\x16\x52\xba\x6b\x28\x57\x89\xd1\x49\x6d\xcb\x1a\x2b\x35\x19\x36\x1a\x6c\xef\x58\xf3\x37\x57\xe7\x52\xeb\x5a\xba\x26\x17\x61\xb1\x04\x96\x0e\x68\x85\x1c\x2d\x68\x94\x21\x22\x01\x7f\x69\x3c\x07\x5b\xff\x6f\x82\xdf\x7a\xf9\xc7\xf9\x1c\x35\x2b\x71\x17\x26\x7a\x89\x45\xb9\x25\xd9\x0a\x2b\x75\xe4\x11\x5b\xe8\xe7\x82\x4a\x8c\x1f\x4d\xe2\xe7\x31\x68\xa6\x6d\xcf\x17\xc7\x91\xd1\x49\xa8\x09\x79\x7b\x3e\x9b\xbd\xbb\xe3\xe1\x4f\x37\x7b\x6e\x16\xb7\x10\xb7\x33\x7c\xed\xb4\xbc\x8e\xfb\x2f\x00\x00\xff\xff\x7a\x1c\x9d\xb2\x1b\x0f\x00\x00")
+var _assetsMetricStateServiceMonitorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x53\x3d\x8f\xd4\x30\x10\xed\xf7\x57\xb8\xb8\x82\x0f\x79\xc3\x51\x41\x5a\x24\x2a\xa0\xe1\x44\x83\xd0\x6a\x76\xf2\x48\xcc\xc6\x1e\x6b\x3c\x59\xf1\xf3\x91\x1d\x73\xe8\x8a\x43\x3a\x89\x06\xa5\x71\xc6\x6f\xec\xf7\xde\x3c\x53\x0e\x5f\xa0\x25\x48\x1a\x5d\x94\x14\x4c\x34\xa4\xf9\xc8\xa2\x90\x72\x64\x89\xc3\xf5\xf6\x70\x09\x69\x1a\xdd\x67\xe8\x35\x30\x3e\xee\xa8\x43\x84\xd1\x44\x46\xe3\xc1\xb9\x95\xce\x58\x4b\x5d\x39\x47\x39\x1f\x2f\xdb\x19\x9a\x60\x28\xc7\x20\x03\x4b\xcc\x92\x90\x6c\x74\x2c\xc9\x54\xd6\x15\xfa\x08\x36\x51\xc4\xe8\x74\x89\x3e\xc2\x34\xb0\x2f\x46\x86\x83\x73\x8f\x6c\x94\x0c\xae\xf7\x22\x4d\x59\x42\xb2\x46\xc2\xbb\x33\x48\xa1\x77\x72\x41\x7a\x1f\x56\x8c\x6e\xb8\x92\x0e\xba\xa5\xa1\x80\x15\x56\x86\x87\xd7\x96\x5d\x1b\x31\xcb\x96\x6c\xb0\xda\xd8\x18\x2e\x92\x44\x3f\xec\xf2\x9c\xe9\x56\xa9\x38\x17\x92\x41\xaf\xb4\x8e\xee\x75\x6c\x85\x2c\x6a\xa3\x5b\xcc\x72\x69\xff\x85\x17\x44\x3c\xac\x28\x65\xdc\x85\x08\xd9\xec\xbe\xcf\xd6\xf2\x4e\xd2\xf7\x30\x57\xda\xf5\x63\xea\x7c\x61\x3c\x64\x95\x08\x5b\xb0\x95\x81\x1b\x2a\x52\x2e\x3b\xd7\x34\x7b\x86\x5a\xf1\x4c\xfe\xbc\xa5\x69\xc5\x6f\x0d\x9e\xe9\xc8\x6a\xfd\xbc\x5a\x84\x7e\x6a\xe6\xed\x6b\x5f\x9d\xf4\x8a\xbc\x12\x63\xf2\x64\x5e\xb7\x64\x21\xe2\xdf\x1a\xf7\x37\x8b\xfa\x08\x77\x63\x14\x2d\x3d\x21\xcd\x3d\x40\xde\x11\x5b\x0b\x64\x27\xd9\xa5\x28\x66\xfc\x1c\xdd\xf1\x85\x7f\xf6\xf5\x95\x7f\xfb\xed\xe5\xf3\x9b\xfb\x9d\x86\x8b\x2d\x62\x37\xb7\xbd\x5a\x64\x53\x46\x9f\x5d\xaf\x79\x77\x3a\xd5\xe0\x9e\xfe\xa8\x38\x65\x99\x4e\xd5\x93\x0e\x31\xd2\x19\xd6\xda\x46\x57\x16\xd2\xe9\x7f\x9e\xe8\x0f\x39\x77\x29\x97\x37\xc5\x53\xce\x87\x7a\xc2\x0a\x36\xd1\x9d\x61\x24\xe3\xe5\xa1\x4b\x4f\x79\xc2\x4f\x78\xc4\xbf\x06\x00\xb3\x5b\x7a\x7e\x6c\x04\x00\x00")
 
-func assetsMetricStateDeploymentYamlBytes() ([]byte, error) {
+func assetsMetricStateServiceMonitorYamlBytes() ([]byte, error) {
 	return bindataRead(
-		_assetsMetricStateDeploymentYaml,
-		"assets/metric-state/deployment.yaml",
+		_assetsMetricStateServiceMonitorYaml,
+		"assets/metric-state/service-monitor.yaml",
 	)
 }
 
-func assetsMetricStateDeploymentYaml() (*asset, error) {
-	bytes, err := assetsMetricStateDeploymentYamlBytes()
+func assetsMetricStateServiceMonitorYaml() (*asset, error) {
+	bytes, err := assetsMetricStateServiceMonitorYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/metric-state/deployment.yaml", size: 3867, mode: os.FileMode(420), modTime: time.Unix(1597390676, 0)}
+	info := bindataFileInfo{name: "assets/metric-state/service-monitor.yaml", size: 1132, mode: os.FileMode(420), modTime: time.Unix(1792428248, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsMetricStateServiceMonitorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x53\x4d\x8f\xd4\x30\x0c\xbd\xcf\xaf\xf0\x1f\x48\x2b\x38\xa1\x5e\x91\x38\x2d\x5c\x58\x71\x77\xdd\xc7\x34\x4c\x62\x47\x8e\x3b\xbf\x1f\xb5\x1d\xd0\x72\x58\xa4\x95\xb8\x70\x73\x5e\xfc\xf1\xfc\x92\xc7\x2d\x7f\x83\xf7\x6c\x3a\x51\x35\xcd\x61\x9e\xf5\x3a\x88\x39\xac\x0f\x62\x75\xbc\xbf\xbb\xdc\xb2\x2e\x13\x7d\x85\xdf\xb3\xe0\xf3\x99\x75\xa9\x08\x5e\x38\x78\xba\x10\x15\x9e\x51\xfa\x1e\x11\x71\x6b\xc3\x6d\x9b\xe1\x8a\x40\x1f\xb2\x8d\x62\xb5\x99\x42\x63\x22\x31\x0d\xb7\x52\xe0\xaf\xe4\x2a\x57\x4c\xe4\x6b\x4d\x15\xe1\x59\x52\x0f\x0e\x5c\x88\x5e\xb9\xe8\x0d\xb2\xcf\x85\x2e\xcd\xb2\xc6\x41\x22\xd1\x0c\x76\xf8\xb3\xdd\xa0\x9f\x72\xc1\x44\xe3\x9d\x7d\xf4\x4d\xc7\x0e\x71\x44\x1f\xff\x1c\xdb\xcf\xdd\x58\xc4\x36\x8d\x31\xf6\xc2\x83\xe1\x6a\x6a\xfe\x74\xae\x47\xe1\x1b\x0e\x34\x6b\xc0\xef\x5c\x26\x7a\x5f\x0f\xa0\x99\xc7\x44\x6b\x44\xeb\xc7\xb9\xcb\x8a\x9d\xef\x4b\xc4\xb9\xe1\x39\x57\xd8\x16\xbf\xeb\xa2\xf4\x8f\xa6\xdf\xf3\xf5\xd4\x8e\x48\xf8\xc1\x17\x21\x63\x73\xab\x88\x15\x5b\x1f\xe5\xc8\xaa\xdc\xfa\xc9\x55\xaf\x49\xe0\xd1\x93\x70\x9a\x37\x5d\x0a\x7e\xed\x90\x84\x07\xf1\x78\xf4\xdb\x41\xf8\x97\x43\xbc\x33\x4e\xbb\x92\xc9\xd1\x0a\x0b\x96\xc4\x91\x7c\xd3\xc8\x15\xff\x56\xb8\xbf\x49\xf4\x78\xc2\xff\x5a\xaa\x1f\x36\x1f\xdf\x62\xa2\xdb\x87\x9e\xb8\xb5\xcb\xde\xa1\x40\xc2\xfc\x64\x58\x39\x64\x7d\x7a\xe1\x8c\xb7\x79\xe3\x0d\xee\xf8\x19\x00\x00\xff\xff\x74\xfb\x99\x7d\xc5\x03\x00\x00")
+var _assetsMetricStateServiceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x91\xbd\x6e\x02\x31\x10\x84\x7b\x3f\xc5\xbe\x80\x2f\x7f\x15\xee\xa2\x54\x74\x48\x91\xd2\x1b\x33\x80\x85\x6f\x6d\x79\x07\x24\xde\x3e\x3a\x38\x29\x89\x92\x2b\x53\x7a\x76\x66\x3e\xdb\x1b\x5b\xfe\x40\xb7\x5c\x35\xc8\xe5\xc9\x9d\xb2\xee\x82\xbc\xa3\x5f\x72\x82\x1b\xc1\xb8\x8b\x8c\xc1\x89\x44\xd5\xca\xc8\x5c\xd5\xa6\xa3\x88\xdd\x4d\xc3\x16\x8c\x43\x6d\x50\x3b\xe6\x3d\x87\x5c\x1f\x6e\x13\x3d\xf8\x84\x4e\x6f\x48\x1d\xf4\x1a\x47\x04\xe9\xc7\xd1\x8f\x60\xcf\xc9\x1b\x23\xe1\x59\xcc\x89\x94\xb8\x45\x99\x6b\x63\x6b\xc3\xe9\xbc\x45\x57\x10\x36\xd5\xa5\x3a\xb6\xaa\x50\x06\x49\x55\xd9\x6b\x29\xe8\x0b\xde\xbf\x31\x4e\x64\x81\x3f\x3f\xc2\x59\x43\x9a\xf8\xad\x76\xce\x17\xf1\x73\xe6\x48\x36\xbb\x29\xf7\x71\x90\xd5\xe3\xea\x79\x16\x18\xfb\x01\xdc\xdc\xe4\x2f\xe3\x8f\xe8\x0c\xfc\x55\xf1\xb2\x54\xf1\x2d\x60\x28\x48\xac\xfd\x5f\xbf\xc6\x60\xd3\xfe\x5f\xf7\xfb\xac\x99\xd7\x20\x6f\x25\x43\xb9\xde\x38\x11\x5e\x1b\x26\xe1\x6c\x44\x5f\x6f\xdc\x67\x00\x00\x00\xff\xff\xe0\x0f\xf0\x12\x2f\x02\x00\x00")
 
-func assetsMetricStateServiceMonitorYamlBytes() ([]byte, error) {
+func assetsMetricStateServiceYamlBytes() ([]byte, error) {
 	return bindataRead(
-		_assetsMetricStateServiceMonitorYaml,
-		"assets/metric-state/service-monitor.yaml",
+		_assetsMetricStateServiceYaml,
+		"assets/metric-state/service.yaml",
 	)
 }
 
-func assetsMetricStateServiceMonitorYaml() (*asset, error) {
-	bytes, err := assetsMetricStateServiceMonitorYamlBytes()
+func assetsMetricStateServiceYaml() (*asset, error) {
+	bytes, err := assetsMetricStateServiceYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/metric-state/service-monitor.yaml", size: 965, mode: os.FileMode(420), modTime: time.Unix(1597390676, 0)}
+	info := bindataFileInfo{name: "assets/metric-state/service.yaml", size: 559, mode: os.FileMode(420), modTime: time.Unix(1597390676, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsMetricStateServiceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x91\xbd\x6e\x02\x31\x10\x84\x7b\x3f\xc5\xbe\x80\x2f\x7f\x15\xee\xa2\x54\x74\x48\x91\xd2\x1b\x33\x80\x85\x6f\x6d\x79\x07\x24\xde\x3e\x3a\x38\x29\x89\x92\x2b\x53\x7a\x76\x66\x3e\xdb\x1b\x5b\xfe\x40\xb7\x5c\x35\xc8\xe5\xc9\x9d\xb2\xee\x82\xbc\xa3\x5f\x72\x82\x1b\xc1\xb8\x8b\x8c\xc1\x89\x44\xd5\xca\xc8\x5c\xd5\xa6\xa3\x88\xdd\x4d\xc3\x16\x8c\x43\x6d\x50\x3b\xe6\x3d\x87\x5c\x1f\x6e\x13\x3d\xf8\x84\x4e\x6f\x48\x1d\xf4\x1a\x47\x04\xe9\xc7\xd1\x8f\x60\xcf\xc9\x1b\x23\xe1\x59\xcc\x89\x94\xb8\x45\x99\x6b\x63\x6b\xc3\xe9\xbc\x45\x57\x10\x36\xd5\xa5\x3a\xb6\xaa\x50\x06\x49\x55\xd9\x6b\x29\xe8\x0b\xde\xbf\x31\x4e\x64\x81\x3f\x3f\xc2\x59\x43\x9a\xf8\xad\x76\xce\x17\xf1\x73\xe6\x48\x36\xbb\x29\xf7\x71\x90\xd5\xe3\xea\x79\x16\x18\xfb\x01\xdc\xdc\xe4\x2f\xe3\x8f\xe8\x0c\xfc\x55\xf1\xb2\x54\xf1\x2d\x60\x28\x48\xac\xfd\x5f\xbf\xc6\x60\xd3\xfe\x5f\xf7\xfb\xac\x99\xd7\x20\x6f\x25\x43\xb9\xde\x38\x11\x5e\x1b\x26\xe1\x6c\x44\x5f\x6f\xdc\x67\x00\x00\x00\xff\xff\xe0\x0f\xf0\x12\x2f\x02\x00\x00")
+var _assetsMetricStateStatefulsetYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x57\x5f\x6f\x22\x37\x10\x7f\xe7\x53\xf8\xa1\xd2\xb5\x52\xcd\xb2\x9c\xd2\xe6\x2c\xe5\x81\x12\xee\x52\x29\x24\xe8\x88\xda\x47\x34\x78\x07\xb0\xf0\xda\x3e\x7b\x96\xcb\xb6\xea\x77\xaf\x0c\x4b\xb2\x2c\x10\x8e\xb6\x77\xd5\x49\xa7\xe5\x21\xf1\xfc\x66\xe6\xe7\xf9\x2b\x83\x53\xbf\xa1\x0f\xca\x1a\xc1\xc0\xb9\x90\xac\xd2\xd6\x52\x99\x4c\xb0\x31\x01\xe1\xac\xd0\x63\xa4\x56\x8e\x04\x19\x10\x88\x16\x63\x06\x72\x14\xcc\x2f\x72\x9e\x23\x79\x25\x79\x88\xc0\x16\x63\x1a\xa6\xa8\x43\x84\xb0\x68\xaa\xbd\x2c\xa6\xe8\x0d\x12\x86\xb6\xb2\x89\xb4\xb9\xb3\x06\x0d\x09\x26\xad\x21\x6f\xb5\x46\x7f\x04\x7b\xc4\x45\x70\x28\xa3\x79\x8f\x4e\x2b\x09\x41\xb0\xb4\xc5\x58\x40\xbf\x52\x12\xef\x0e\xea\xf0\x4a\xda\x62\xcc\xd9\x6c\x08\x06\xe6\x98\xa3\xa1\x91\xd5\x4a\x96\x82\x8d\xc0\x83\xd6\xa8\xd7\x76\x34\x4a\xb2\x3e\x7a\x60\x2c\x07\x92\x8b\xdb\xda\x8d\xce\xbb\xd3\x19\xb7\x62\x8c\x30\x77\x1a\x08\x2b\xcf\xb5\x58\x33\xb6\x1b\xd6\xf3\x69\x9c\x45\x84\xb1\x6d\x88\xe3\x17\xd3\x04\xca\xa0\xaf\x39\xe7\x55\xfa\xf7\x14\x37\x3f\x95\xc3\xfc\x84\x74\x54\x68\xbd\x8d\x7e\x4f\x7f\x84\x32\xd4\x10\x1e\x83\x2d\xbc\xc4\x9a\xc7\xf8\xf3\xf8\xa1\xc0\x40\x8d\x53\xc6\xa4\x2b\x04\x4b\x3b\x9d\xbc\x71\x9e\x63\x6e\x7d\x29\x58\x7a\xd1\x19\xaa\x9a\x2c\xa0\x2c\xbc\xa2\xb2\x6f\x0d\xe1\x23\x09\xf6\xe7\x5f\x35\x29\xa1\xcf\x95\x01\x52\xd6\x0c\x31\x84\xc8\xb5\xe2\xf9\x16\xb4\x9e\x82\x5c\x3e\xd8\x5b\x3b\x0f\xf7\x66\xe0\xbd\x7d\x0e\x70\x2c\x2d\xdf\x24\xc7\x9f\xc3\x37\xb2\x9e\x04\xbb\xec\x5c\x76\x76\x10\xdb\x4e\xfa\x88\xd3\x93\x9a\xe9\x41\xcd\x4d\x12\x76\xe3\x07\x99\x32\x18\xc2\xc8\xdb\x69\x55\x4f\xdb\x6f\x41\xe4\xde\x21\xed\x1e\x32\xe6\x80\x16\x82\x25\x51\xb3\xfc\xa3\x29\x5b\x33\x6f\x12\x54\x46\x91\x02\x7d\x8d\x1a\xca\x31\x4a\x6b\xb2\x20\xd8\xc5\x0e\xc4\xa1\x57\x36\x7b\x12\xa6\xf5\x9b\xa3\x59\xed\x72\xd8\xd6\xd4\xe8\xfe\x7a\x72\xd7\x1b\x0e\x76\x84\x8c\xad\x40\x17\xf8\xd6\xdb\xbc\xc9\x9c\xb1\x99\x42\x9d\xbd\xc7\xd9\xbe\xa4\x92\x8d\xd6\x97\xdb\x4e\xaf\x76\xf4\xf3\xa2\xeb\xf1\xa8\xd7\xff\xcc\xfe\x83\x03\x59\x27\x01\x7e\xbe\x57\x3a\x9c\x3b\x9b\x5d\x7d\xf7\xfd\x96\xd6\x0f\x87\xe4\xfc\xc9\x5a\x0d\xb9\xbe\xc0\x33\x9c\x1f\x31\xaf\xed\x9c\x6c\xa0\x0c\x7d\xbd\x8c\x23\x9e\xf3\x75\x93\x20\xd7\x2a\x10\x1a\x0e\x59\xe6\x31\x84\x2b\xf1\xa6\xf3\xa6\xbb\x87\x25\x1d\xb8\x54\x6e\x81\x9e\x87\x42\x11\x86\xab\x87\xdb\xf1\x64\xd0\xbf\xbe\x19\x4c\xde\x8f\x7b\x93\xdf\x7f\x7d\xb8\x99\xf4\x06\xe3\x49\xda\xbd\x9c\xbc\xeb\x0f\x27\xe3\x9b\x5e\xf7\xe2\xa7\x1f\x9f\x51\x83\xfe\xf5\x09\xdc\x9e\x9d\xfe\x2f\xfd\x4f\xb2\x73\x10\xf7\x82\xb5\xbd\xdb\x15\x2e\x90\x47\xc8\xaf\x62\xeb\x88\x24\x49\xbb\x3f\xb7\x3b\xed\x4e\x3b\x15\xb1\x99\x93\xc3\xd1\x40\x4f\x7c\xa6\x34\x5e\x25\x48\x32\x21\x1d\x12\xe7\xd5\x0a\x08\xe3\xdf\x6d\xe9\xe9\xa0\x5a\x85\xe1\x4b\x2c\x5f\xd0\x5e\x62\x79\x94\x24\x97\x50\xd3\x94\xd6\xcc\xd4\x3c\x07\x17\x92\xf5\x06\x34\xf3\x0d\x33\x09\x7c\x5a\x98\x4c\x63\x52\x2d\x46\x2e\xa1\x41\xaa\x9a\xe1\x1f\x0a\x28\xe3\xb2\x90\xd6\xa3\x0d\x49\xdc\x20\xdc\x4f\x41\x72\xe7\xed\x63\x29\x56\x9d\xf6\x45\xbb\xde\xd6\xb1\x1a\x05\x6b\xc0\x78\x7a\xe6\x94\xdc\xab\xb2\xad\xe1\x98\x82\x7f\xbf\x29\x8e\x2d\x8a\xee\x97\xd9\x13\x2b\xab\x8b\x1c\x87\xb6\x30\xfb\x81\xc8\xe3\xe9\x66\x5e\x34\x73\x7f\x30\x20\xcd\xf5\x1d\xcb\xa8\x01\x8c\x33\xfd\xde\xe8\x52\xb0\x19\xe8\x80\x27\x1c\x9e\x2c\x99\x86\xf5\x4d\x5e\xea\xd0\x70\x14\x7b\x8c\xc9\x7f\x3b\x9f\x5e\x7f\x9b\x4f\xcf\xf3\x29\xfd\x1a\xe7\x53\xf8\xe2\x03\xaa\x7b\xfe\x80\x7a\x7d\xb0\x11\x62\x0e\x42\xd5\x91\xdf\x06\xd5\xff\x30\xa8\xc2\x67\x9c\x54\xc6\x66\x38\xde\x79\x1f\xc6\x6f\x8a\x04\x8d\xa7\x95\x0d\x82\x69\x65\x8a\xc7\x27\x50\x54\xe5\xde\x6a\x6c\x20\x73\x08\x84\x5e\xb0\x57\xaf\x2a\xa8\xf3\xca\xae\x97\x8e\x86\x10\x36\xaf\xd9\x50\x06\xc2\x9c\x4b\x5d\x44\x2c\x97\x5e\x91\x92\xa0\x5b\xa7\x92\x5f\xb5\x4e\x4f\xca\x98\xab\xea\x65\x8c\xd9\x02\x88\xe7\xe0\x97\x48\x4e\x83\x44\x6e\x1d\x7a\xa0\xa7\xc4\x93\xd5\xf1\x7f\x65\x4d\x2d\xe7\x9c\xe1\x6c\x86\x92\x04\xbb\xb3\x63\xb9\xc0\xac\xd8\x09\xd9\x12\x4b\x71\xe2\x8a\x35\xf4\xd6\xa1\x60\x83\x47\x15\x68\x5b\x06\x9b\xb5\xb8\xe3\xf4\x93\x4a\x27\xa0\xf4\xcd\x07\xcd\xe6\xec\xee\xb4\xfa\xfa\x81\x36\x53\xf3\x21\x38\xd1\xfa\x27\xd5\xf2\x32\xee\xef\x01\x00\xa6\x82\x44\x23\x58\x11\x00\x00")
 
-func assetsMetricStateServiceYamlBytes() ([]byte, error) {
+func assetsMetricStateStatefulsetYamlBytes() ([]byte, error) {
 	return bindataRead(
-		_assetsMetricStateServiceYaml,
-		"assets/metric-state/service.yaml",
+		_assetsMetricStateStatefulsetYaml,
+		"assets/metric-state/statefulset.yaml",
 	)
 }
 
-func assetsMetricStateServiceYaml() (*asset, error) {
-	bytes, err := assetsMetricStateServiceYamlBytes()
+func assetsMetricStateStatefulsetYaml() (*asset, error) {
+	bytes, err := assetsMetricStateStatefulsetYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/metric-state/service.yaml", size: 559, mode: os.FileMode(420), modTime: time.Unix(1597390676, 0)}
+	info := bindataFileInfo{name: "assets/metric-state/statefulset.yaml", size: 4440, mode: os.FileMode(420), modTime: time.Unix(1792428242, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -625,9 +625,9 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"assets/metric-state/deployment.yaml":                      assetsMetricStateDeploymentYaml,
 	"assets/metric-state/service-monitor.yaml":                 assetsMetricStateServiceMonitorYaml,
 	"assets/metric-state/service.yaml":                         assetsMetricStateServiceYaml,
+	"assets/metric-state/statefulset.yaml":                     assetsMetricStateStatefulsetYaml,
 	"assets/prometheus/additional-scrape-configs.yaml":         assetsPrometheusAdditionalScrapeConfigsYaml,
 	"assets/prometheus/htpasswd-secret.yaml":                   assetsPrometheusHtpasswdSecretYaml,
 	"assets/prometheus/kube-rbac-proxy-secret.yaml":            assetsPrometheusKubeRbacProxySecretYaml,
@@ -693,9 +693,9 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"assets": &bintree{nil, map[string]*bintree{
 		"metric-state": &bintree{nil, map[string]*bintree{
-			"deployment.yaml":      &bintree{assetsMetricStateDeploymentYaml, map[string]*bintree{}},
 			"service-monitor.yaml": &bintree{assetsMetricStateServiceMonitorYaml, map[string]*bintree{}},
 			"service.yaml":         &bintree{assetsMetricStateServiceYaml, map[string]*bintree{}},
+			"statefulset.yaml":     &bintree{assetsMetricStateStatefulsetYaml, map[string]*bintree{}},
 		}},
 		"prometheus": &bintree{nil, map[string]*bintree{
 			"additional-scrape-configs.yaml":     &bintree{assetsPrometheusAdditionalScrapeConfigsYaml, map[string]*bintree{}},
@@ -23,6 +23,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
@@ -54,7 +55,7 @@ const (
 
 	ReporterJob = "assets/reporter/job.yaml"
 
-	MetricStateDeployment     = "assets/metric-state/deployment.yaml"
+	MetricStateStatefulSet    = "assets/metric-state/statefulset.yaml"
 	MetricStateServiceMonitor = "assets/metric-state/service-monitor.yaml"
 	MetricStateService        = "assets/metric-state/service.yaml"
 )
@@ -88,6 +89,19 @@ func (f *Factory) NewDeployment(manifest io.Reader) (*appsv1.Deployment, error)
 	return d, nil
 }
 
+func (f *Factory) NewStatefulSet(manifest io.Reader) (*appsv1.StatefulSet, error) {
+	s, err := NewStatefulSet(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.GetNamespace() == "" {
+		s.SetNamespace(f.namespace)
+	}
+
+	return s, nil
+}
+
 func (f *Factory) NewService(manifest io.Reader) (*corev1.Service, error) {
 	d, err := NewService(manifest)
 	if err != nil {
@@ -362,7 +376,10 @@ func (f *Factory) PrometheusServingCertsCABundle() (*v1.ConfigMap, error) {
 	return c, nil
 }
 
-func (f *Factory) ReporterJob(report *marketplacev1alpha1.MeterReport) (*batchv1.Job, error) {
+func (f *Factory) ReporterJob(
+	report *marketplacev1alpha1.MeterReport,
+	template *marketplacev1alpha1.ReporterJobTemplateSpec,
+) (*batchv1.Job, error) {
 	j, err := f.NewJob(MustAssetReader(ReporterJob))
 
 	if err != nil {
@@ -382,15 +399,97 @@ func (f *Factory) ReporterJob(report *marketplacev1alpha1.MeterReport) (*batchv1
 
 	j.Spec.Template.Spec.Containers[0] = container
 
+	if template != nil {
+		mergeReporterJobTemplate(j, template)
+	}
+
 	return j, nil
 }
 
-func (f *Factory) MetricStateDeployment() (*appsv1.Deployment, error) {
-	d, err := f.NewDeployment(MustAssetReader(MetricStateDeployment))
+// mergeReporterJobTemplate overrides the defaults of the reporter job with
+// the fields set on the template.
+func mergeReporterJobTemplate(
+	j *batchv1.Job,
+	template *marketplacev1alpha1.ReporterJobTemplateSpec,
+) {
+	podSpec := &j.Spec.Template.Spec
+	container := &podSpec.Containers[0]
+
+	if template.Resources != nil {
+		container.Resources = *template.Resources.DeepCopy()
+	}
+
+	for _, envVar := range template.Env {
+		replaced := false
+		for i := range container.Env {
+			if container.Env[i].Name == envVar.Name {
+				container.Env[i] = *envVar.DeepCopy()
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			container.Env = append(container.Env, *envVar.DeepCopy())
+		}
+	}
+
+	if len(template.NodeSelector) > 0 {
+		podSpec.NodeSelector = make(map[string]string)
+		for key, value := range template.NodeSelector {
+			podSpec.NodeSelector[key] = value
+		}
+	}
+
+	for _, toleration := range template.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, *toleration.DeepCopy())
+	}
+
+	if template.Affinity != nil {
+		podSpec.Affinity = template.Affinity.DeepCopy()
+	}
+
+	if template.PriorityClassName != "" {
+		podSpec.PriorityClassName = template.PriorityClassName
+	}
+
+	podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, template.ImagePullSecrets...)
+
+	if template.ActiveDeadlineSeconds != nil {
+		j.Spec.ActiveDeadlineSeconds = ptr.Int64(*template.ActiveDeadlineSeconds)
+	}
+
+	if template.BackoffLimit != nil {
+		j.Spec.BackoffLimit = ptr.Int32(*template.BackoffLimit)
+	}
+
+	if template.TTLSecondsAfterFinished != nil {
+		j.Spec.TTLSecondsAfterFinished = ptr.Int32(*template.TTLSecondsAfterFinished)
+	}
+}
+
+// MetricStateShardsAnnotation is set on the pods of the metric state so they
+// restart with the new number of shards when it changes.
+const MetricStateShardsAnnotation = "marketplace.redhat.com/metric-state-shards"
+
+// MetricStateStatefulSet returns the metric state with a replica for each
+// shard. The pods find their shard from their ordinal.
+func (f *Factory) MetricStateStatefulSet(shards int32) (*appsv1.StatefulSet, error) {
+	d, err := f.NewStatefulSet(MustAssetReader(MetricStateStatefulSet))
 	if err != nil {
 		return nil, err
 	}
 
+	if shards < 1 {
+		shards = 1
+	}
+
+	d.Spec.Replicas = ptr.Int32(shards)
+
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	d.Spec.Template.Annotations[MetricStateShardsAnnotation] = strconv.Itoa(int(shards))
+
 	for i, container := range d.Spec.Template.Spec.Containers {
 		switch container.Name {
 		case "kube-rbac-proxy-1":
@@ -399,6 +498,7 @@ func (f *Factory) MetricStateDeployment() (*appsv1.Deployment, error) {
 			d.Spec.Template.Spec.Containers[i].Image = f.config.RelatedImages.KubeRbacProxy
 		case "metric-state":
 			d.Spec.Template.Spec.Containers[i].Image = f.config.RelatedImages.MetricState
+			f.addMetricStatePrometheusConfig(&d.Spec.Template.Spec, &d.Spec.Template.Spec.Containers[i])
 		}
 	}
 
@@ -407,6 +507,58 @@ func (f *Factory) MetricStateDeployment() (*appsv1.Deployment, error) {
 	return d, nil
 }
 
+// addMetricStatePrometheusConfig lets the metric state query the meterbase
+// prometheus for the workload status of meter definitions.
+func (f *Factory) addMetricStatePrometheusConfig(podSpec *corev1.PodSpec, container *corev1.Container) {
+	container.Args = append(container.Args,
+		fmt.Sprintf("--prometheus-address=https://rhm-prometheus-meterbase.%s.svc:9092", f.namespace),
+		"--prometheus-cafile=/etc/configmaps/operator-cert-ca-bundle/service-ca.crt",
+		"--prometheus-tokenfile=/etc/service-account/token",
+	)
+
+	container.VolumeMounts = append(container.VolumeMounts,
+		corev1.VolumeMount{
+			Name:      "operator-certs-ca-bundle",
+			MountPath: "/etc/configmaps/operator-cert-ca-bundle",
+			ReadOnly:  true,
+		},
+		corev1.VolumeMount{
+			Name:      "token-vol",
+			MountPath: "/etc/service-account",
+			ReadOnly:  true,
+		},
+	)
+
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: "operator-certs-ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "operator-certs-ca-bundle",
+					},
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "token-vol",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          fmt.Sprintf("rhm-prometheus-meterbase.%s.svc", f.namespace),
+								ExpirationSeconds: ptr.Int64(3600),
+								Path:              "token",
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+}
+
 func (f *Factory) MetricStateServiceMonitor() (*monitoringv1.ServiceMonitor, error) {
 	sm, err := f.NewServiceMonitor(MustAssetReader(MetricStateServiceMonitor))
 	if err != nil {
@@ -454,6 +606,16 @@ func NewDeployment(manifest io.Reader) (*appsv1.Deployment, error) {
 	return &d, nil
 }
 
+func NewStatefulSet(manifest io.Reader) (*appsv1.StatefulSet, error) {
+	s := appsv1.StatefulSet{}
+	err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
 func NewConfigMap(manifest io.Reader) (*v1.ConfigMap, error) {
 	cm := v1.ConfigMap{}
 	err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&cm)
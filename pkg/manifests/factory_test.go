@@ -0,0 +1,156 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func newTestReporterJob() *batchv1.Job {
+	return &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.Int32(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "reporter",
+							Env: []corev1.EnvVar{
+								{Name: "POD_NAMESPACE", Value: "openshift-redhat-marketplace"},
+								{Name: "LOG_LEVEL", Value: "info"},
+							},
+						},
+					},
+					NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+					Tolerations: []corev1.Toleration{
+						{Key: "node-role.kubernetes.io/master", Operator: corev1.TolerationOpExists},
+					},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "pull-secret"}},
+				},
+			},
+		},
+	}
+}
+
+func TestMergeReporterJobTemplate(t *testing.T) {
+	infra := corev1.Toleration{Key: "infra", Operator: corev1.TolerationOpEqual, Value: "reserved", Effect: corev1.TaintEffectNoSchedule}
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	tests := []struct {
+		name     string
+		template *marketplacev1alpha1.ReporterJobTemplateSpec
+		expected func(j *batchv1.Job)
+	}{
+		{
+			name:     "empty template keeps the defaults",
+			template: &marketplacev1alpha1.ReporterJobTemplateSpec{},
+			expected: func(j *batchv1.Job) {},
+		},
+		{
+			name: "env replaces variables by name and appends new ones",
+			template: &marketplacev1alpha1.ReporterJobTemplateSpec{
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+				},
+			},
+			expected: func(j *batchv1.Job) {
+				j.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+					{Name: "POD_NAMESPACE", Value: "openshift-redhat-marketplace"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+				}
+			},
+		},
+		{
+			name: "node selector replaces the default",
+			template: &marketplacev1alpha1.ReporterJobTemplateSpec{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+			},
+			expected: func(j *batchv1.Job) {
+				j.Spec.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/infra": ""}
+			},
+		},
+		{
+			name: "tolerations and pull secrets are appended",
+			template: &marketplacev1alpha1.ReporterJobTemplateSpec{
+				Tolerations:      []corev1.Toleration{infra},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mirror-secret"}},
+			},
+			expected: func(j *batchv1.Job) {
+				podSpec := &j.Spec.Template.Spec
+				podSpec.Tolerations = append(podSpec.Tolerations, infra)
+				podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: "mirror-secret"})
+			},
+		},
+		{
+			name: "resources, scheduling and job limits are overridden",
+			template: &marketplacev1alpha1.ReporterJobTemplateSpec{
+				Resources:               &resources,
+				Affinity:                &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+				PriorityClassName:       "system-cluster-critical",
+				ActiveDeadlineSeconds:   ptr.Int64(600),
+				BackoffLimit:            ptr.Int32(1),
+				TTLSecondsAfterFinished: ptr.Int32(3600),
+			},
+			expected: func(j *batchv1.Job) {
+				podSpec := &j.Spec.Template.Spec
+				podSpec.Containers[0].Resources = resources
+				podSpec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+				podSpec.PriorityClassName = "system-cluster-critical"
+				j.Spec.ActiveDeadlineSeconds = ptr.Int64(600)
+				j.Spec.BackoffLimit = ptr.Int32(1)
+				j.Spec.TTLSecondsAfterFinished = ptr.Int32(3600)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := newTestReporterJob()
+			tt.expected(expected)
+
+			job := newTestReporterJob()
+			mergeReporterJobTemplate(job, tt.template)
+
+			assert.Equal(t, expected, job)
+		})
+	}
+}
+
+func TestMergeReporterJobTemplateDoesNotShareTemplate(t *testing.T) {
+	template := &marketplacev1alpha1.ReporterJobTemplateSpec{
+		Env:          []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+	}
+
+	job := newTestReporterJob()
+	mergeReporterJobTemplate(job, template)
+
+	job.Spec.Template.Spec.Containers[0].Env[1].Value = "error"
+	job.Spec.Template.Spec.NodeSelector["changed"] = "true"
+
+	assert.Equal(t, "debug", template.Env[0].Value)
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/infra": ""}, template.NodeSelector)
+}
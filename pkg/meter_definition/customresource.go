@@ -0,0 +1,205 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// customResourceMappingRetry is how long to wait before looking up the
+// mapping of a custom resource again, for example when its CRD is installed
+// after the meter definition.
+const customResourceMappingRetry = time.Minute
+
+// customResourceWatchKey is a custom resource in a namespace.
+type customResourceWatchKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// String is the name of the reflector of the watch.
+func (k customResourceWatchKey) String() string {
+	name := k.gvk.GroupKind().String()
+
+	if k.namespace == corev1.NamespaceAll {
+		return name
+	}
+
+	return name + "/" + k.namespace
+}
+
+// customResourceWatch is the reflector of a custom resource in a namespace
+// and the meter definitions that need it. The reflector is stopped once no
+// meter definition needs it.
+type customResourceWatch struct {
+	resource     schema.GroupVersionResource
+	namespaced   bool
+	cancel       context.CancelFunc
+	meterDefUIDs map[MeterDefUID]bool
+}
+
+// customResources returns the custom resources of the CustomResource
+// workloads of the lookup.
+func (s *MeterDefinitionLookupFilter) customResources() []schema.GroupVersionKind {
+	gvks := []schema.GroupVersionKind{}
+
+	for _, workload := range s.workloads {
+		if workload.WorkloadType != v1beta1.WorkloadTypeCustomResource || workload.CustomResource == nil {
+			continue
+		}
+
+		gvks = append(gvks, schema.FromAPIVersionAndKind(workload.CustomResource.APIVersion, workload.CustomResource.Kind))
+	}
+
+	return gvks
+}
+
+// namespaces returns the namespaces the workloads of the lookup are matched in.
+func (s *MeterDefinitionLookupFilter) namespaces() []string {
+	return append([]string{}, s.namespaceFilter.namespaces...)
+}
+
+// watchedNamespaces returns the namespaces watched by the store that are in
+// the scope of a meter definition.
+func watchedNamespaces(storeNamespaces, scope []string) []string {
+	watched := sets.NewString(storeNamespaces...)
+	scoped := sets.NewString(scope...)
+
+	switch {
+	case scoped.Has(corev1.NamespaceAll):
+		return watched.List()
+	case watched.Has(corev1.NamespaceAll):
+		return scoped.List()
+	default:
+		return watched.Intersection(scoped).List()
+	}
+}
+
+// watchCustomResources updates the custom resources watched for the meter
+// definition to gvks in the namespaces it is scoped to. Watches no meter
+// definition needs anymore are stopped, so calling it without gvks releases
+// the watches of the meter definition. A custom resource that has no mapping
+// yet is looked up again later.
+func (s *MeterDefinitionStore) watchCustomResources(meterDefUID MeterDefUID, gvks []schema.GroupVersionKind, namespaces []string) {
+	s.watchMutex.Lock()
+	defer s.watchMutex.Unlock()
+
+	// a nil mapping keeps the current watches of the custom resource
+	wanted := map[customResourceWatchKey]*meta.RESTMapping{}
+	missing := false
+
+	for _, gvk := range gvks {
+		mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			s.log.Error(err, "failed to get mapping for custom resource, retrying", "gvk", gvk, "after", customResourceMappingRetry)
+			missing = true
+
+			for key, w := range s.customResourceWatches {
+				if key.gvk == gvk && w.meterDefUIDs[meterDefUID] {
+					wanted[key] = nil
+				}
+			}
+
+			continue
+		}
+
+		watchNamespaces := watchedNamespaces(s.namespaces, namespaces)
+		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			watchNamespaces = []string{corev1.NamespaceAll}
+		}
+
+		for _, ns := range watchNamespaces {
+			wanted[customResourceWatchKey{gvk: gvk, namespace: ns}] = mapping
+		}
+	}
+
+	for key, mapping := range wanted {
+		w, ok := s.customResourceWatches[key]
+
+		if !ok {
+			s.log.Info("watching custom resource", "gvk", key.gvk, "namespace", key.namespace, "resource", mapping.Resource)
+
+			ctx, cancel := context.WithCancel(s.ctx)
+			s.watchUntil(
+				key.String(),
+				CreateCustomResourceListWatch(s.dynamicClient, mapping.Resource, key.namespace),
+				&unstructured.Unstructured{},
+				ctx.Done())
+
+			w = &customResourceWatch{
+				resource:     mapping.Resource,
+				namespaced:   mapping.Scope.Name() != meta.RESTScopeNameRoot,
+				cancel:       cancel,
+				meterDefUIDs: map[MeterDefUID]bool{},
+			}
+			s.customResourceWatches[key] = w
+		}
+
+		w.meterDefUIDs[meterDefUID] = true
+	}
+
+	for key, w := range s.customResourceWatches {
+		if _, ok := wanted[key]; ok || !w.meterDefUIDs[meterDefUID] {
+			continue
+		}
+
+		delete(w.meterDefUIDs, meterDefUID)
+
+		if len(w.meterDefUIDs) == 0 {
+			s.log.Info("stopping custom resource watch", "gvk", key.gvk, "namespace", key.namespace)
+			w.cancel()
+			s.unwatch(key.String())
+			delete(s.customResourceWatches, key)
+		}
+	}
+
+	if missing && !s.customResourceRetries[meterDefUID] {
+		s.customResourceRetries[meterDefUID] = true
+		time.AfterFunc(customResourceMappingRetry, func() {
+			s.retryCustomResources(meterDefUID)
+		})
+	}
+}
+
+// retryCustomResources watches the custom resources of the meter definition
+// again if it still exists.
+func (s *MeterDefinitionStore) retryCustomResources(meterDefUID MeterDefUID) {
+	s.watchMutex.Lock()
+	delete(s.customResourceRetries, meterDefUID)
+	s.watchMutex.Unlock()
+
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// the watches of a deleted meter definition were released with it
+	lookup, ok := s.meterDefinitionFilters[meterDefUID]
+	if !ok {
+		return
+	}
+
+	s.watchCustomResources(meterDefUID, lookup.customResources(), lookup.namespaces())
+}
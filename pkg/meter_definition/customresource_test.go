@@ -0,0 +1,189 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// newTestCustomResourceStore returns a store watching the apps and other
+// namespaces whose dynamic client has the App objects.
+func newTestCustomResourceStore(ctx context.Context, t *testing.T, objs ...runtime.Object) *MeterDefinitionStore {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	dynamicScheme := runtime.NewScheme()
+	dynamicScheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "partner.metering.com", Version: "v1alpha1", Kind: "AppList"},
+		&unstructured.UnstructuredList{})
+
+	cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(testScheme), testScheme, logf.Log.WithName("customresource_test"))
+	store := NewMeterDefinitionStore(ctx, logf.Log.WithName("customresource_test"), cc, nil, nil, nil, nil,
+		dynamicfake.NewSimpleDynamicClient(dynamicScheme, objs...), newTestMapper(), testScheme)
+	store.SetNamespaces([]string{"apps", "other"})
+
+	return store
+}
+
+// newCustomResourceMeterDefinition returns a meter definition of a
+// CustomResource workload of the kind.
+func newCustomResourceMeterDefinition(name, namespace, kind string) *v1beta1.MeterDefinition {
+	meterdef := newValidMeterDefinition()
+	meterdef.Name = name
+	meterdef.Namespace = namespace
+	meterdef.UID = types.UID(namespace + "-" + name)
+	meterdef.Spec.Workloads[0].WorkloadType = v1beta1.WorkloadTypeCustomResource
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].CustomResource = &v1beta1.CustomResourceWorkload{
+		GroupVersionKind: common.GroupVersionKind{
+			APIVersion: "partner.metering.com/v1alpha1",
+			Kind:       kind,
+		},
+	}
+	return meterdef
+}
+
+func TestCustomResourceWatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newTestCustomResourceStore(ctx, t)
+
+	watches := func() map[string]int {
+		store.watchMutex.Lock()
+		defer store.watchMutex.Unlock()
+
+		refs := map[string]int{}
+		for key, w := range store.customResourceWatches {
+			refs[key.String()] = len(w.meterDefUIDs)
+		}
+		return refs
+	}
+
+	// the operator group vertex without installedBy is scoped to the
+	// namespace of the meterdef
+	first := newCustomResourceMeterDefinition("first", "apps", "App")
+	second := newCustomResourceMeterDefinition("second", "apps", "App")
+	require.NoError(t, store.Add(first))
+	require.NoError(t, store.Add(second))
+	assert.Equal(t, map[string]int{"App.partner.metering.com/apps": 2}, watches())
+	assert.Contains(t, store.SyncStatus(), "App.partner.metering.com/apps")
+
+	// namespaces the store doesn't watch are not watched for the meterdef
+	require.NoError(t, store.Add(newCustomResourceMeterDefinition("elsewhere", "elsewhere", "App")))
+	assert.Equal(t, map[string]int{"App.partner.metering.com/apps": 2}, watches())
+
+	// a custom resource without a mapping is retried instead of failing
+	missing := newCustomResourceMeterDefinition("missing", "other", "Missing")
+	require.NoError(t, store.Add(missing))
+	assert.Equal(t, map[string]int{"App.partner.metering.com/apps": 2}, watches())
+	store.watchMutex.Lock()
+	assert.True(t, store.customResourceRetries[MeterDefUID(missing.UID)])
+	store.watchMutex.Unlock()
+
+	// the watch is stopped once the last meterdef using it is deleted
+	require.NoError(t, store.Delete(first))
+	assert.Equal(t, map[string]int{"App.partner.metering.com/apps": 1}, watches())
+
+	require.NoError(t, store.Delete(second))
+	assert.Empty(t, watches())
+	assert.NotContains(t, store.SyncStatus(), "App.partner.metering.com/apps")
+
+	store.retryCustomResources(MeterDefUID(first.UID))
+	assert.Empty(t, watches(), "deleted meterdefs are not watched again")
+}
+
+func TestCustomResourceWatchStartAndStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &unstructured.Unstructured{}
+	app.SetAPIVersion("partner.metering.com/v1alpha1")
+	app.SetKind("App")
+	app.SetName("app")
+	app.SetNamespace("apps")
+	app.SetUID("apps-app")
+
+	store := newTestCustomResourceStore(ctx, t, app)
+	l := newTestListener(store)
+
+	// the watch is started with the first meterdef and lists the apps
+	first := newCustomResourceMeterDefinition("first", "apps", "App")
+	require.NoError(t, store.Add(first))
+
+	assert.Eventually(t, func() bool {
+		return store.SyncStatus()["App.partner.metering.com/apps"]
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"apps-app"}, store.ListKeys())
+	assert.Len(t, drainMessages(l), 1)
+
+	key := customResourceWatchKey{
+		gvk:       schema.GroupVersionKind{Group: "partner.metering.com", Version: "v1alpha1", Kind: "App"},
+		namespace: "apps",
+	}
+
+	store.watchMutex.Lock()
+	w := store.customResourceWatches[key]
+	require.NotNil(t, w)
+	stopWatch := w.cancel
+	stopped := 0
+	w.cancel = func() {
+		stopped++
+		stopWatch()
+	}
+	store.watchMutex.Unlock()
+
+	// a second meterdef shares the watch, it is not started again
+	second := newCustomResourceMeterDefinition("second", "apps", "App")
+	require.NoError(t, store.Add(second))
+
+	store.watchMutex.Lock()
+	assert.Len(t, store.customResourceWatches, 1)
+	assert.Same(t, w, store.customResourceWatches[key])
+	assert.Len(t, w.meterDefUIDs, 2)
+	store.watchMutex.Unlock()
+
+	// the watch is stopped once, with the last meterdef
+	require.NoError(t, store.Delete(first))
+	assert.Equal(t, 0, stopped)
+
+	require.NoError(t, store.Delete(second))
+	assert.Equal(t, 1, stopped)
+	assert.Empty(t, store.SyncStatus())
+}
+
+func TestWatchedNamespaces(t *testing.T) {
+	assert.Equal(t, []string{"apps", "other"}, watchedNamespaces([]string{"apps", "other"}, []string{""}))
+	assert.Equal(t, []string{"apps"}, watchedNamespaces([]string{""}, []string{"apps"}))
+	assert.Equal(t, []string{"apps"}, watchedNamespaces([]string{"apps", "other"}, []string{"apps", "elsewhere"}))
+	assert.Empty(t, watchedNamespaces([]string{"apps"}, []string{"elsewhere"}))
+}
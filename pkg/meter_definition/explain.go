@@ -0,0 +1,292 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"fmt"
+	"sort"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MeterDefinitionDebug is the lookup of a meter definition in the store.
+type MeterDefinitionDebug struct {
+	UID                types.UID       `json:"uid"`
+	Name               string          `json:"name"`
+	Namespace          string          `json:"namespace,omitempty"`
+	Hash               string          `json:"hash"`
+	Namespaces         []string        `json:"namespaces"`
+	ExcludedNamespaces []string        `json:"excludedNamespaces,omitempty"`
+	Workloads          []WorkloadDebug `json:"workloads"`
+	MatchedObjects     int             `json:"matchedObjects"`
+}
+
+// WorkloadDebug is a workload of a meter definition and its filters.
+type WorkloadDebug struct {
+	Name    string   `json:"name"`
+	Filters []string `json:"filters"`
+}
+
+// MeterDefinitionExplanation explains whether an object matches the
+// workloads of a meter definition.
+type MeterDefinitionExplanation struct {
+	MeterDefinition types.NamespacedName  `json:"meterDefinition"`
+	UID             types.UID             `json:"uid"`
+	Object          ObjectDebug           `json:"object"`
+	Matched         bool                  `json:"matched"`
+	Workloads       []WorkloadExplanation `json:"workloads"`
+}
+
+// ObjectDebug identifies an object.
+type ObjectDebug struct {
+	UID       types.UID `json:"uid"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace,omitempty"`
+}
+
+// NewObjectDebug identifies the object.
+func NewObjectDebug(o metav1.Object) ObjectDebug {
+	return ObjectDebug{
+		UID:       o.GetUID(),
+		Type:      fmt.Sprintf("%T", o),
+		Name:      o.GetName(),
+		Namespace: o.GetNamespace(),
+	}
+}
+
+// WorkloadExplanation is the verdict of every filter of a workload. Unlike
+// FindMatchingWorkloads all of the filters are run so each one is explained.
+type WorkloadExplanation struct {
+	Name     string          `json:"name"`
+	Matched  bool            `json:"matched"`
+	Verdicts []FilterVerdict `json:"verdicts"`
+}
+
+// FilterVerdict is the result of a filter for an object.
+type FilterVerdict struct {
+	Filter       string                  `json:"filter"`
+	Description  string                  `json:"description"`
+	Passed       bool                    `json:"passed"`
+	Error        string                  `json:"error,omitempty"`
+	OwnersWalked []metav1.OwnerReference `json:"ownersWalked,omitempty"`
+}
+
+func filterName(f FilterRuntimeObject) string {
+	switch f.(type) {
+	case *WorkloadNamespaceFilter:
+		return "namespace"
+	case *WorkloadTypeFilter:
+		return "type"
+	case *WorkloadGVKFilter:
+		return "gvk"
+	case *WorkloadLabelFilter:
+		return "label"
+	case *WorkloadAnnotationFilter:
+		return "annotation"
+	case *WorkloadFieldFilter:
+		return "field"
+	case *WorkloadFilterForOwner:
+		return "owner"
+	default:
+		return fmt.Sprintf("%T", f)
+	}
+}
+
+// Explain runs the filters of every workload against the object, sorted by
+// workload name.
+func (s *MeterDefinitionLookupFilter) Explain(obj interface{}) []WorkloadExplanation {
+	explanations := []WorkloadExplanation{}
+
+	for name, workloadFilters := range s.filters {
+		explanation := WorkloadExplanation{
+			Name:     name,
+			Matched:  true,
+			Verdicts: []FilterVerdict{},
+		}
+
+		for _, filter := range workloadFilters {
+			verdict := FilterVerdict{
+				Filter:      filterName(filter),
+				Description: printFilter(filter),
+			}
+
+			var err error
+			if ownerFilter, ok := filter.(*WorkloadFilterForOwner); ok {
+				verdict.Passed, verdict.OwnersWalked, err = ownerFilter.walkOwners(obj)
+			} else {
+				verdict.Passed, err = filter.Filter(obj)
+			}
+
+			if err != nil {
+				verdict.Passed = false
+				verdict.Error = err.Error()
+			}
+
+			explanation.Matched = explanation.Matched && verdict.Passed
+			explanation.Verdicts = append(explanation.Verdicts, verdict)
+		}
+
+		explanations = append(explanations, explanation)
+	}
+
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].Name < explanations[j].Name
+	})
+
+	return explanations
+}
+
+func (s *MeterDefinitionLookupFilter) debug() MeterDefinitionDebug {
+	d := MeterDefinitionDebug{
+		Name:               s.MeterDefName.Name,
+		Namespace:          s.MeterDefName.Namespace,
+		Hash:               s.hash,
+		Namespaces:         append([]string{}, s.namespaceFilter.namespaces...),
+		ExcludedNamespaces: s.namespaceFilter.excluded,
+		Workloads:          []WorkloadDebug{},
+	}
+
+	for name, workloadFilters := range s.filters {
+		filters := []string{}
+		for _, filter := range workloadFilters {
+			filters = append(filters, printFilter(filter))
+		}
+
+		d.Workloads = append(d.Workloads, WorkloadDebug{Name: name, Filters: filters})
+	}
+
+	sort.Slice(d.Workloads, func(i, j int) bool {
+		return d.Workloads[i].Name < d.Workloads[j].Name
+	})
+
+	return d
+}
+
+// DebugMeterDefinitions returns the lookups of the meter definitions of the
+// store sorted by namespace and name.
+func (s *MeterDefinitionStore) DebugMeterDefinitions() []MeterDefinitionDebug {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	meterdefs := []MeterDefinitionDebug{}
+	for meterDefUID, lookup := range s.meterDefinitionFilters {
+		d := lookup.debug()
+		d.UID = types.UID(meterDefUID)
+
+		for _, resource := range s.queryObjectResources(ObjectResourceQuery{MeterDefUID: d.UID}) {
+			if resource.value.Matched {
+				d.MatchedObjects++
+			}
+		}
+
+		meterdefs = append(meterdefs, d)
+	}
+
+	sort.Slice(meterdefs, func(i, j int) bool {
+		if meterdefs[i].Namespace != meterdefs[j].Namespace {
+			return meterdefs[i].Namespace < meterdefs[j].Namespace
+		}
+		return meterdefs[i].Name < meterdefs[j].Name
+	})
+
+	return meterdefs
+}
+
+// Explain explains whether the object matches the meter definition. The
+// meter definition is its UID, its namespace/name or the name of a cluster
+// meter definition.
+func (s *MeterDefinitionStore) Explain(meterdef string, obj metav1.Object) (*MeterDefinitionExplanation, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for meterDefUID, lookup := range s.meterDefinitionFilters {
+		name := lookup.MeterDefName
+
+		if meterdef != string(meterDefUID) &&
+			meterdef != name.String() &&
+			!(name.Namespace == "" && meterdef == name.Name) {
+			continue
+		}
+
+		workloads := lookup.Explain(obj)
+		explanation := &MeterDefinitionExplanation{
+			MeterDefinition: name,
+			UID:             types.UID(meterDefUID),
+			Object:          NewObjectDebug(obj),
+			Workloads:       workloads,
+		}
+
+		for _, workload := range workloads {
+			explanation.Matched = explanation.Matched || workload.Matched
+		}
+
+		return explanation, nil
+	}
+
+	return nil, errors.NewWithDetails("meter definition not found", "meterdef", meterdef)
+}
+
+// FindObject returns the object with the UID. Objects that matched a meter
+// definition are kept by the store, any other object is looked up in the
+// workloads and custom resources the store watches. Types that fail to list
+// are skipped and their errors returned if the object is not found.
+func (s *MeterDefinitionStore) FindObject(uid types.UID) (metav1.Object, bool, error) {
+	if item, exists, _ := s.GetByKey(string(uid)); exists {
+		if o, err := meta.Accessor(item); err == nil {
+			return o, true, nil
+		}
+	}
+
+	listers := []cache.ListerWatcher{}
+	for _, ns := range s.namespaces {
+		for _, lister := range s.createWatchers(ns) {
+			listers = append(listers, lister)
+		}
+	}
+
+	s.watchMutex.Lock()
+	for key, w := range s.customResourceWatches {
+		listers = append(listers, CreateCustomResourceListWatch(s.dynamicClient, w.resource, key.namespace))
+	}
+	s.watchMutex.Unlock()
+
+	var errs error
+	for _, lister := range listers {
+		listObj, err := lister.List(metav1.ListOptions{})
+		if err != nil {
+			errs = errors.Append(errs, err)
+			continue
+		}
+
+		items, err := meta.ExtractList(listObj)
+		if err != nil {
+			errs = errors.Append(errs, err)
+			continue
+		}
+
+		for _, item := range items {
+			if o, err := meta.Accessor(item); err == nil && o.GetUID() == uid {
+				return o, true, nil
+			}
+		}
+	}
+
+	return nil, false, errs
+}
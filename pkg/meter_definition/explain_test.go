@@ -0,0 +1,163 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1client "github.com/coreos/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestMeterDefinitionStoreExplain(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(testScheme), testScheme, logf.Log.WithName("explain_test"))
+	store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("explain_test"), cc, nil, nil, nil, nil, nil, nil, testScheme)
+
+	meterdef := newValidMeterDefinition()
+	meterdef.UID = "meterdef-uid"
+	meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+	meterdef.Spec.Workloads = append(meterdef.Spec.Workloads, v1beta1.Workload{
+		Name:         "labeled-pods",
+		WorkloadType: v1beta1.WorkloadTypePod,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "app"},
+		},
+	})
+	require.NoError(t, store.Add(meterdef))
+
+	isController := true
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pod",
+		Namespace: "apps",
+		UID:       "pod-uid",
+		Labels:    map[string]string{"app": "other"},
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion: "partner.metering.com/v1alpha1",
+				Kind:       "App",
+				Name:       "app",
+				UID:        "app-uid",
+				Controller: &isController,
+			},
+		},
+	}}
+
+	meterdefs := store.DebugMeterDefinitions()
+	require.Len(t, meterdefs, 1)
+	assert.Equal(t, types.UID("meterdef-uid"), meterdefs[0].UID)
+	assert.Equal(t, []string{""}, meterdefs[0].Namespaces)
+	require.Len(t, meterdefs[0].Workloads, 2)
+	assert.Equal(t, "app-pods", meterdefs[0].Workloads[0].Name)
+
+	_, err := store.Explain("default/missing", pod)
+	assert.Error(t, err)
+
+	for _, key := range []string{"meterdef-uid", "default/example-meterdefinition"} {
+		explanation, err := store.Explain(key, pod)
+		require.NoError(t, err)
+
+		assert.True(t, explanation.Matched)
+		assert.Equal(t, types.UID("pod-uid"), explanation.Object.UID)
+		require.Len(t, explanation.Workloads, 2)
+
+		owned := explanation.Workloads[0]
+		assert.Equal(t, "app-pods", owned.Name)
+		assert.True(t, owned.Matched)
+		require.Len(t, owned.Verdicts, 3)
+		assert.Equal(t, "owner", owned.Verdicts[2].Filter)
+		assert.True(t, owned.Verdicts[2].Passed)
+		require.Len(t, owned.Verdicts[2].OwnersWalked, 1)
+		assert.Equal(t, types.UID("app-uid"), owned.Verdicts[2].OwnersWalked[0].UID)
+
+		labeled := explanation.Workloads[1]
+		assert.Equal(t, "labeled-pods", labeled.Name)
+		assert.False(t, labeled.Matched)
+
+		verdicts := map[string]bool{}
+		for _, verdict := range labeled.Verdicts {
+			verdicts[verdict.Filter] = verdict.Passed
+		}
+		assert.Equal(t, map[string]bool{"namespace": true, "type": true, "label": false}, verdicts)
+	}
+}
+
+func TestMeterDefinitionStoreFindObject(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "apps", UID: "pod-uid"}}
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "apps", UID: "pvc-uid"}}
+
+	app := &unstructured.Unstructured{}
+	app.SetAPIVersion("partner.metering.com/v1alpha1")
+	app.SetKind("App")
+	app.SetName("app")
+	app.SetNamespace("apps")
+	app.SetUID("app-uid")
+
+	dynamicScheme := runtime.NewScheme()
+	dynamicScheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "partner.metering.com", Version: "v1alpha1", Kind: "AppList"},
+		&unstructured.UnstructuredList{})
+
+	// service monitors fail to list, the other types are still searched
+	monitoringClient, err := monitoringv1client.NewForConfig(&rest.Config{Host: "http://127.0.0.1:1"})
+	require.NoError(t, err)
+
+	cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(testScheme), testScheme, logf.Log.WithName("explain_test"))
+	store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("explain_test"), cc,
+		kubefake.NewSimpleClientset(pod, pvc), nil, monitoringClient, nil,
+		dynamicfake.NewSimpleDynamicClient(dynamicScheme, app), newTestMapper(), testScheme)
+	store.SetNamespaces([]string{"apps"})
+	store.customResourceWatches[customResourceWatchKey{
+		gvk:       schema.GroupVersionKind{Group: "partner.metering.com", Version: "v1alpha1", Kind: "App"},
+		namespace: "apps",
+	}] = &customResourceWatch{
+		resource:   schema.GroupVersionResource{Group: "partner.metering.com", Version: "v1alpha1", Resource: "apps"},
+		namespaced: true,
+	}
+
+	for _, uid := range []types.UID{"pod-uid", "pvc-uid", "app-uid"} {
+		obj, exists, err := store.FindObject(uid)
+		require.NoError(t, err, uid)
+		require.True(t, exists, uid)
+		assert.Equal(t, uid, obj.GetUID())
+	}
+
+	_, exists, err := store.FindObject("missing-uid")
+	assert.False(t, exists)
+	assert.Error(t, err, "the failed list is returned")
+}
@@ -20,10 +20,15 @@ import (
 	"strings"
 
 	"emperror.dev/errors"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -53,6 +58,7 @@ func printFilter(f FilterRuntimeObject) string {
 
 type WorkloadNamespaceFilter struct {
 	namespaces []string
+	excluded   []string
 }
 
 func (f *WorkloadNamespaceFilter) Filter(obj interface{}) (bool, error) {
@@ -62,6 +68,12 @@ func (f *WorkloadNamespaceFilter) Filter(obj interface{}) (bool, error) {
 		return false, errors.New("type was not a metav1.Object")
 	}
 
+	for _, ns := range f.excluded {
+		if ns == meta.GetNamespace() {
+			return false, nil
+		}
+	}
+
 	for _, ns := range f.namespaces {
 		if ns == "" {
 			return true, nil
@@ -76,7 +88,7 @@ func (f *WorkloadNamespaceFilter) Filter(obj interface{}) (bool, error) {
 }
 
 func (f *WorkloadNamespaceFilter) String() string {
-	return fmt.Sprintf("WorkloadNamespaceFilter{namespaces: %s}", strings.Join(f.namespaces, ","))
+	return fmt.Sprintf("WorkloadNamespaceFilter{namespaces: %s, excluded: %s}", strings.Join(f.namespaces, ","), strings.Join(f.excluded, ","))
 }
 
 type WorkloadTypeFilter struct {
@@ -108,8 +120,26 @@ func (f *WorkloadTypeFilter) Filter(obj interface{}) (bool, error) {
 	return false, nil
 }
 
+type WorkloadGVKFilter struct {
+	gvk schema.GroupVersionKind
+}
+
+func (f *WorkloadGVKFilter) String() string {
+	return fmt.Sprintf("WorkloadGVKFilter{gvk: %v}", f.gvk)
+}
+
+func (f *WorkloadGVKFilter) Filter(obj interface{}) (bool, error) {
+	o, ok := obj.(runtime.Object)
+
+	if !ok {
+		return false, errors.New("type was not a runtime.Object")
+	}
+
+	return o.GetObjectKind().GroupVersionKind() == f.gvk, nil
+}
+
 type WorkloadFilterForOwner struct {
-	workload  v1alpha1.Workload
+	workload  v1beta1.Workload
 	findOwner *rhmclient.FindOwnerHelper
 }
 
@@ -118,42 +148,97 @@ func (f *WorkloadFilterForOwner) String() string {
 }
 
 func (f *WorkloadFilterForOwner) Filter(obj interface{}) (bool, error) {
+	ok, _, err := f.walkOwners(obj)
+	return ok, err
+}
+
+// walkOwners returns true if an owner of the object matches the owner CRD
+// and the owners that were walked in order.
+func (f *WorkloadFilterForOwner) walkOwners(obj interface{}) (bool, []metav1.OwnerReference, error) {
 	meta, ok := obj.(metav1.Object)
 
 	if !ok {
-		return false, errors.New("type was not a metav1.Object")
-	}
-
-	owner := metav1.GetControllerOf(meta)
-
-	if owner == nil {
-		return false, nil
+		return false, nil, errors.New("type was not a metav1.Object")
 	}
 
-	if owner.APIVersion == f.workload.OwnerCRD.APIVersion && owner.Kind == f.workload.OwnerCRD.Kind {
-		return true, nil
+	depth := v1beta1.DefaultOwnerDepth
+	if f.workload.OwnerDepth != nil {
+		depth = *f.workload.OwnerDepth
 	}
 
 	namespace := meta.GetNamespace()
-	var err error
+	owners := f.ownersToWalk(meta.GetOwnerReferences())
+	visited := map[types.UID]bool{}
+	walked := []metav1.OwnerReference{}
 
-	for i := 0; i < 5; i++ {
-		owner, err = f.findOwner.FindOwner(owner.Name, namespace, owner)
+	// walk the owners a level at a time so the closest owners are checked first
+	for level := int32(1); len(owners) != 0; level++ {
+		nextOwners := []metav1.OwnerReference{}
 
-		if err != nil {
-			return false, err
-		}
+		for i := range owners {
+			owner := &owners[i]
 
-		if owner == nil {
-			return false, nil
+			if visited[owner.UID] {
+				continue
+			}
+			visited[owner.UID] = true
+			walked = append(walked, *owner)
+
+			if f.matchesOwnerCRD(owner) {
+				return true, walked, nil
+			}
+
+			if level == depth {
+				continue
+			}
+
+			ownerRefs, err := f.findOwner.FindOwners(owner.Name, namespace, owner)
+
+			if err != nil {
+				return false, walked, err
+			}
+
+			nextOwners = append(nextOwners, f.ownersToWalk(ownerRefs)...)
 		}
 
-		if owner.APIVersion == f.workload.OwnerCRD.APIVersion && owner.Kind == f.workload.OwnerCRD.Kind {
-			return true, nil
+		owners = nextOwners
+	}
+
+	return false, walked, nil
+}
+
+// ownersToWalk returns the controller reference, or all of the references if
+// the workload matches any owner.
+func (f *WorkloadFilterForOwner) ownersToWalk(ownerRefs []metav1.OwnerReference) []metav1.OwnerReference {
+	if f.workload.MatchAnyOwner {
+		return ownerRefs
+	}
+
+	for _, ownerRef := range ownerRefs {
+		if ownerRef.Controller != nil && *ownerRef.Controller {
+			return []metav1.OwnerReference{ownerRef}
 		}
 	}
 
-	return false, nil
+	return nil
+}
+
+func (f *WorkloadFilterForOwner) matchesOwnerCRD(owner *metav1.OwnerReference) bool {
+	ownerGV, err := schema.ParseGroupVersion(owner.APIVersion)
+
+	if err != nil {
+		return false
+	}
+
+	gv, err := schema.ParseGroupVersion(f.workload.OwnerCRD.APIVersion)
+
+	if err != nil {
+		return false
+	}
+
+	return gv.Group == ownerGV.Group &&
+		(gv.Version == v1beta1.OwnerWildcard || gv.Version == ownerGV.Version) &&
+		(f.workload.OwnerCRD.Kind == v1beta1.OwnerWildcard || f.workload.OwnerCRD.Kind == owner.Kind)
 }
 
 type WorkloadLabelFilter struct {
@@ -170,6 +255,10 @@ func (f *WorkloadLabelFilter) Filter(obj interface{}) (bool, error) {
 	return f.labelSelector.Matches(labels.Set(meta.GetLabels())), nil
 }
 
+func (f *WorkloadLabelFilter) String() string {
+	return fmt.Sprintf("WorkloadLabelFilter{labelSelector: %s}", f.labelSelector)
+}
+
 type WorkloadAnnotationFilter struct {
 	annotationSelector labels.Selector
 }
@@ -183,3 +272,96 @@ func (f *WorkloadAnnotationFilter) Filter(obj interface{}) (bool, error) {
 
 	return f.annotationSelector.Matches(labels.Set(meta.GetAnnotations())), nil
 }
+
+func (f *WorkloadAnnotationFilter) String() string {
+	return fmt.Sprintf("WorkloadAnnotationFilter{annotationSelector: %s}", f.annotationSelector)
+}
+
+type WorkloadFieldFilter struct {
+	fieldFilter v1beta1.FieldFilter
+	jsonPath    *jsonpath.JSONPath
+}
+
+func NewWorkloadFieldFilter(fieldFilter v1beta1.FieldFilter) (*WorkloadFieldFilter, error) {
+	j := jsonpath.New("fieldFilter").AllowMissingKeys(true)
+
+	if err := j.Parse(fieldFilter.JSONPath); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse jsonPath %s", fieldFilter.JSONPath)
+	}
+
+	return &WorkloadFieldFilter{
+		fieldFilter: fieldFilter,
+		jsonPath:    j,
+	}, nil
+}
+
+func (f *WorkloadFieldFilter) String() string {
+	return fmt.Sprintf("WorkloadFieldFilter{jsonPath: %s, operator: %s, values: %s}",
+		f.fieldFilter.JSONPath, f.fieldFilter.Operator, strings.Join(f.fieldFilter.Values, ","))
+}
+
+func (f *WorkloadFieldFilter) Filter(obj interface{}) (bool, error) {
+	var data map[string]interface{}
+
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		data = o.Object
+	case runtime.Object:
+		var err error
+		data, err = runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+
+		if err != nil {
+			return false, errors.Wrap(err, "failed to convert object")
+		}
+	default:
+		return false, errors.New("type was not a runtime.Object")
+	}
+
+	// the path was parsed when the filter was created so an error only means
+	// the object doesn't have the field, like an index past the end of a list
+	results, err := f.jsonPath.FindResults(data)
+
+	if err != nil {
+		results = nil
+	}
+
+	fields := []string{}
+
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || (value.Kind() == reflect.Interface && value.IsNil()) {
+				continue
+			}
+
+			fields = append(fields, fmt.Sprint(value.Interface()))
+		}
+	}
+
+	switch f.fieldFilter.Operator {
+	case v1beta1.FieldFilterOpExists:
+		return len(fields) != 0, nil
+	case v1beta1.FieldFilterOpDoesNotExist:
+		return len(fields) == 0, nil
+	case v1beta1.FieldFilterOpIn:
+		return f.anyField(fields, func(field, value string) bool { return field == value }), nil
+	case v1beta1.FieldFilterOpNotIn:
+		return !f.anyField(fields, func(field, value string) bool { return field == value }), nil
+	case v1beta1.FieldFilterOpHasPrefix:
+		return f.anyField(fields, strings.HasPrefix), nil
+	default:
+		return false, errors.Errorf("unsupported field filter operator %s", f.fieldFilter.Operator)
+	}
+}
+
+// anyField returns true if any field matches any value of the filter.
+func (f *WorkloadFieldFilter) anyField(fields []string, match func(field, value string) bool) bool {
+	for _, field := range fields {
+		for _, value := range f.fieldFilter.Values {
+			if match(field, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
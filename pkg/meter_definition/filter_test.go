@@ -0,0 +1,293 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func TestWorkloadFieldFilter(t *testing.T) {
+	storageClass := "premium"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "sidecar", Image: "quay.io/sidecar:1.0"},
+				{Name: "app", Image: "registry.example.com/licensed/app:1.0"},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+		},
+	}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "partner.metering.com/v1alpha1",
+		"kind":       "App",
+		"spec": map[string]interface{}{
+			"size": int64(3),
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		fieldFilter v1beta1.FieldFilter
+		obj         interface{}
+		expected    bool
+	}{
+		{
+			name:        "phase in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.status.phase}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"Running"}},
+			obj:         pod,
+			expected:    true,
+		},
+		{
+			name:        "phase not in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.status.phase}", Operator: v1beta1.FieldFilterOpNotIn, Values: []string{"Running"}},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "any image has prefix",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.containers[*].image}", Operator: v1beta1.FieldFilterOpHasPrefix, Values: []string{"registry.example.com/licensed/"}},
+			obj:         pod,
+			expected:    true,
+		},
+		{
+			name:        "no image has prefix",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.containers[*].image}", Operator: v1beta1.FieldFilterOpHasPrefix, Values: []string{"docker.io/"}},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "storage class in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.storageClassName}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"standard", "premium"}},
+			obj:         pvc,
+			expected:    true,
+		},
+		{
+			name:        "missing field does not exist",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.volumeName}", Operator: v1beta1.FieldFilterOpDoesNotExist},
+			obj:         pvc,
+			expected:    true,
+		},
+		{
+			name:        "missing field exists",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.volumeName}", Operator: v1beta1.FieldFilterOpExists},
+			obj:         pvc,
+			expected:    false,
+		},
+		{
+			name:        "index out of bounds in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.containers[2].image}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"quay.io/sidecar:1.0"}},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "index out of bounds exists",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.containers[2].image}", Operator: v1beta1.FieldFilterOpExists},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "index out of bounds does not exist",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.containers[2].image}", Operator: v1beta1.FieldFilterOpDoesNotExist},
+			obj:         pod,
+			expected:    true,
+		},
+		{
+			name:        "missing list exists",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.initContainers[0].image}", Operator: v1beta1.FieldFilterOpExists},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "index into a value that is not a list",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.status.phase[0]}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"Running"}},
+			obj:         pod,
+			expected:    false,
+		},
+		{
+			name:        "missing nested field in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.template.size}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"3"}},
+			obj:         cr,
+			expected:    false,
+		},
+		{
+			name:        "unstructured number in",
+			fieldFilter: v1beta1.FieldFilter{JSONPath: "{.spec.size}", Operator: v1beta1.FieldFilterOpIn, Values: []string{"3"}},
+			obj:         cr,
+			expected:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewWorkloadFieldFilter(test.fieldFilter)
+			require.NoError(t, err)
+
+			ans, err := filter.Filter(test.obj)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ans)
+		})
+	}
+
+	_, err := NewWorkloadFieldFilter(v1beta1.FieldFilter{JSONPath: "{.status.phase", Operator: v1beta1.FieldFilterOpExists})
+	assert.Error(t, err)
+}
+
+func newOwnerRef(apiVersion, kind, name string, controller bool) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+		UID:        types.UID(kind + "-" + name),
+		Controller: &controller,
+	}
+}
+
+func newOwner(apiVersion, kind, name string, owners ...metav1.OwnerReference) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(kind + "-" + name),
+			OwnerReferences: owners,
+		},
+	}
+}
+
+func TestWorkloadFilterForOwner(t *testing.T) {
+	apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+	partner := schema.GroupVersion{Group: "partner.metering.com", Version: "v2"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{apps, partner})
+	mapper.Add(apps.WithKind("ReplicaSet"), meta.RESTScopeNamespace)
+	mapper.Add(apps.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(partner.WithKind("App"), meta.RESTScopeNamespace)
+
+	// the app is not the controller of the deployment
+	metadataScheme := runtime.NewScheme()
+	require.NoError(t, metav1.AddMetaToScheme(metadataScheme))
+
+	client := metadatafake.NewSimpleMetadataClient(metadataScheme,
+		newOwner("apps/v1", "ReplicaSet", "app-rs",
+			newOwnerRef("apps/v1", "Deployment", "app", true)),
+		newOwner("apps/v1", "Deployment", "app",
+			newOwnerRef("partner.metering.com/v2", "App", "app", false)),
+		newOwner("partner.metering.com/v2", "App", "app"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	findOwner := rhmclient.NewFindOwnerHelper(ctx, client, mapper)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app-rs-1",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{newOwnerRef("apps/v1", "ReplicaSet", "app-rs", true)},
+		},
+	}
+
+	depth := int32(2)
+
+	tests := []struct {
+		name          string
+		ownerCRD      common.GroupVersionKind
+		ownerDepth    *int32
+		matchAnyOwner bool
+		expected      bool
+	}{
+		{
+			name:     "exact controller",
+			ownerCRD: common.GroupVersionKind{APIVersion: "apps/v1", Kind: "Deployment"},
+			expected: true,
+		},
+		{
+			name:     "other version",
+			ownerCRD: common.GroupVersionKind{APIVersion: "apps/v1beta1", Kind: "Deployment"},
+			expected: false,
+		},
+		{
+			name:     "version wildcard",
+			ownerCRD: common.GroupVersionKind{APIVersion: "apps/*", Kind: "Deployment"},
+			expected: true,
+		},
+		{
+			name:     "owner not a controller",
+			ownerCRD: common.GroupVersionKind{APIVersion: "partner.metering.com/*", Kind: "App"},
+			expected: false,
+		},
+		{
+			name:          "any owner",
+			ownerCRD:      common.GroupVersionKind{APIVersion: "partner.metering.com/*", Kind: "App"},
+			matchAnyOwner: true,
+			expected:      true,
+		},
+		{
+			name:          "any kind of group",
+			ownerCRD:      common.GroupVersionKind{APIVersion: "partner.metering.com/v2", Kind: "*"},
+			matchAnyOwner: true,
+			expected:      true,
+		},
+		{
+			name:          "too deep",
+			ownerCRD:      common.GroupVersionKind{APIVersion: "partner.metering.com/v2", Kind: "App"},
+			ownerDepth:    &depth,
+			matchAnyOwner: true,
+			expected:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ownerCRD := test.ownerCRD
+			filter := &WorkloadFilterForOwner{
+				workload: v1beta1.Workload{
+					Name:          "app-pods",
+					WorkloadType:  v1beta1.WorkloadTypePod,
+					OwnerCRD:      &ownerCRD,
+					OwnerDepth:    test.ownerDepth,
+					MatchAnyOwner: test.matchAnyOwner,
+				},
+				findOwner: findOwner,
+			}
+
+			ans, err := filter.Filter(pod)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ans)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"sort"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Indices of the objects of the MeterDefinitionStore.
+const (
+	ObjectUIDIndex    = "objectUID"
+	MeterDefUIDIndex  = "meterDefUID"
+	NamespaceIndex    = "namespace"
+	WorkloadNameIndex = "workloadName"
+)
+
+func (k ObjectResourceKey) String() string {
+	return string(k.ObjectUID) + "/" + string(k.MeterDefUID)
+}
+
+// objectResource is an object that matched a workload of a meter definition.
+type objectResource struct {
+	key    ObjectResourceKey
+	object interface{}
+	value  *ObjectResourceValue
+}
+
+func objectResourceKeyFunc(obj interface{}) (string, error) {
+	resource, ok := obj.(*objectResource)
+	if !ok {
+		return "", errors.Errorf("unexpected type %T", obj)
+	}
+
+	return resource.key.String(), nil
+}
+
+func newObjectResourceIndexer() cache.Indexer {
+	index := func(f func(*objectResource) string) cache.IndexFunc {
+		return func(obj interface{}) ([]string, error) {
+			resource, ok := obj.(*objectResource)
+			if !ok {
+				return nil, errors.Errorf("unexpected type %T", obj)
+			}
+
+			return []string{f(resource)}, nil
+		}
+	}
+
+	return cache.NewIndexer(objectResourceKeyFunc, cache.Indexers{
+		ObjectUIDIndex: index(func(r *objectResource) string {
+			return string(r.key.ObjectUID)
+		}),
+		MeterDefUIDIndex: index(func(r *objectResource) string {
+			return string(r.key.MeterDefUID)
+		}),
+		NamespaceIndex: index(func(r *objectResource) string {
+			return r.value.WorkloadResource.Namespace
+		}),
+		WorkloadNameIndex: index(func(r *objectResource) string {
+			return r.value.WorkloadResource.ReferencedWorkloadName
+		}),
+	})
+}
+
+// ObjectResourceQuery selects objects of the MeterDefinitionStore. Empty
+// fields match any value.
+type ObjectResourceQuery struct {
+	ObjectUID    types.UID
+	MeterDefUID  types.UID
+	Namespace    string
+	WorkloadName string
+}
+
+func (q ObjectResourceQuery) matches(r *objectResource) bool {
+	return (q.ObjectUID == "" || ObjectUID(q.ObjectUID) == r.key.ObjectUID) &&
+		(q.MeterDefUID == "" || MeterDefUID(q.MeterDefUID) == r.key.MeterDefUID) &&
+		(q.Namespace == "" || q.Namespace == r.value.WorkloadResource.Namespace) &&
+		(q.WorkloadName == "" || q.WorkloadName == r.value.WorkloadResource.ReferencedWorkloadName)
+}
+
+// Query returns the values of the matched objects selected by the query,
+// sorted by meter definition, namespace and name. The most selective index
+// of the query is used to find them.
+func (s *MeterDefinitionStore) Query(query ObjectResourceQuery) []*ObjectResourceValue {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vals := []*ObjectResourceValue{}
+	for _, resource := range s.queryObjectResources(query) {
+		if resource.value.Matched {
+			vals = append(vals, resource.value)
+		}
+	}
+
+	sort.Slice(vals, func(i, j int) bool {
+		a, b := vals[i], vals[j]
+		switch {
+		case a.MeterDef != b.MeterDef:
+			return a.MeterDef.String() < b.MeterDef.String()
+		case a.WorkloadResource.Namespace != b.WorkloadResource.Namespace:
+			return a.WorkloadResource.Namespace < b.WorkloadResource.Namespace
+		default:
+			return a.WorkloadResource.Name < b.WorkloadResource.Name
+		}
+	})
+
+	return vals
+}
+
+func (s *MeterDefinitionStore) queryObjectResources(query ObjectResourceQuery) []*objectResource {
+	var items []interface{}
+	var err error
+
+	switch {
+	case query.ObjectUID != "":
+		items, err = s.objectResources.ByIndex(ObjectUIDIndex, string(query.ObjectUID))
+	case query.MeterDefUID != "":
+		items, err = s.objectResources.ByIndex(MeterDefUIDIndex, string(query.MeterDefUID))
+	case query.WorkloadName != "":
+		items, err = s.objectResources.ByIndex(WorkloadNameIndex, query.WorkloadName)
+	case query.Namespace != "":
+		items, err = s.objectResources.ByIndex(NamespaceIndex, query.Namespace)
+	default:
+		items = s.objectResources.List()
+	}
+
+	if err != nil {
+		s.log.Error(err, "failed to query objects")
+		return nil
+	}
+
+	resources := []*objectResource{}
+	for _, item := range items {
+		if resource := item.(*objectResource); query.matches(resource) {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources
+}
+
+func (s *MeterDefinitionStore) getObjectResource(key ObjectResourceKey) (*objectResource, bool) {
+	item, exists, err := s.objectResources.GetByKey(key.String())
+	if err != nil || !exists {
+		return nil, false
+	}
+
+	return item.(*objectResource), true
+}
+
+func (s *MeterDefinitionStore) setObjectResource(key ObjectResourceKey, obj interface{}, value *ObjectResourceValue) {
+	if err := s.objectResources.Add(&objectResource{key: key, object: obj, value: value}); err != nil {
+		s.log.Error(err, "failed to add object", "key", key.String())
+	}
+}
+
+func (s *MeterDefinitionStore) deleteObjectResource(resource *objectResource) {
+	if err := s.objectResources.Delete(resource); err != nil {
+		s.log.Error(err, "failed to delete object", "key", resource.key.String())
+	}
+}
+
+// unmatchObjectResource removes the object from the meter definition it no
+// longer matches and broadcasts a Delete message. If the object still matches
+// other meter definitions it is broadcast again as an Add so listeners keep
+// it. The mutex must be held.
+func (s *MeterDefinitionStore) unmatchObjectResource(resource *objectResource, obj interface{}) {
+	s.deleteObjectResource(resource)
+
+	s.broadcast(&ObjectResourceMessage{
+		Action:              DeleteMessageAction,
+		Object:              obj,
+		ObjectResourceValue: resource.value,
+	})
+
+	for _, other := range s.queryObjectResources(ObjectResourceQuery{ObjectUID: types.UID(resource.key.ObjectUID)}) {
+		if other.value.Matched {
+			s.broadcast(&ObjectResourceMessage{
+				Action:              AddMessageAction,
+				Object:              obj,
+				ObjectResourceValue: other.value,
+			})
+			break
+		}
+	}
+}
+
+// isUnchanged returns true if the object has the resource version it had
+// when it was matched with the same meter rules, for example when the
+// objects are listed again.
+func (r *objectResource) isUnchanged(obj interface{}, lookup *MeterDefinitionLookupFilter) bool {
+	if r.value.MeterDefHash != lookup.Hash() {
+		return false
+	}
+
+	current, err := meta.Accessor(r.object)
+	if err != nil {
+		return false
+	}
+
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+
+	return o.GetResourceVersion() != "" && o.GetResourceVersion() == current.GetResourceVersion()
+}
+
+// objectsByUID returns the objects in the store by UID.
+func (s *MeterDefinitionStore) objectsByUID() map[string]interface{} {
+	objs := map[string]interface{}{}
+	for _, item := range s.objectResources.List() {
+		resource := item.(*objectResource)
+		objs[string(resource.key.ObjectUID)] = resource.object
+	}
+	return objs
+}
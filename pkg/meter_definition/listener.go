@@ -0,0 +1,244 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+const (
+	// ResyncMessageAction messages carry the objects of the store in Objects.
+	// Listeners replace their state with them.
+	ResyncMessageAction ObjectResourceMessageAction = "Resync"
+
+	defaultListenerQueueSize      = 1000
+	defaultListenerResyncInterval = 10 * time.Minute
+)
+
+// ObjectResource is an object that matched a workload of a meter definition.
+type ObjectResource struct {
+	Object interface{}
+	*ObjectResourceValue
+}
+
+type listenerMetrics struct {
+	dropped   *prometheus.CounterVec
+	coalesced *prometheus.CounterVec
+	resyncs   *prometheus.CounterVec
+}
+
+func newListenerMetrics() *listenerMetrics {
+	return &listenerMetrics{
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "meterdefinition_store_listener_dropped_messages_total",
+			Help: "Messages dropped because the queue of the listener was full.",
+		}, []string{"listener"}),
+		coalesced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "meterdefinition_store_listener_coalesced_messages_total",
+			Help: "Queued messages replaced by a newer message for the same object and meter definition.",
+		}, []string{"listener"}),
+		resyncs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "meterdefinition_store_listener_resyncs_total",
+			Help: "Resyncs of the objects of the store sent to the listener.",
+		}, []string{"listener"}),
+	}
+}
+
+// Collectors returns the metrics of the listeners of the store to register.
+func (s *MeterDefinitionStore) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		s.listenerMetrics.dropped,
+		s.listenerMetrics.coalesced,
+		s.listenerMetrics.resyncs,
+	}
+}
+
+// listener delivers the messages of the store to a channel in order without
+// blocking the store. Messages wait in a bounded queue until the channel is
+// ready. A newer message for the same object and meter definition replaces
+// the queued one; when the queue is full the oldest message is dropped. The
+// periodic resync converges the listener after a drop.
+type listener struct {
+	name string
+	out  chan *ObjectResourceMessage
+	size int
+
+	mutex  sync.Mutex
+	queue  *list.List
+	queued map[string]*list.Element
+	ready  chan struct{}
+
+	dropped   prometheus.Counter
+	coalesced prometheus.Counter
+}
+
+func newListener(name string, out chan *ObjectResourceMessage, size int, metrics *listenerMetrics) *listener {
+	return &listener{
+		name:      name,
+		out:       out,
+		size:      size,
+		queue:     list.New(),
+		queued:    make(map[string]*list.Element),
+		ready:     make(chan struct{}, 1),
+		dropped:   metrics.dropped.WithLabelValues(name),
+		coalesced: metrics.coalesced.WithLabelValues(name),
+	}
+}
+
+// messageKey is the object and meter definition of the message. Resync
+// messages share a key so only the latest is delivered.
+func messageKey(msg *ObjectResourceMessage) string {
+	if msg.Action == ResyncMessageAction || msg.ObjectResourceValue == nil {
+		return string(ResyncMessageAction)
+	}
+
+	key := msg.MeterDef.String()
+	if o, err := meta.Accessor(msg.Object); err == nil {
+		key = string(o.GetUID()) + "/" + key
+	}
+
+	return key
+}
+
+func (l *listener) enqueue(msg *ObjectResourceMessage) {
+	key := messageKey(msg)
+
+	l.mutex.Lock()
+
+	if elem, ok := l.queued[key]; ok {
+		// moved to the back to keep the order with the messages of other keys
+		l.queue.Remove(elem)
+		l.coalesced.Inc()
+	} else if l.queue.Len() >= l.size {
+		oldest := l.queue.Front()
+		l.queue.Remove(oldest)
+		delete(l.queued, messageKey(oldest.Value.(*ObjectResourceMessage)))
+		l.dropped.Inc()
+	}
+
+	l.queued[key] = l.queue.PushBack(msg)
+
+	l.mutex.Unlock()
+
+	select {
+	case l.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (l *listener) dequeue() *ObjectResourceMessage {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	elem := l.queue.Front()
+	if elem == nil {
+		return nil
+	}
+
+	l.queue.Remove(elem)
+	msg := elem.Value.(*ObjectResourceMessage)
+	delete(l.queued, messageKey(msg))
+
+	return msg
+}
+
+// run sends the queued messages to the channel until the context is done.
+func (l *listener) run(ctx context.Context) {
+	for {
+		msg := l.dequeue()
+
+		if msg == nil {
+			select {
+			case <-l.ready:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case l.out <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// resync sends the objects of the store to every listener so listeners that
+// missed messages converge to the state of the store.
+func (s *MeterDefinitionStore) resync() {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	objects := s.snapshot()
+
+	s.log.Info("resyncing listeners", "objects", len(objects), "listeners", len(s.listeners))
+
+	for _, l := range s.listeners {
+		s.resyncListener(l, objects)
+	}
+}
+
+// snapshot returns the matched objects of the store. The caller must hold
+// the mutex.
+func (s *MeterDefinitionStore) snapshot() []ObjectResource {
+	objects := []ObjectResource{}
+	for _, item := range s.objectResources.List() {
+		resource := item.(*objectResource)
+
+		if resource.value.Matched {
+			objects = append(objects, ObjectResource{
+				Object:              resource.object,
+				ObjectResourceValue: resource.value,
+			})
+		}
+	}
+
+	return objects
+}
+
+func (s *MeterDefinitionStore) resyncListener(l *listener, objects []ObjectResource) {
+	s.listenerMetrics.resyncs.WithLabelValues(l.name).Inc()
+	l.enqueue(&ObjectResourceMessage{
+		Action:  ResyncMessageAction,
+		Objects: objects,
+	})
+}
+
+func (s *MeterDefinitionStore) runResync() {
+	ticker := time.NewTicker(s.resyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.resync()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// SetResyncInterval sets how often the objects of the store are sent to the
+// listeners.
+func (s *MeterDefinitionStore) SetResyncInterval(interval time.Duration) {
+	s.resyncInterval = interval
+}
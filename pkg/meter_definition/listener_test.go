@@ -0,0 +1,117 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestListener(t *testing.T) {
+	newMessage := func(action ObjectResourceMessageAction, uid string) *ObjectResourceMessage {
+		return &ObjectResourceMessage{
+			Action: action,
+			Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}},
+			ObjectResourceValue: &ObjectResourceValue{
+				MeterDef: types.NamespacedName{Name: "meterdef", Namespace: "ns"},
+			},
+		}
+	}
+
+	actions := func(msgs []*ObjectResourceMessage) []string {
+		out := []string{}
+		for _, msg := range msgs {
+			out = append(out, string(msg.Action)+":"+string(msg.Object.(*corev1.Pod).UID))
+		}
+		return out
+	}
+
+	t.Run("coalesces messages of the same object", func(t *testing.T) {
+		l := newListener("test", nil, 10, newListenerMetrics())
+
+		l.enqueue(newMessage(AddMessageAction, "a"))
+		l.enqueue(newMessage(AddMessageAction, "b"))
+		l.enqueue(newMessage(DeleteMessageAction, "a"))
+
+		assert.Equal(t, []string{"Add:b", "Delete:a"}, actions(drainMessages(l)))
+	})
+
+	t.Run("drops the oldest message when full", func(t *testing.T) {
+		l := newListener("test", nil, 2, newListenerMetrics())
+
+		l.enqueue(newMessage(AddMessageAction, "a"))
+		l.enqueue(newMessage(AddMessageAction, "b"))
+		l.enqueue(newMessage(AddMessageAction, "c"))
+
+		assert.Equal(t, []string{"Add:b", "Add:c"}, actions(drainMessages(l)))
+	})
+
+	t.Run("delivers queued messages in order", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		out := make(chan *ObjectResourceMessage)
+		l := newListener("test", out, 10, newListenerMetrics())
+
+		l.enqueue(newMessage(AddMessageAction, "a"))
+		l.enqueue(newMessage(AddMessageAction, "b"))
+		go l.run(ctx)
+		l.enqueue(newMessage(AddMessageAction, "c"))
+
+		msgs := []*ObjectResourceMessage{}
+		for len(msgs) < 3 {
+			select {
+			case msg := <-out:
+				msgs = append(msgs, msg)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for messages")
+			}
+		}
+
+		assert.Equal(t, []string{"Add:a", "Add:b", "Add:c"}, actions(msgs))
+	})
+
+	t.Run("resync sends the matched objects", func(t *testing.T) {
+		store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("listener_test"), nil, nil, nil, nil, nil, nil, nil, nil)
+		l := newTestListener(store)
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "a"}}
+		store.setObjectResource(ObjectResourceKey{ObjectUID: ObjectUID(pod.UID), MeterDefUID: "meterdef-uid"}, pod, &ObjectResourceValue{
+			MeterDef: types.NamespacedName{Name: "meterdef", Namespace: "ns"},
+			Matched:  true,
+			WorkloadResource: &v1beta1.WorkloadResource{
+				ReferencedWorkloadName: "app-pods",
+			},
+		})
+
+		store.resync()
+		store.resync()
+
+		msgs := drainMessages(l)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, ResyncMessageAction, msgs[0].Action)
+		require.Len(t, msgs[0].Objects, 1)
+		assert.Equal(t, pod, msgs[0].Objects[0].Object)
+	})
+}
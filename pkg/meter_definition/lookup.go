@@ -16,8 +16,10 @@ package meter_definition
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"emperror.dev/errors"
@@ -25,12 +27,17 @@ import (
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/go-logr/logr"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -38,11 +45,17 @@ import (
 
 type MeterDefinitionLookupFilter struct {
 	MeterDefName types.NamespacedName
-	workloads    map[string]v1alpha1.Workload
+	hash         string
+	workloads    map[string]v1beta1.Workload
 	filters      map[string][]FilterRuntimeObject
 	cc           ClientCommandRunner
 	log          logr.Logger
 	findOwner    *rhmclient.FindOwnerHelper
+
+	// scope is the vertex the namespaces of the workloads are found from,
+	// it is shared by the filters of all workloads
+	scope           namespaceScope
+	namespaceFilter *WorkloadNamespaceFilter
 }
 
 var (
@@ -51,30 +64,64 @@ var (
 
 func NewMeterDefinitionLookupFilter(
 	cc ClientCommandRunner,
-	meterdef *v1alpha1.MeterDefinition,
+	meterdef *v1beta1.MeterDefinition,
+	findOwner *rhmclient.FindOwnerHelper,
+) (*MeterDefinitionLookupFilter, error) {
+	return newLookupFilter(cc, meterdef, nil, findOwner)
+}
+
+// NewClusterMeterDefinitionLookupFilter builds the lookup of a cluster meter
+// definition. Its MeterDefName has no namespace.
+func NewClusterMeterDefinitionLookupFilter(
+	cc ClientCommandRunner,
+	clusterMeterdef *v1beta1.ClusterMeterDefinition,
+	findOwner *rhmclient.FindOwnerHelper,
+) (*MeterDefinitionLookupFilter, error) {
+	return newLookupFilter(cc, clusterMeterdef.ToMeterDefinition(), clusterMeterdef.Spec.ExcludedNamespaces, findOwner)
+}
+
+func newLookupFilter(
+	cc ClientCommandRunner,
+	meterdef *v1beta1.MeterDefinition,
+	excludedNamespaces []string,
 	findOwner *rhmclient.FindOwnerHelper,
 ) (*MeterDefinitionLookupFilter, error) {
 	log.Info("building filters", "meterdef", meterdef)
 
+	hash, err := MeterDefinitionHash(meterdef, excludedNamespaces)
+	if err != nil {
+		log.Error(err, "")
+		return nil, err
+	}
+
 	s := &MeterDefinitionLookupFilter{
 		MeterDefName: types.NamespacedName{Name: meterdef.Name, Namespace: meterdef.Namespace},
+		hash:         hash,
 		findOwner:    findOwner,
 		cc:           cc,
 		log:          log.WithValues("meterdefName", meterdef.Name, "meterdefNamespace", meterdef.Namespace),
 	}
 
+	s.scope, err = newNamespaceScope(meterdef)
+	if err != nil {
+		log.Error(err, "")
+		return nil, err
+	}
+
 	ns, err := s.findNamespaces(meterdef)
 	if err != nil {
 		log.Error(err, "")
 		return nil, err
 	}
-	filters, err := s.createFilters(meterdef, ns)
+
+	s.namespaceFilter = &WorkloadNamespaceFilter{namespaces: ns, excluded: excludedNamespaces}
+	filters, err := s.createFilters(meterdef)
 	if err != nil {
 		s.log.Error(err, "")
 		return nil, err
 	}
 
-	workloads := map[string]v1alpha1.Workload{}
+	workloads := map[string]v1beta1.Workload{}
 	for _, wkld := range meterdef.Spec.Workloads {
 		workloads[wkld.Name] = wkld
 	}
@@ -85,23 +132,46 @@ func NewMeterDefinitionLookupFilter(
 	return s, nil
 }
 
+// Hash identifies the meter rules the lookup was built from.
 func (s *MeterDefinitionLookupFilter) Hash() string {
-	h := xxhash.New()
+	return s.hash
+}
 
-	h.Write([]byte(fmt.Sprintf("%v", s.MeterDefName)))
-	for k, v := range s.workloads {
-		h.Write([]byte(fmt.Sprintf("%v", k)))
-		h.Write([]byte(fmt.Sprintf("%v", v)))
+// MeterDefinitionHash hashes the name and the meter rules of a meter
+// definition. It is the same for equal rules regardless of the order of the
+// workloads, so it can identify the revision of a meter definition.
+func MeterDefinitionHash(meterdef *v1beta1.MeterDefinition, excludedNamespaces []string) (string, error) {
+	spec := meterdef.Spec.DeepCopy()
+
+	// the installing CSV is not a meter rule
+	spec.InstalledBy = nil
+	sort.Slice(spec.Workloads, func(i, j int) bool {
+		return spec.Workloads[i].Name < spec.Workloads[j].Name
+	})
+
+	excluded := append([]string{}, excludedNamespaces...)
+	sort.Strings(excluded)
+
+	data, err := json.Marshal(struct {
+		Spec               *v1beta1.MeterDefinitionSpec `json:"spec"`
+		ExcludedNamespaces []string                     `json:"excludedNamespaces"`
+	}{spec, excluded})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal meterdef spec")
 	}
 
-	return fmt.Sprintf("%x", h.Sum(nil))
+	h := xxhash.New()
+	h.Write([]byte(types.NamespacedName{Name: meterdef.Name, Namespace: meterdef.Namespace}.String()))
+	h.Write(data)
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
 func (s *MeterDefinitionLookupFilter) String() string {
 	return fmt.Sprintf("MeterDef{workloads=%v, filters=%v}", len(s.workloads), len(s.filters))
 }
 
-func (s *MeterDefinitionLookupFilter) FindMatchingWorkloads(obj interface{}) (*v1alpha1.Workload, bool, error) {
+func (s *MeterDefinitionLookupFilter) FindMatchingWorkloads(obj interface{}) (*v1beta1.Workload, bool, error) {
 	o, ok := obj.(metav1.Object)
 
 	if !ok {
@@ -144,14 +214,14 @@ func (s *MeterDefinitionLookupFilter) FindMatchingWorkloads(obj interface{}) (*v
 }
 
 func (s *MeterDefinitionLookupFilter) findNamespaces(
-	instance *v1alpha1.MeterDefinition,
+	instance *v1beta1.MeterDefinition,
 ) (namespaces []string, err error) {
 	cc := s.cc
 	functionError := errors.NewWithDetails("error with findNamespaces", "meterdef", instance.Name+"/"+instance.Namespace)
 	reqLogger := s.log.WithValues("func", "findNamespaces", "meterdef", instance.Name+"/"+instance.Namespace)
 
 	switch instance.Spec.WorkloadVertexType {
-	case v1alpha1.WorkloadVertexOperatorGroup:
+	case v1beta1.WorkloadVertexOperatorGroup:
 		reqLogger.Info("operatorGroup vertex")
 		csv := &olmv1alpha1.ClusterServiceVersion{}
 
@@ -180,29 +250,23 @@ func (s *MeterDefinitionLookupFilter) findNamespaces(
 			return
 		}
 
-		olmNamespacesStr, ok := csv.GetAnnotations()["olm.targetNamespaces"]
+		namespaces, err = csvTargetNamespaces(csv)
 
-		if !ok {
-			err = errors.Wrap(functionError, "olmNamspaces on CSV not found")
+		if err != nil {
+			err = errors.Wrap(functionError, err.Error())
 			// set condition and requeue for later
 			reqLogger.Error(err, "")
 			return
 		}
 
-		if olmNamespacesStr == "" {
-			reqLogger.Info("operatorGroup is for all namespaces")
-			namespaces = []string{corev1.NamespaceAll}
-			return
-		}
-
-		namespaces = strings.Split(olmNamespacesStr, ",")
 		return
-	case v1alpha1.WorkloadVertexNamespace:
+	case v1beta1.WorkloadVertexNamespace:
 		reqLogger.Info("namespace vertex with filter")
 
 		if instance.Spec.VertexLabelSelector == nil {
 			reqLogger.Info("namespace vertex is for all namespaces")
-			break
+			namespaces = []string{corev1.NamespaceAll}
+			return
 		}
 
 		namespaceList := &corev1.NamespaceList{}
@@ -234,9 +298,24 @@ func (s *MeterDefinitionLookupFilter) findNamespaces(
 	return
 }
 
+// csvTargetNamespaces returns the namespaces of the operator group of the
+// CSV. An operator group for all namespaces returns NamespaceAll.
+func csvTargetNamespaces(csv *olmv1alpha1.ClusterServiceVersion) ([]string, error) {
+	olmNamespacesStr, ok := csv.GetAnnotations()["olm.targetNamespaces"]
+
+	if !ok {
+		return nil, errors.New("olmNamspaces on CSV not found")
+	}
+
+	if olmNamespacesStr == "" {
+		return []string{corev1.NamespaceAll}, nil
+	}
+
+	return strings.Split(olmNamespacesStr, ","), nil
+}
+
 func (s *MeterDefinitionLookupFilter) createFilters(
-	instance *v1alpha1.MeterDefinition,
-	namespaces []string,
+	instance *v1beta1.MeterDefinition,
 ) (map[string][]FilterRuntimeObject, error) {
 
 	// Bottom Up
@@ -244,24 +323,47 @@ func (s *MeterDefinitionLookupFilter) createFilters(
 	filters := make(map[string][]FilterRuntimeObject)
 
 	for _, workload := range instance.Spec.Workloads {
-		runtimeFilters := []FilterRuntimeObject{&WorkloadNamespaceFilter{namespaces: namespaces}}
+		runtimeFilters := []FilterRuntimeObject{s.namespaceFilter}
 
 		var err error
 		typeFilter := &WorkloadTypeFilter{}
 		switch workload.WorkloadType {
-		case v1alpha1.WorkloadTypePod:
+		case v1beta1.WorkloadTypePod:
 			gvk := reflect.TypeOf(&corev1.Pod{})
 			typeFilter.gvks = []reflect.Type{gvk}
-		case v1alpha1.WorkloadTypePVC:
+		case v1beta1.WorkloadTypePVC:
 			gvk := reflect.TypeOf(&corev1.PersistentVolumeClaim{})
 			typeFilter.gvks = []reflect.Type{gvk}
-		case v1alpha1.WorkloadTypeService:
+		case v1beta1.WorkloadTypeService:
 			gvk1 := reflect.TypeOf(&corev1.Service{})
 			typeFilter.gvks = []reflect.Type{gvk1}
-		case v1alpha1.WorkloadTypeServiceMonitor:
+		case v1beta1.WorkloadTypeServiceMonitor:
 			gvk1 := reflect.TypeOf(&corev1.Service{})
 			gvk2 := reflect.TypeOf(&monitoringv1.ServiceMonitor{})
 			typeFilter.gvks = []reflect.Type{gvk1, gvk2}
+		case v1beta1.WorkloadTypeDeployment:
+			gvk := reflect.TypeOf(&appsv1.Deployment{})
+			typeFilter.gvks = []reflect.Type{gvk}
+		case v1beta1.WorkloadTypeStatefulSet:
+			gvk := reflect.TypeOf(&appsv1.StatefulSet{})
+			typeFilter.gvks = []reflect.Type{gvk}
+		case v1beta1.WorkloadTypeDaemonSet:
+			gvk := reflect.TypeOf(&appsv1.DaemonSet{})
+			typeFilter.gvks = []reflect.Type{gvk}
+		case v1beta1.WorkloadTypeJob:
+			gvk := reflect.TypeOf(&batchv1.Job{})
+			typeFilter.gvks = []reflect.Type{gvk}
+		case v1beta1.WorkloadTypeCronJob:
+			gvk := reflect.TypeOf(&batchv1beta1.CronJob{})
+			typeFilter.gvks = []reflect.Type{gvk}
+		case v1beta1.WorkloadTypeCustomResource:
+			if workload.CustomResource == nil {
+				err = errors.NewWithDetails("customResource is required", "workload", workload.Name)
+				break
+			}
+
+			gvk := reflect.TypeOf(&unstructured.Unstructured{})
+			typeFilter.gvks = []reflect.Type{gvk}
 		default:
 			err = errors.NewWithDetails("unknown type filter", "type", workload.WorkloadType)
 		}
@@ -272,8 +374,13 @@ func (s *MeterDefinitionLookupFilter) createFilters(
 
 		runtimeFilters = append(runtimeFilters, typeFilter)
 
-		if workload.LabelSelector == nil && workload.AnnotationSelector == nil && workload.OwnerCRD == nil {
-			return nil, errors.New("workload isn't specific enough. 1 of owner, annotationSelector or labelSelector is required.")
+		if workload.WorkloadType == v1beta1.WorkloadTypeCustomResource {
+			// the kind of the custom resource is specific enough
+			runtimeFilters = append(runtimeFilters, &WorkloadGVKFilter{
+				gvk: schema.FromAPIVersionAndKind(workload.CustomResource.APIVersion, workload.CustomResource.Kind),
+			})
+		} else if workload.LabelSelector == nil && workload.AnnotationSelector == nil && workload.OwnerCRD == nil && len(workload.FieldFilters) == 0 {
+			return nil, errors.New("workload isn't specific enough. 1 of owner, annotationSelector, labelSelector or fieldFilters is required.")
 		}
 
 		if workload.LabelSelector != nil {
@@ -300,6 +407,16 @@ func (s *MeterDefinitionLookupFilter) createFilters(
 			})
 		}
 
+		for _, fieldFilter := range workload.FieldFilters {
+			filter, err := NewWorkloadFieldFilter(fieldFilter)
+
+			if err != nil {
+				return nil, err
+			}
+
+			runtimeFilters = append(runtimeFilters, filter)
+		}
+
 		if workload.OwnerCRD != nil {
 			runtimeFilters = append(runtimeFilters, &WorkloadFilterForOwner{
 				workload:  workload,
@@ -0,0 +1,131 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"testing"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestClusterMeterDefinitionLookupFilter(t *testing.T) {
+	metered := map[string]string{"metered": "true"}
+
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	k8sClient := fake.NewFakeClientWithScheme(testScheme,
+		newNamespace("apps", metered),
+		newNamespace("kube-system", metered),
+		newNamespace("other", nil),
+	)
+	cc := reconcileutils.NewClientCommand(k8sClient, testScheme, logf.Log.WithName("lookup_test"))
+
+	clusterMeterdef := &v1beta1.ClusterMeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-agent", UID: "cluster-uid"},
+		Spec: v1beta1.ClusterMeterDefinitionSpec{
+			Group:              "partner.metering.com",
+			Kind:               "NodeAgent",
+			ExcludedNamespaces: []string{"kube-system"},
+			Workloads: []v1beta1.Workload{
+				{
+					Name:         "node-agent-pods",
+					WorkloadType: v1beta1.WorkloadTypePod,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "node-agent"},
+					},
+				},
+			},
+		},
+	}
+
+	newPod := func(namespace string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-agent",
+			Namespace: namespace,
+			Labels:    map[string]string{"app": "node-agent"},
+		}}
+	}
+
+	tests := []struct {
+		name              string
+		namespaceSelector *metav1.LabelSelector
+		expected          map[string]bool
+	}{
+		{
+			name:     "all namespaces",
+			expected: map[string]bool{"apps": true, "kube-system": false, "other": true},
+		},
+		{
+			name:              "selected namespaces",
+			namespaceSelector: &metav1.LabelSelector{MatchLabels: metered},
+			expected:          map[string]bool{"apps": true, "kube-system": false, "other": false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clusterMeterdef.Spec.NamespaceSelector = test.namespaceSelector
+
+			lookup, err := NewClusterMeterDefinitionLookupFilter(cc, clusterMeterdef, nil)
+			require.NoError(t, err)
+			assert.Equal(t, types.NamespacedName{Name: "node-agent"}, lookup.MeterDefName)
+
+			for namespace, expected := range test.expected {
+				_, ok, err := lookup.FindMatchingWorkloads(newPod(namespace))
+				require.NoError(t, err)
+				assert.Equal(t, expected, ok, namespace)
+			}
+		})
+	}
+}
+
+func TestMeterDefinitionHash(t *testing.T) {
+	meterdef := newValidMeterDefinition()
+	meterdef.Spec.Workloads = append(meterdef.Spec.Workloads, v1beta1.Workload{
+		Name:         "second",
+		WorkloadType: v1beta1.WorkloadTypeService,
+	})
+
+	hash, err := MeterDefinitionHash(meterdef, []string{"a", "b"})
+	require.NoError(t, err)
+
+	reordered := meterdef.DeepCopy()
+	reordered.Spec.Workloads[0], reordered.Spec.Workloads[1] = reordered.Spec.Workloads[1], reordered.Spec.Workloads[0]
+	reorderedHash, err := MeterDefinitionHash(reordered, []string{"b", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, hash, reorderedHash, "order of workloads and exclusions should not change the hash")
+
+	changed := meterdef.DeepCopy()
+	changed.Spec.Kind = "App2"
+	changedHash, err := MeterDefinitionHash(changed, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
@@ -0,0 +1,355 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"fmt"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespaceScope is what the namespaces of the workloads of a meter
+// definition are found from. Namespace vertexes with a selector depend on the
+// labels of the namespaces, operator group vertexes on the target namespaces
+// of the installing CSV.
+type namespaceScope struct {
+	vertex      v1beta1.WorkloadVertex
+	namespace   string
+	selector    labels.Selector
+	installedBy *types.NamespacedName
+}
+
+func newNamespaceScope(meterdef *v1beta1.MeterDefinition) (namespaceScope, error) {
+	scope := namespaceScope{
+		vertex:    meterdef.Spec.WorkloadVertexType,
+		namespace: meterdef.Namespace,
+	}
+
+	switch scope.vertex {
+	case v1beta1.WorkloadVertexNamespace:
+		if meterdef.Spec.VertexLabelSelector == nil {
+			return scope, nil
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(meterdef.Spec.VertexLabelSelector)
+		if err != nil {
+			return scope, err
+		}
+
+		scope.selector = selector
+	case v1beta1.WorkloadVertexOperatorGroup:
+		if meterdef.Spec.InstalledBy == nil {
+			return scope, nil
+		}
+
+		installedBy := meterdef.Spec.InstalledBy.ToTypes()
+		scope.installedBy = &installedBy
+	}
+
+	return scope, nil
+}
+
+// namespacesForNamespace returns the namespaces of the lookup after the
+// namespace was added, updated or deleted. False is returned if the
+// namespaces of the lookup did not change.
+func (s *MeterDefinitionLookupFilter) namespacesForNamespace(ns *corev1.Namespace, deleted bool) ([]string, bool) {
+	if s.scope.vertex != v1beta1.WorkloadVertexNamespace || s.scope.selector == nil {
+		return nil, false
+	}
+
+	matches := !deleted && s.scope.selector.Matches(labels.Set(ns.GetLabels()))
+	found := false
+	namespaces := []string{}
+
+	for _, name := range s.namespaceFilter.namespaces {
+		if name == ns.GetName() {
+			found = true
+			continue
+		}
+
+		namespaces = append(namespaces, name)
+	}
+
+	if matches == found {
+		return nil, false
+	}
+
+	if matches {
+		namespaces = append(namespaces, ns.GetName())
+	}
+
+	return namespaces, true
+}
+
+// namespacesForCSV returns the namespaces of the lookup after the CSV that
+// installed the meter definition was added, updated or deleted. Like
+// findNamespaces, the namespace of the meter definition is used when the CSV
+// is gone. False is returned if the lookup does not depend on the CSV.
+func (s *MeterDefinitionLookupFilter) namespacesForCSV(csv *olmv1alpha1.ClusterServiceVersion, deleted bool) ([]string, bool, error) {
+	if s.scope.vertex != v1beta1.WorkloadVertexOperatorGroup || s.scope.installedBy == nil ||
+		s.scope.installedBy.Name != csv.GetName() || s.scope.installedBy.Namespace != csv.GetNamespace() {
+		return nil, false, nil
+	}
+
+	if deleted {
+		return []string{s.scope.namespace}, true, nil
+	}
+
+	namespaces, err := csvTargetNamespaces(csv)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return namespaces, true, nil
+}
+
+// setNamespaces replaces the namespaces the workloads of the lookup are
+// matched in and returns the namespaces that entered or left them.
+func (s *MeterDefinitionLookupFilter) setNamespaces(namespaces []string) []string {
+	current := sets.NewString(s.namespaceFilter.namespaces...)
+	updated := sets.NewString(namespaces...)
+
+	s.namespaceFilter.namespaces = namespaces
+
+	return current.Difference(updated).Union(updated.Difference(current)).List()
+}
+
+// updateNamespaceScope recomputes the namespaces of the lookups that depend
+// on the namespace or CSV. The objects in the namespaces that entered or left
+// the scope of a lookup are matched again so they are added to or deleted
+// from the meter definition.
+func (s *MeterDefinitionStore) updateNamespaceScope(obj interface{}, deleted bool) error {
+	changed := map[MeterDefUID][]string{}
+
+	func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		for meterDefUID, lookup := range s.meterDefinitionFilters {
+			var namespaces []string
+			var ok bool
+			var err error
+
+			switch o := obj.(type) {
+			case *corev1.Namespace:
+				namespaces, ok = lookup.namespacesForNamespace(o, deleted)
+			case *olmv1alpha1.ClusterServiceVersion:
+				namespaces, ok, err = lookup.namespacesForCSV(o, deleted)
+			}
+
+			if err != nil {
+				s.log.Error(err, "failed to get namespaces of meterdef", "meterdef", lookup.MeterDefName)
+				continue
+			}
+
+			if !ok {
+				continue
+			}
+
+			if affected := lookup.setNamespaces(namespaces); len(affected) != 0 {
+				s.log.Info("meterdef namespaces changed", "meterdef", lookup.MeterDefName, "namespaces", namespaces, "affected", affected)
+				changed[meterDefUID] = affected
+				s.watchCustomResources(meterDefUID, lookup.customResources(), lookup.namespaces())
+			}
+		}
+	}()
+
+	for meterDefUID, affected := range changed {
+		objs, err := s.listNamespaceObjects(affected)
+		if err != nil {
+			s.log.Error(err, "failed to list objects of namespaces", "namespaces", affected)
+			return err
+		}
+
+		for _, obj := range objs {
+			if err := s.rematch(meterDefUID, obj); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// rematch matches the object against the lookup of the meter definition
+// again. An Add message is broadcast if the object started to match and a
+// Delete message if it stopped matching.
+func (s *MeterDefinitionStore) rematch(meterDefUID MeterDefUID, obj interface{}) error {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	key := NewObjectResourceKey(o, meterDefUID)
+
+	matchObj, err := s.matchObject(obj)
+	if err != nil {
+		return err
+	}
+
+	var lookup *MeterDefinitionLookupFilter
+	var workload *v1beta1.Workload
+	var matched, found bool
+
+	err = func() error {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		var ok bool
+		lookup, ok = s.meterDefinitionFilters[meterDefUID]
+		if !ok {
+			return nil
+		}
+
+		_, found = s.getObjectResource(key)
+		if matchObj != nil {
+			workload, matched, err = lookup.FindMatchingWorkloads(matchObj)
+		}
+		return err
+	}()
+
+	if err != nil {
+		s.log.Error(err, "")
+		return err
+	}
+
+	if lookup == nil || matched == found {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if matched {
+		resource, err := v1beta1.NewWorkloadResource(*workload, obj, s.scheme)
+		if err != nil {
+			s.log.Error(err, "")
+			return err
+		}
+
+		value := NewObjectResourceValue(lookup, resource, o, matched)
+		s.setObjectResource(key, obj, value)
+
+		s.log.Info("workload entered meterdef scope", "type", fmt.Sprintf("%T", obj), "mdef", value.MeterDef, "workloadName", value.WorkloadResource.Name)
+		s.broadcast(&ObjectResourceMessage{
+			Action:              AddMessageAction,
+			Object:              obj,
+			ObjectResourceValue: value,
+		})
+
+		return nil
+	}
+
+	resource, ok := s.getObjectResource(key)
+	if !ok {
+		return nil
+	}
+
+	s.log.Info("workload left meterdef scope", "type", fmt.Sprintf("%T", obj), "mdef", resource.value.MeterDef, "workloadName", resource.value.WorkloadResource.Name)
+	s.unmatchObjectResource(resource, obj)
+
+	return nil
+}
+
+// listNamespaceObjects lists the objects of the watched types in the
+// namespaces. NamespaceAll lists every watched namespace; namespaces that
+// are not watched are skipped since the store never sees their objects.
+func (s *MeterDefinitionStore) listNamespaceObjects(namespaces []string) ([]interface{}, error) {
+	watched := sets.NewString(s.namespaces...)
+	toList := sets.NewString()
+
+	for _, ns := range namespaces {
+		switch {
+		case ns == corev1.NamespaceAll:
+			toList.Insert(s.namespaces...)
+		case watched.Has(corev1.NamespaceAll) || watched.Has(ns):
+			toList.Insert(ns)
+		}
+	}
+
+	if toList.Has(corev1.NamespaceAll) {
+		toList = sets.NewString(corev1.NamespaceAll)
+	}
+
+	objs := []interface{}{}
+
+	list := func(lister cache.ListerWatcher) error {
+		listObj, err := lister.List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		items, err := meta.ExtractList(listObj)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if o, err := meta.Accessor(item); err == nil && !s.sharding.Keep(o.GetUID()) {
+				continue
+			}
+
+			objs = append(objs, item)
+		}
+
+		return nil
+	}
+
+	for _, ns := range toList.List() {
+		for _, lister := range s.createWatchers(ns) {
+			if err := list(lister); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, lister := range s.customResourceListers(ns) {
+			if err := list(lister); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objs, nil
+}
+
+// customResourceListers returns the listers of the namespaced custom
+// resources that are watched.
+func (s *MeterDefinitionStore) customResourceListers(ns string) []cache.ListerWatcher {
+	s.watchMutex.Lock()
+	defer s.watchMutex.Unlock()
+
+	listers := []cache.ListerWatcher{}
+	resources := map[schema.GroupVersionResource]bool{}
+
+	for _, w := range s.customResourceWatches {
+		if !w.namespaced || resources[w.resource] {
+			continue
+		}
+
+		resources[w.resource] = true
+		listers = append(listers, CreateCustomResourceListWatch(s.dynamicClient, w.resource, ns))
+	}
+
+	return listers
+}
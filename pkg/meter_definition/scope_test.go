@@ -0,0 +1,161 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestNamespaceScope(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+	require.NoError(t, olmv1alpha1.AddToScheme(testScheme))
+
+	csv := &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app-operator.v1",
+			Namespace:   "operators",
+			Annotations: map[string]string{"olm.targetNamespaces": "apps"},
+		},
+	}
+
+	k8sClient := fake.NewFakeClientWithScheme(testScheme,
+		newNamespace("apps", map[string]string{"metered": "true"}),
+		newNamespace("other", nil),
+		csv,
+	)
+	cc := reconcileutils.NewClientCommand(k8sClient, testScheme, logf.Log.WithName("scope_test"))
+
+	newPod := func(namespace string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: namespace,
+			UID:       types.UID("pod-" + namespace),
+			Labels:    map[string]string{"app": "app"},
+		}}
+	}
+
+	meterdef := newValidMeterDefinition()
+	meterdef.UID = "meterdef-uid"
+	meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+	meterdef.Spec.VertexLabelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"metered": "true"},
+	}
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].LabelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "app"},
+	}
+
+	t.Run("namespace vertex", func(t *testing.T) {
+		lookup, err := NewMeterDefinitionLookupFilter(cc, meterdef, nil)
+		require.NoError(t, err)
+
+		_, ok := lookup.namespacesForNamespace(newNamespace("apps", map[string]string{"metered": "true"}), false)
+		assert.False(t, ok, "labels of a namespace in scope did not change")
+
+		namespaces, ok := lookup.namespacesForNamespace(newNamespace("other", map[string]string{"metered": "true"}), false)
+		require.True(t, ok)
+		assert.ElementsMatch(t, []string{"apps", "other"}, namespaces)
+		assert.Equal(t, []string{"other"}, lookup.setNamespaces(namespaces))
+
+		_, matched, err := lookup.FindMatchingWorkloads(newPod("other"))
+		require.NoError(t, err)
+		assert.True(t, matched)
+
+		namespaces, ok = lookup.namespacesForNamespace(newNamespace("apps", nil), true)
+		require.True(t, ok)
+		assert.Equal(t, []string{"other"}, namespaces)
+		assert.Equal(t, []string{"apps"}, lookup.setNamespaces(namespaces))
+
+		_, ok, err = lookup.namespacesForCSV(csv, false)
+		require.NoError(t, err)
+		assert.False(t, ok, "namespace vertex does not depend on csvs")
+	})
+
+	t.Run("operator group vertex", func(t *testing.T) {
+		csvMeterdef := meterdef.DeepCopy()
+		csvMeterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexOperatorGroup
+		csvMeterdef.Spec.VertexLabelSelector = nil
+		csvMeterdef.Spec.InstalledBy = &common.NamespacedNameReference{Name: csv.Name, Namespace: csv.Namespace}
+
+		lookup, err := NewMeterDefinitionLookupFilter(cc, csvMeterdef, nil)
+		require.NoError(t, err)
+
+		_, ok := lookup.namespacesForNamespace(newNamespace("other", map[string]string{"metered": "true"}), false)
+		assert.False(t, ok, "operator group vertex does not depend on namespace labels")
+
+		otherCSV := csv.DeepCopy()
+		otherCSV.Name = "other-operator.v1"
+		_, ok, err = lookup.namespacesForCSV(otherCSV, false)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		updatedCSV := csv.DeepCopy()
+		updatedCSV.Annotations["olm.targetNamespaces"] = ""
+		namespaces, ok, err := lookup.namespacesForCSV(updatedCSV, false)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{corev1.NamespaceAll}, namespaces)
+
+		namespaces, ok, err = lookup.namespacesForCSV(updatedCSV, true)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{csvMeterdef.Namespace}, namespaces)
+	})
+
+	t.Run("rematch", func(t *testing.T) {
+		lookup, err := NewMeterDefinitionLookupFilter(cc, meterdef, nil)
+		require.NoError(t, err)
+
+		store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("scope_test"), cc, nil, nil, nil, nil, nil, nil, testScheme)
+		store.meterDefinitionFilters[MeterDefUID(meterdef.UID)] = lookup
+
+		l := newTestListener(store)
+
+		pod := newPod("other")
+		require.NoError(t, store.rematch(MeterDefUID(meterdef.UID), pod))
+		assert.Empty(t, drainMessages(l), "pod out of scope should not be broadcast")
+
+		lookup.setNamespaces([]string{"apps", "other"})
+		require.NoError(t, store.rematch(MeterDefUID(meterdef.UID), pod))
+		msgs := drainMessages(l)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, AddMessageAction, msgs[0].Action)
+		assert.Len(t, store.GetMeterDefObjects(meterdef.UID), 1)
+
+		lookup.setNamespaces([]string{"apps"})
+		require.NoError(t, store.rematch(MeterDefUID(meterdef.UID), pod))
+		msgs = drainMessages(l)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, ObjectResourceMessageAction(DeleteMessageAction), msgs[0].Action)
+		assert.Empty(t, store.GetMeterDefObjects(meterdef.UID))
+	})
+}
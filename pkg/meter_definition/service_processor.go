@@ -67,6 +67,26 @@ func (u *ServiceProcessor) Process(ctx context.Context, inObj *ObjectResourceMes
 		return nil
 	}
 
+	if inObj.Action == ResyncMessageAction {
+		for _, obj := range inObj.Objects {
+			if reflect.TypeOf(obj.Object) != serviceType {
+				continue
+			}
+
+			err := u.Process(ctx, &ObjectResourceMessage{
+				Action:              AddMessageAction,
+				Object:              obj.Object,
+				ObjectResourceValue: obj.ObjectResourceValue,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	if inObj.Action == DeleteMessageAction {
 		return nil
 	}
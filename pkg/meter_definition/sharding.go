@@ -0,0 +1,46 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"hash/fnv"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Sharding splits the objects between the replicas of the metric state by
+// the hash of their UID, so an object is always in the same shard for the
+// same number of shards.
+type Sharding struct {
+	Shard       int32
+	TotalShards int
+}
+
+// IsSharded returns true if there is more than one shard.
+func (s Sharding) IsSharded() bool {
+	return s.TotalShards > 1
+}
+
+// Keep returns true if the object with the UID is in the shard.
+func (s Sharding) Keep(uid types.UID) bool {
+	if !s.IsSharded() {
+		return true
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(uid))
+
+	return h.Sum64()%uint64(s.TotalShards) == uint64(s.Shard)
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestShardingKeep(t *testing.T) {
+	unsharded := Sharding{}
+	assert.False(t, unsharded.IsSharded())
+	assert.True(t, unsharded.Keep(types.UID("a")))
+
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		uid := types.UID(fmt.Sprintf("uid-%d", i))
+		kept := 0
+		for shard := range counts {
+			if (Sharding{Shard: int32(shard), TotalShards: len(counts)}).Keep(uid) {
+				counts[shard]++
+				kept++
+			}
+		}
+		assert.Equal(t, 1, kept, "uid %s should belong to exactly one shard", uid)
+	}
+
+	for shard, count := range counts {
+		assert.NotZero(t, count, "shard %d has no objects", shard)
+	}
+}
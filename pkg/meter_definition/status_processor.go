@@ -21,8 +21,10 @@ import (
 	"sync"
 
 	"github.com/go-logr/logr"
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -62,12 +64,17 @@ func (u *StatusProcessor) Start(ctx context.Context) error {
 // resoruces and checks it against the status.
 func (u *StatusProcessor) Process(ctx context.Context, inObj *ObjectResourceMessage) error {
 	log := u.log.WithValues("process", "statusProcessor")
-	mdef := &marketplacev1alpha1.MeterDefinition{}
 
 	if inObj == nil {
 		return nil
 	}
 
+	if inObj.Action == ResyncMessageAction {
+		return u.resync(ctx, inObj.Objects)
+	}
+
+	mdef, mdefStatus := newMeterDefinitionObject(inObj.MeterDef)
+
 	if inObj != nil {
 	
 	}
@@ -92,11 +99,11 @@ func (u *StatusProcessor) Process(ctx context.Context, inObj *ObjectResourceMess
 			OnContinue(Call(func() (ClientAction, error) {
 				log.Info("found objs", "mdef", inObj.MeterDef)
 
-				resources := []marketplacev1alpha1.WorkloadResource{}
+				resources := []marketplacev1beta1.WorkloadResource{}
 
-				set := map[types.UID]marketplacev1alpha1.WorkloadResource{}
+				set := map[types.UID]marketplacev1beta1.WorkloadResource{}
 
-				for _, obj := range mdef.Status.WorkloadResources {
+				for _, obj := range mdefStatus.WorkloadResources {
 					set[obj.UID] = obj
 				}
 
@@ -111,10 +118,10 @@ func (u *StatusProcessor) Process(ctx context.Context, inObj *ObjectResourceMess
 					resources = append(resources, obj)
 				}
 
-				sort.Sort(marketplacev1alpha1.ByAlphabetical(resources))
-				mdef.Status.WorkloadResources = resources
+				sort.Sort(marketplacev1beta1.ByAlphabetical(resources))
+				mdefStatus.WorkloadResources = resources
 
-				log.Info("updating meter def", "mdef", inObj.MeterDef, "uid", mdef.UID, "len", len(mdef.Status.WorkloadResources))
+				log.Info("updating meter def", "mdef", inObj.MeterDef, "len", len(mdefStatus.WorkloadResources))
 				return UpdateAction(mdef, UpdateStatusOnly(true)), nil
 			})),
 		),
@@ -131,3 +138,103 @@ func (u *StatusProcessor) Process(ctx context.Context, inObj *ObjectResourceMess
 
 	return nil
 }
+
+// resync sets the workload resources of every meter definition to the ones
+// in the store snapshot, so statuses converge after missed messages. The
+// resources of the other shards are kept as they are.
+func (u *StatusProcessor) resync(ctx context.Context, objects []ObjectResource) error {
+	log := u.log.WithValues("process", "statusProcessor", "action", ResyncMessageAction)
+
+	set := map[types.NamespacedName]map[types.UID]marketplacev1beta1.WorkloadResource{}
+
+	for _, obj := range objects {
+		if obj.ObjectResourceValue == nil || obj.WorkloadResource == nil {
+			continue
+		}
+
+		if _, ok := set[obj.MeterDef]; !ok {
+			set[obj.MeterDef] = map[types.UID]marketplacev1beta1.WorkloadResource{}
+		}
+
+		set[obj.MeterDef][obj.UID] = *obj.WorkloadResource
+	}
+
+	meterdefs := &marketplacev1beta1.MeterDefinitionList{}
+	clusterMeterdefs := &marketplacev1beta1.ClusterMeterDefinitionList{}
+
+	result, _ := u.cc.Do(ctx,
+		ListAction(meterdefs),
+		ListAction(clusterMeterdefs),
+	)
+
+	if result.Is(Error) {
+		log.Error(result, "failed to list meter definitions")
+		return result
+	}
+
+	actions := []ClientAction{}
+	sharding := u.meterDefStore.Sharding()
+
+	updateStatus := func(name types.NamespacedName, obj runtime.Object, status *marketplacev1beta1.MeterDefinitionStatus) {
+		resources := []marketplacev1beta1.WorkloadResource{}
+
+		for _, resource := range status.WorkloadResources {
+			if !sharding.Keep(resource.UID) {
+				resources = append(resources, resource)
+			}
+		}
+
+		for _, resource := range set[name] {
+			resources = append(resources, resource)
+		}
+
+		sort.Sort(marketplacev1beta1.ByAlphabetical(resources))
+
+		if equality.Semantic.DeepEqual(resources, status.WorkloadResources) {
+			return
+		}
+
+		log.Info("updating meter def", "mdef", name, "len", len(resources))
+		status.WorkloadResources = resources
+		actions = append(actions, HandleResult(
+			UpdateAction(obj, UpdateStatusOnly(true)),
+			OnNotFound(ContinueResponse()),
+		))
+	}
+
+	for i := range meterdefs.Items {
+		meterdef := &meterdefs.Items[i]
+		updateStatus(types.NamespacedName{Name: meterdef.Name, Namespace: meterdef.Namespace}, meterdef, &meterdef.Status)
+	}
+
+	for i := range clusterMeterdefs.Items {
+		clusterMeterdef := &clusterMeterdefs.Items[i]
+		updateStatus(types.NamespacedName{Name: clusterMeterdef.Name}, clusterMeterdef, &clusterMeterdef.Status)
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	result, _ = u.cc.Do(ctx, actions...)
+
+	if result.Is(Error) {
+		log.Error(result, "failed to resync meter definitions")
+		return result
+	}
+
+	return nil
+}
+
+// newMeterDefinitionObject returns an empty object to get the meter
+// definition with the name into and a pointer to its status. Names without
+// a namespace are ClusterMeterDefinitions.
+func newMeterDefinitionObject(name types.NamespacedName) (runtime.Object, *marketplacev1beta1.MeterDefinitionStatus) {
+	if name.Namespace == "" {
+		clusterMeterdef := &marketplacev1beta1.ClusterMeterDefinition{}
+		return clusterMeterdef, &clusterMeterdef.Status
+	}
+
+	meterdef := &marketplacev1beta1.MeterDefinition{}
+	return meterdef, &meterdef.Status
+}
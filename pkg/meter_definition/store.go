@@ -20,25 +20,31 @@ import (
 	"sync"
 	"time"
 
+	"emperror.dev/errors"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1client "github.com/coreos/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
 	"github.com/go-logr/logr"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
-	marketplacev1alpha1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
+	marketplacev1beta1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
 type ObjectUID types.UID
 type MeterDefUID types.UID
-type ResourceSet map[MeterDefUID]*v1alpha1.WorkloadResource
+type ResourceSet map[MeterDefUID]*v1beta1.WorkloadResource
 type ObjectResourceMessageAction string
 
 const (
@@ -50,6 +56,9 @@ type ObjectResourceMessage struct {
 	Action               ObjectResourceMessageAction `json:"action"`
 	Object               interface{}                 `json:"object"`
 	*ObjectResourceValue `json:"resourceValue,omitempty"`
+
+	// Objects are the objects of the store for a Resync message
+	Objects []ObjectResource `json:"-"`
 }
 
 type ObjectResourceKey struct {
@@ -69,12 +78,12 @@ type ObjectResourceValue struct {
 	MeterDefHash string
 	Generation   int64
 	Matched      bool
-	*v1alpha1.WorkloadResource
+	*v1beta1.WorkloadResource
 }
 
 func NewObjectResourceValue(
 	lookup *MeterDefinitionLookupFilter,
-	resource *v1alpha1.WorkloadResource,
+	resource *v1beta1.WorkloadResource,
 	obj metav1.Object,
 	matched bool,
 ) *ObjectResourceValue {
@@ -93,7 +102,11 @@ func NewObjectResourceValue(
 // find the child assets of a meter definition rules.
 type MeterDefinitionStore struct {
 	meterDefinitionFilters map[MeterDefUID]*MeterDefinitionLookupFilter
-	objectResourceSet      map[ObjectResourceKey]*ObjectResourceValue
+
+	// objectResources are the objects that matched a workload of a meter
+	// definition, indexed by object, meter definition, namespace and
+	// workload name
+	objectResources cache.Indexer
 
 	mutex sync.RWMutex
 
@@ -105,13 +118,34 @@ type MeterDefinitionStore struct {
 
 	namespaces []string
 
+	// synced are the stores of the reflectors by name, workloadsSynced is
+	// set once the workloads are listed and sent to the listeners
+	synced          map[string]*syncedStore
+	syncMutex       sync.RWMutex
+	workloadsSynced bool
+
+	// sharding selects the objects of the workloads kept by this store,
+	// meter definitions, namespaces and CSVs are kept by every shard
+	sharding Sharding
+
 	kubeClient        clientset.Interface
 	findOwner         *rhmclient.FindOwnerHelper
 	monitoringClient  *monitoringv1client.MonitoringV1Client
-	marketplaceClient *marketplacev1alpha1client.MarketplaceV1alpha1Client
-
-	listenerMutex sync.Mutex
-	listeners     []chan *ObjectResourceMessage
+	marketplaceClient *marketplacev1beta1client.MarketplaceV1beta1Client
+	dynamicClient     dynamic.Interface
+	restMapper        meta.RESTMapper
+
+	// customResourceWatches are the custom resources watched for
+	// CustomResource workloads by namespace, customResourceRetries the
+	// meter definitions waiting to look up a custom resource again
+	customResourceWatches map[customResourceWatchKey]*customResourceWatch
+	customResourceRetries map[MeterDefUID]bool
+	watchMutex            sync.Mutex
+
+	listeners         []*listener
+	listenerQueueSize int
+	listenerMetrics   *listenerMetrics
+	resyncInterval    time.Duration
 }
 
 func NewMeterDefinitionStore(
@@ -121,7 +155,9 @@ func NewMeterDefinitionStore(
 	kubeClient clientset.Interface,
 	findOwner *rhmclient.FindOwnerHelper,
 	monitoringClient *monitoringv1client.MonitoringV1Client,
-	marketplaceclient *marketplacev1alpha1client.MarketplaceV1alpha1Client,
+	marketplaceclient *marketplacev1beta1client.MarketplaceV1beta1Client,
+	dynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
 	scheme *runtime.Scheme,
 ) *MeterDefinitionStore {
 	return &MeterDefinitionStore{
@@ -131,75 +167,71 @@ func NewMeterDefinitionStore(
 		kubeClient:             kubeClient,
 		monitoringClient:       monitoringClient,
 		marketplaceClient:      marketplaceclient,
+		dynamicClient:          dynamicClient,
+		restMapper:             restMapper,
 		findOwner:              findOwner,
 		scheme:                 scheme,
-		listeners:              []chan *ObjectResourceMessage{},
+		listeners:              []*listener{},
+		listenerQueueSize:      defaultListenerQueueSize,
+		listenerMetrics:        newListenerMetrics(),
+		resyncInterval:         defaultListenerResyncInterval,
 		meterDefinitionFilters: make(map[MeterDefUID]*MeterDefinitionLookupFilter),
-		objectResourceSet:      make(map[ObjectResourceKey]*ObjectResourceValue),
+		objectResources:        newObjectResourceIndexer(),
+		customResourceWatches:  make(map[customResourceWatchKey]*customResourceWatch),
+		customResourceRetries:  make(map[MeterDefUID]bool),
 	}
 }
 
+// RegisterListener sends the messages of the store to the channel. Messages
+// are queued for the listener so the store never waits for it.
 func (s *MeterDefinitionStore) RegisterListener(name string, ch chan *ObjectResourceMessage) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	s.log.Info("registering listener", "name", name)
-	s.listeners = append(s.listeners, ch)
+
+	l := newListener(name, ch, s.listenerQueueSize, s.listenerMetrics)
+	s.listeners = append(s.listeners, l)
+	go l.run(s.ctx)
+
+	if s.workloadsSynced {
+		s.resyncListener(l, s.snapshot())
+	}
 }
 
-func (s *MeterDefinitionStore) addMeterDefinition(meterdef *v1alpha1.MeterDefinition, lookup *MeterDefinitionLookupFilter) {
+func (s *MeterDefinitionStore) addMeterDefinition(meterdef *v1beta1.MeterDefinition, lookup *MeterDefinitionLookupFilter) {
 	s.meterDefinitionFilters[MeterDefUID(meterdef.UID)] = lookup
 }
 
-func (s *MeterDefinitionStore) removeMeterDefinition(meterdef *v1alpha1.MeterDefinition) {
-	delete(s.meterDefinitionFilters, MeterDefUID(meterdef.UID))
-	for key := range s.objectResourceSet {
-		if key.MeterDefUID == MeterDefUID(meterdef.GetUID()) {
-			delete(s.objectResourceSet, key)
-		}
+func (s *MeterDefinitionStore) removeMeterDefinition(meterdef metav1.Object) {
+	delete(s.meterDefinitionFilters, MeterDefUID(meterdef.GetUID()))
+	s.watchCustomResources(MeterDefUID(meterdef.GetUID()), nil, nil)
+	for _, resource := range s.queryObjectResources(ObjectResourceQuery{MeterDefUID: meterdef.GetUID()}) {
+		s.deleteObjectResource(resource)
 	}
 }
 
 func (s *MeterDefinitionStore) broadcast(msg *ObjectResourceMessage) {
-	for _, ch := range s.listeners {
-		select {
-		case ch <- msg:
-			s.log.V(3).Info("sent message",  "msg", msg)
-		default:
-			s.log.V(3).Info("no message sent", "msg", msg)
-		}
+	for _, l := range s.listeners {
+		s.log.V(3).Info("queued message", "listener", l.name, "msg", msg)
+		l.enqueue(msg)
 	}
 }
 
+// GetMeterDefinitionRefs returns the values of the meter definitions the
+// object matched.
 func (s *MeterDefinitionStore) GetMeterDefinitionRefs(uid types.UID) []*ObjectResourceValue {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	vals := []*ObjectResourceValue{}
-	for key, val := range s.objectResourceSet {
-		if key.ObjectUID == ObjectUID(uid) && val.Matched {
-			vals = append(vals, val)
-		}
-	}
-	return vals
+	return s.Query(ObjectResourceQuery{ObjectUID: uid})
 }
 
+// GetMeterDefObjects returns the values of the objects that matched the meter
+// definition.
 func (s *MeterDefinitionStore) GetMeterDefObjects(meterDefUID types.UID) []*ObjectResourceValue {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	vals := []*ObjectResourceValue{}
-	for key, val := range s.objectResourceSet {
-		if key.MeterDefUID == MeterDefUID(meterDefUID) && val.Matched {
-			vals = append(vals, val)
-		}
-	}
-
-	return vals
+	return s.Query(ObjectResourceQuery{MeterDefUID: meterDefUID})
 }
 
 type result struct {
 	meterDefUID MeterDefUID
-	workload    *v1alpha1.Workload
+	workload    *v1beta1.Workload
 	ok          bool
 	lookup      *MeterDefinitionLookupFilter
 	key         ObjectResourceKey
@@ -211,30 +243,36 @@ type result struct {
 // adding the generated metrics to the metrics map that underlies the MetricStore.
 func (s *MeterDefinitionStore) Add(obj interface{}) error {
 
-	if meterdef, ok := obj.(*v1alpha1.MeterDefinition); ok {
-		err := func() error {
-			s.mutex.Lock()
-			defer s.mutex.Unlock()
-			lookup, err := NewMeterDefinitionLookupFilter(s.cc, meterdef, s.findOwner)
+	if meterdef, ok := obj.(*v1beta1.MeterDefinition); ok {
+		return s.addLookup(meterdef, func() (*MeterDefinitionLookupFilter, error) {
+			return NewMeterDefinitionLookupFilter(s.cc, meterdef, s.findOwner)
+		})
+	}
 
-			if err != nil {
-				s.log.Error(err, "error building lookup")
-				return err
-			}
+	if clusterMeterdef, ok := obj.(*v1beta1.ClusterMeterDefinition); ok {
+		return s.addLookup(clusterMeterdef.ToMeterDefinition(), func() (*MeterDefinitionLookupFilter, error) {
+			return NewClusterMeterDefinitionLookupFilter(s.cc, clusterMeterdef, s.findOwner)
+		})
+	}
 
-			s.log.Info("found lookup", "lookup", lookup)
-			s.meterDefinitionFilters[MeterDefUID(meterdef.UID)] = lookup
-			return nil
-		}()
+	switch obj.(type) {
+	case *corev1.Namespace, *olmv1alpha1.ClusterServiceVersion:
+		return s.updateNamespaceScope(obj, false)
+	}
 
-		if err != nil {
-			return err
-		}
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
 
+	if !s.sharding.Keep(o.GetUID()) {
+		if service, ok := obj.(*corev1.Service); ok {
+			return s.rematchEndpoints(service, false)
+		}
 		return nil
 	}
 
-	o, err := meta.Accessor(obj)
+	matchObj, err := s.matchObject(obj)
 	if err != nil {
 		return err
 	}
@@ -247,7 +285,14 @@ func (s *MeterDefinitionStore) Add(obj interface{}) error {
 		defer s.mutex.RUnlock()
 		for meterDefUID, lookup := range s.meterDefinitionFilters {
 			key := NewObjectResourceKey(o, meterDefUID)
-			workload, ok, err := lookup.FindMatchingWorkloads(obj)
+
+			var workload *v1beta1.Workload
+			var ok bool
+			var err error
+
+			if matchObj != nil {
+				workload, ok, err = lookup.FindMatchingWorkloads(matchObj)
+			}
 
 			if err != nil {
 				s.log.Error(err, "")
@@ -270,23 +315,32 @@ func (s *MeterDefinitionStore) Add(obj interface{}) error {
 	}
 
 	for _, result := range results {
-		if !result.ok {
-			continue
-		}
-
 		err := func() error {
 			s.mutex.Lock()
 			defer s.mutex.Unlock()
 
+			existing, found := s.getObjectResource(result.key)
+
+			if !result.ok {
+				if found {
+					s.unmatchObjectResource(existing, obj)
+				}
+				return nil
+			}
+
+			if found && existing.isUnchanged(obj, result.lookup) {
+				return nil
+			}
+
 			log.Info("workload found", "obj", obj, "meterDefUID", string(result.meterDefUID))
-			resource, err := v1alpha1.NewWorkloadResource(*result.workload, obj, s.scheme)
+			resource, err := v1beta1.NewWorkloadResource(*result.workload, obj, s.scheme)
 			if err != nil {
 				s.log.Error(err, "")
 				return err
 			}
 
 			value := NewObjectResourceValue(result.lookup, resource, o, result.ok)
-			s.objectResourceSet[result.key] = value
+			s.setObjectResource(result.key, obj, value)
 
 			msg := &ObjectResourceMessage{
 				Action:              AddMessageAction,
@@ -305,6 +359,104 @@ func (s *MeterDefinitionStore) Add(obj interface{}) error {
 		}
 	}
 
+	if service, ok := obj.(*corev1.Service); ok {
+		return s.rematchEndpoints(service, false)
+	}
+
+	return nil
+}
+
+// rematchEndpoints matches the endpoints of the service again since they are
+// matched by the service. The endpoints are removed from every meter
+// definition when the service is deleted.
+func (s *MeterDefinitionStore) rematchEndpoints(service *corev1.Service, deleted bool) error {
+	endpoints := &corev1.Endpoints{}
+	result, _ := s.cc.Do(s.ctx, GetAction(types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, endpoints))
+
+	if result.Is(NotFound) {
+		return nil
+	}
+
+	if !result.Is(Continue) {
+		return errors.Wrap(result, "failed to get endpoints of service")
+	}
+
+	if !s.sharding.Keep(endpoints.UID) {
+		return nil
+	}
+
+	if deleted {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		for _, resource := range s.queryObjectResources(ObjectResourceQuery{ObjectUID: endpoints.UID}) {
+			s.unmatchObjectResource(resource, endpoints)
+		}
+
+		return nil
+	}
+
+	meterDefUIDs := []MeterDefUID{}
+	func() {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		for meterDefUID := range s.meterDefinitionFilters {
+			meterDefUIDs = append(meterDefUIDs, meterDefUID)
+		}
+	}()
+
+	for _, meterDefUID := range meterDefUIDs {
+		if err := s.rematch(meterDefUID, endpoints); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchObject returns the object meter definitions are matched against.
+// Endpoints are matched by the service of the same name so the workloads of
+// a service select its endpoints, nil is returned without a service.
+func (s *MeterDefinitionStore) matchObject(obj interface{}) (interface{}, error) {
+	endpoints, ok := obj.(*corev1.Endpoints)
+	if !ok {
+		return obj, nil
+	}
+
+	service := &corev1.Service{}
+	result, _ := s.cc.Do(s.ctx, GetAction(types.NamespacedName{Namespace: endpoints.Namespace, Name: endpoints.Name}, service))
+
+	if result.Is(NotFound) {
+		return nil, nil
+	}
+
+	if !result.Is(Continue) {
+		return nil, errors.Wrap(result, "failed to get service of endpoints")
+	}
+
+	return service, nil
+}
+
+// addLookup saves the lookup of a meter definition and watches the custom
+// resources of its workloads. The lookup is saved first so the custom
+// resources listed by the watches are matched against it.
+func (s *MeterDefinitionStore) addLookup(
+	meterdef *v1beta1.MeterDefinition,
+	newLookup func() (*MeterDefinitionLookupFilter, error),
+) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lookup, err := newLookup()
+
+	if err != nil {
+		s.log.Error(err, "error building lookup")
+		return err
+	}
+
+	s.log.Info("found lookup", "lookup", lookup)
+	s.meterDefinitionFilters[MeterDefUID(meterdef.UID)] = lookup
+	s.watchCustomResources(MeterDefUID(meterdef.UID), lookup.customResources(), lookup.namespaces())
 	return nil
 }
 
@@ -316,79 +468,104 @@ func (s *MeterDefinitionStore) Update(obj interface{}) error {
 
 // Delete deletes an existing entry in the OwnerCache.
 func (s *MeterDefinitionStore) Delete(obj interface{}) error {
+	switch obj.(type) {
+	case *corev1.Namespace, *olmv1alpha1.ClusterServiceVersion:
+		return s.updateNamespaceScope(obj, true)
+	}
+
+	if service, ok := obj.(*corev1.Service); ok {
+		if err := s.rematchEndpoints(service, true); err != nil {
+			return err
+		}
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if meterdef, ok := obj.(*v1alpha1.MeterDefinition); ok {
+	if meterdef, ok := obj.(*v1beta1.MeterDefinition); ok {
 		s.removeMeterDefinition(meterdef)
 		return nil
 	}
 
+	if clusterMeterdef, ok := obj.(*v1beta1.ClusterMeterDefinition); ok {
+		s.removeMeterDefinition(clusterMeterdef)
+		return nil
+	}
+
 	o, err := meta.Accessor(obj)
 	if err != nil {
 		return err
 	}
 
-	for key, val := range s.objectResourceSet {
-		if key.ObjectUID == ObjectUID(o.GetUID()) {
-			s.broadcast(&ObjectResourceMessage{
-				Action:              DeleteMessageAction,
-				Object:              o,
-				ObjectResourceValue: val,
-			})
+	for _, resource := range s.queryObjectResources(ObjectResourceQuery{ObjectUID: o.GetUID()}) {
+		s.broadcast(&ObjectResourceMessage{
+			Action:              DeleteMessageAction,
+			Object:              o,
+			ObjectResourceValue: resource.value,
+		})
 
-			delete(s.objectResourceSet, key)
-		}
+		s.deleteObjectResource(resource)
 	}
 
 	return nil
 }
 
-// List implements the List method of the store interface.
+// List implements the List method of the store interface. It returns the
+// objects that matched a meter definition.
 func (s *MeterDefinitionStore) List() []interface{} {
-	return nil
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	objs := []interface{}{}
+	for _, obj := range s.objectsByUID() {
+		objs = append(objs, obj)
+	}
+
+	return objs
 }
 
-// ListKeys implements the ListKeys method of the store interface.
+// ListKeys implements the ListKeys method of the store interface. Objects
+// are keyed by UID since the store holds objects of many types.
 func (s *MeterDefinitionStore) ListKeys() []string {
-	return nil
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.objectResources.ListIndexFuncValues(ObjectUIDIndex)
 }
 
 // Get implements the Get method of the store interface.
 func (s *MeterDefinitionStore) Get(obj interface{}) (item interface{}, exists bool, err error) {
-	return nil, false, nil
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return s.GetByKey(string(o.GetUID()))
 }
 
-// GetByKey implements the GetByKey method of the store interface.
+// GetByKey implements the GetByKey method of the store interface. The key is
+// the UID of the object.
 func (s *MeterDefinitionStore) GetByKey(key string) (item interface{}, exists bool, err error) {
-	return nil, false, nil
-}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
-// Replace will delete the contents of the store, using instead the
-// given list.
-func (s *MeterDefinitionStore) Replace(list []interface{}, _ string) error {
-	s.mutex.Lock()
-	for _, item := range list {
-		o, _ := meta.Accessor(item)
-
-		for key, val := range s.objectResourceSet {
-			if key.ObjectUID == ObjectUID(o.GetUID()) {
-				s.broadcast(&ObjectResourceMessage{
-					Action:              DeleteMessageAction,
-					Object:              item,
-					ObjectResourceValue: val,
-				})
-
-				delete(s.objectResourceSet, key)
-			}
-		}
+	items, err := s.objectResources.ByIndex(ObjectUIDIndex, key)
+	if err != nil || len(items) == 0 {
+		return nil, false, err
 	}
-	s.mutex.Unlock()
 
+	return items[0].(*objectResource).object, true, nil
+}
+
+// Replace matches the listed objects again. Objects that are unchanged since
+// they were matched are not broadcast again. An object that fails to match is
+// logged and skipped, the reflector would otherwise relist every object and
+// never sync.
+func (s *MeterDefinitionStore) Replace(list []interface{}, _ string) error {
 	for _, o := range list {
 		err := s.Add(o)
 		if err != nil {
-			return err
+			s.log.Error(err, "failed to add listed object", "type", fmt.Sprintf("%T", o))
 		}
 	}
 
@@ -401,20 +578,21 @@ func (s *MeterDefinitionStore) Resync() error {
 }
 
 func (s *MeterDefinitionStore) Start() {
+	meterDefsSynced := []cache.InformerSynced{}
 	for _, ns := range s.namespaces {
-		lister := CreateMeterDefinitionWatch(s.marketplaceClient, ns)
-		reflector := cache.NewReflector(lister, &v1alpha1.MeterDefinition{}, s, 0)
-		go reflector.Run(s.ctx.Done())
+		meterDefsSynced = append(meterDefsSynced, s.watch(
+			watchName(&v1beta1.MeterDefinition{}, ns),
+			CreateMeterDefinitionWatch(s.marketplaceClient, ns),
+			&v1beta1.MeterDefinition{}))
 	}
 
-	time.Sleep(5 * time.Second)
+	meterDefsSynced = append(meterDefsSynced, s.watch(
+		watchName(&v1beta1.ClusterMeterDefinition{}, ""),
+		CreateClusterMeterDefinitionWatch(s.marketplaceClient),
+		&v1beta1.ClusterMeterDefinition{}))
+
+	go s.startWorkloads(meterDefsSynced)
 
-	for _, ns := range s.namespaces {
-		for expectedType, lister := range s.createWatchers(ns) {
-			reflector := cache.NewReflector(lister, expectedType, s, 0)
-			go reflector.Run(s.ctx.Done())
-		}
-	}
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
 		for {
@@ -434,6 +612,64 @@ func (s *MeterDefinitionStore) Start() {
 	}()
 }
 
+// startWorkloads watches the workloads once the meter definitions and the
+// namespaces they are scoped to are listed, so workloads are never matched
+// against a partial set of meter definitions.
+func (s *MeterDefinitionStore) startWorkloads(meterDefsSynced []cache.InformerSynced) {
+	if !s.waitForSync("meterdefinitions", meterDefsSynced...) {
+		return
+	}
+
+	// namespaces and csvs change the namespaces meterdefs are scoped to
+	scopeSynced := []cache.InformerSynced{
+		s.watch(
+			watchName(&corev1.Namespace{}, ""),
+			CreateNamespaceListWatch(s.kubeClient),
+			&corev1.Namespace{}),
+	}
+
+	for _, ns := range s.namespaces {
+		scopeSynced = append(scopeSynced, s.watch(
+			watchName(&olmv1alpha1.ClusterServiceVersion{}, ns),
+			CreateCSVListWatch(s.dynamicClient, ns),
+			&olmv1alpha1.ClusterServiceVersion{}))
+	}
+
+	if !s.waitForSync("scope", scopeSynced...) {
+		return
+	}
+
+	workloadsSynced := []cache.InformerSynced{}
+	for _, ns := range s.namespaces {
+		for expectedType, lister := range s.createWatchers(ns) {
+			workloadsSynced = append(workloadsSynced, s.watch(watchName(expectedType, ns), lister, expectedType))
+		}
+	}
+
+	if !s.waitForSync("workloads", workloadsSynced...) {
+		return
+	}
+
+	s.mutex.Lock()
+	s.workloadsSynced = true
+	s.mutex.Unlock()
+
+	// listeners registered from now on get the objects when they register
+	s.resync()
+	s.runResync()
+}
+
+// SetSharding sets the shard of the objects kept by the store. It has to be
+// set before the store is started.
+func (s *MeterDefinitionStore) SetSharding(sharding Sharding) {
+	s.sharding = sharding
+}
+
+// Sharding returns the shard of the objects kept by the store.
+func (s *MeterDefinitionStore) Sharding() Sharding {
+	return s.sharding
+}
+
 func (s *MeterDefinitionStore) SetNamespaces(ns []string) {
 	s.namespaces = ns
 }
@@ -443,6 +679,12 @@ func (s *MeterDefinitionStore) createWatchers(ns string) map[runtime.Object]cach
 		&corev1.PersistentVolumeClaim{}: CreatePVCListWatch(s.kubeClient, ns),
 		&corev1.Pod{}:                   CreatePodListWatch(s.kubeClient, ns),
 		&corev1.Service{}:               CreateServiceListWatch(s.kubeClient, ns),
+		&corev1.Endpoints{}:             CreateEndpointsListWatch(s.kubeClient, ns),
 		&monitoringv1.ServiceMonitor{}:  CreateServiceMonitorListWatch(s.monitoringClient, ns),
+		&appsv1.Deployment{}:            CreateDeploymentListWatch(s.kubeClient, ns),
+		&appsv1.StatefulSet{}:           CreateStatefulSetListWatch(s.kubeClient, ns),
+		&appsv1.DaemonSet{}:             CreateDaemonSetListWatch(s.kubeClient, ns),
+		&batchv1.Job{}:                  CreateJobListWatch(s.kubeClient, ns),
+		&batchv1beta1.CronJob{}:         CreateCronJobListWatch(s.kubeClient, ns),
 	}
 }
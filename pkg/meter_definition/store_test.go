@@ -0,0 +1,192 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestMeterDefinitionStoreIndex(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(testScheme), testScheme, logf.Log.WithName("store_test"))
+	store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("store_test"), cc, nil, nil, nil, nil, nil, nil, testScheme)
+
+	l := newTestListener(store)
+
+	meterdef := newValidMeterDefinition()
+	meterdef.UID = "meterdef-uid"
+	meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].LabelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "app"},
+	}
+	require.NoError(t, store.Add(meterdef))
+
+	newPod := func(name, namespace, resourceVersion string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			UID:             types.UID(namespace + "-" + name),
+			ResourceVersion: resourceVersion,
+			Labels:          labels,
+		}}
+	}
+
+	metered := map[string]string{"app": "app"}
+	podA := newPod("a", "apps", "1", metered)
+	podB := newPod("b", "other", "1", metered)
+	podC := newPod("c", "apps", "1", nil)
+
+	require.NoError(t, store.Replace([]interface{}{podA, podB, podC}, ""))
+	assert.Len(t, drainMessages(l), 2)
+
+	assert.ElementsMatch(t, []string{"apps-a", "other-b"}, store.ListKeys())
+	assert.Len(t, store.List(), 2)
+
+	item, exists, err := store.Get(podA)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, podA, item)
+
+	_, exists, err = store.GetByKey(string(podC.UID))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	vals := store.Query(ObjectResourceQuery{Namespace: "apps"})
+	require.Len(t, vals, 1)
+	assert.Equal(t, "a", vals[0].WorkloadResource.Name)
+
+	vals = store.Query(ObjectResourceQuery{WorkloadName: "app-pods", Namespace: "other"})
+	require.Len(t, vals, 1)
+	assert.Equal(t, "b", vals[0].WorkloadResource.Name)
+
+	assert.Len(t, store.GetMeterDefObjects(meterdef.UID), 2)
+	assert.Len(t, store.GetMeterDefinitionRefs(podA.UID), 1)
+
+	// listing the same objects again does not broadcast them again
+	require.NoError(t, store.Replace([]interface{}{podA, podB, podC}, ""))
+	assert.Empty(t, drainMessages(l))
+
+	// an object that stops matching is deleted from the meter definition
+	require.NoError(t, store.Update(newPod("a", "apps", "2", nil)))
+	msgs := drainMessages(l)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, ObjectResourceMessageAction(DeleteMessageAction), msgs[0].Action)
+	assert.Empty(t, store.GetMeterDefinitionRefs(podA.UID))
+
+	require.NoError(t, store.Delete(podB))
+	assert.Len(t, drainMessages(l), 1)
+	assert.Empty(t, store.List())
+
+	require.NoError(t, store.Add(podB))
+	require.NoError(t, store.Delete(meterdef))
+	assert.Empty(t, store.GetMeterDefObjects(meterdef.UID))
+}
+
+func TestMeterDefinitionStoreEndpoints(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	t.Run("Service relabeled after Endpoints were added", func(t *testing.T) {
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:            "app",
+			Namespace:       "apps",
+			UID:             "service-uid",
+			ResourceVersion: "1",
+		}}
+		endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{
+			Name:            "app",
+			Namespace:       "apps",
+			UID:             "endpoints-uid",
+			ResourceVersion: "1",
+		}}
+
+		client := fake.NewFakeClientWithScheme(testScheme, service.DeepCopy(), endpoints.DeepCopy())
+		cc := reconcileutils.NewClientCommand(client, testScheme, logf.Log.WithName("store_test"))
+		store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("store_test"), cc, nil, nil, nil, nil, nil, nil, testScheme)
+
+		l := newTestListener(store)
+
+		meterdef := newValidMeterDefinition()
+		meterdef.UID = "meterdef-uid"
+		meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+		meterdef.Spec.Workloads[0].WorkloadType = v1beta1.WorkloadTypeService
+		meterdef.Spec.Workloads[0].OwnerCRD = nil
+		meterdef.Spec.Workloads[0].LabelSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "app"},
+		}
+		require.NoError(t, store.Add(meterdef))
+
+		require.NoError(t, store.Add(service))
+		require.NoError(t, store.Add(endpoints))
+		assert.Empty(t, drainMessages(l))
+
+		relabeled := &corev1.Service{}
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "app", Namespace: "apps"}, relabeled))
+		relabeled.Labels = map[string]string{"app": "app"}
+		require.NoError(t, client.Update(context.TODO(), relabeled))
+
+		require.NoError(t, store.Update(relabeled))
+		assert.Len(t, drainMessages(l), 2)
+		assert.ElementsMatch(t, []string{"service-uid", "endpoints-uid"}, store.ListKeys())
+
+		vals := store.GetMeterDefinitionRefs(endpoints.UID)
+		require.Len(t, vals, 1)
+		assert.Equal(t, "app", vals[0].WorkloadResource.Name)
+
+		require.NoError(t, client.Delete(context.TODO(), relabeled))
+		require.NoError(t, store.Delete(relabeled))
+
+		msgs := drainMessages(l)
+		require.Len(t, msgs, 2)
+		for _, msg := range msgs {
+			assert.Equal(t, ObjectResourceMessageAction(DeleteMessageAction), msg.Action)
+		}
+		assert.Empty(t, store.List())
+	})
+}
+
+// newTestListener adds a listener to the store that is not running so its
+// messages stay queued for the test to read.
+func newTestListener(store *MeterDefinitionStore) *listener {
+	l := newListener("test", nil, store.listenerQueueSize, store.listenerMetrics)
+	store.listeners = append(store.listeners, l)
+	return l
+}
+
+func drainMessages(l *listener) []*ObjectResourceMessage {
+	msgs := []*ObjectResourceMessage{}
+	for msg := l.dequeue(); msg != nil; msg = l.dequeue() {
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
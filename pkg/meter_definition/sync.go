@@ -0,0 +1,142 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// syncedStore records when the reflector of the store has listed its
+// objects for the first time. The store is synced once the list is processed
+// even if some objects failed.
+type syncedStore struct {
+	cache.Store
+	synced int32
+}
+
+func (s *syncedStore) Replace(list []interface{}, resourceVersion string) error {
+	defer atomic.StoreInt32(&s.synced, 1)
+
+	return s.Store.Replace(list, resourceVersion)
+}
+
+func (s *syncedStore) HasSynced() bool {
+	return atomic.LoadInt32(&s.synced) == 1
+}
+
+// watchName is the name of the reflector of the type in the namespace.
+func watchName(expectedType runtime.Object, ns string) string {
+	name := reflect.TypeOf(expectedType).Elem().Name()
+
+	if ns == "" {
+		return name
+	}
+
+	return name + "/" + ns
+}
+
+// watch runs a reflector for the lister into the store and returns whether
+// it has synced.
+func (s *MeterDefinitionStore) watch(
+	name string,
+	lister cache.ListerWatcher,
+	expectedType runtime.Object,
+) cache.InformerSynced {
+	return s.watchUntil(name, lister, expectedType, s.ctx.Done())
+}
+
+// watchUntil runs a reflector for the lister into the store until stopCh is
+// closed and returns whether it has synced.
+func (s *MeterDefinitionStore) watchUntil(
+	name string,
+	lister cache.ListerWatcher,
+	expectedType runtime.Object,
+	stopCh <-chan struct{},
+) cache.InformerSynced {
+	store := &syncedStore{Store: s}
+
+	s.syncMutex.Lock()
+	if s.synced == nil {
+		s.synced = map[string]*syncedStore{}
+	}
+	s.synced[name] = store
+	s.syncMutex.Unlock()
+
+	reflector := cache.NewReflector(lister, expectedType, store, 0)
+	go reflector.Run(stopCh)
+
+	return store.HasSynced
+}
+
+// unwatch forgets the reflector of the name once it is stopped so the store
+// doesn't wait for it to sync.
+func (s *MeterDefinitionStore) unwatch(name string) {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	delete(s.synced, name)
+}
+
+// waitForSync waits for the reflectors to sync. It returns false if the
+// store is stopped first.
+func (s *MeterDefinitionStore) waitForSync(stage string, synced ...cache.InformerSynced) bool {
+	s.log.Info("waiting for reflectors to sync", "stage", stage)
+
+	if !cache.WaitForCacheSync(s.ctx.Done(), synced...) {
+		s.log.Info("store stopped before reflectors synced", "stage", stage)
+		return false
+	}
+
+	s.log.Info("reflectors synced", "stage", stage)
+	return true
+}
+
+// HasSynced returns true once every reflector of the store has listed its
+// objects and the listeners were sent the objects.
+func (s *MeterDefinitionStore) HasSynced() bool {
+	s.mutex.RLock()
+	workloadsSynced := s.workloadsSynced
+	s.mutex.RUnlock()
+
+	if !workloadsSynced {
+		return false
+	}
+
+	for _, synced := range s.SyncStatus() {
+		if !synced {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SyncStatus returns whether each reflector of the store has synced, by
+// type and namespace.
+func (s *MeterDefinitionStore) SyncStatus() map[string]bool {
+	s.syncMutex.RLock()
+	defer s.syncMutex.RUnlock()
+
+	status := make(map[string]bool, len(s.synced))
+	for name, store := range s.synced {
+		status[name] = store.HasSynced()
+	}
+
+	return status
+}
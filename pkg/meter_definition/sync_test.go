@@ -0,0 +1,101 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestStoreSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewMeterDefinitionStore(ctx, logf.Log.WithName("sync_test"), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	assert.Equal(t, "Pod/ns", watchName(&corev1.Pod{}, "ns"))
+	assert.Equal(t, "Namespace", watchName(&corev1.Namespace{}, ""))
+
+	pods := &syncedStore{Store: store}
+	services := &syncedStore{Store: store}
+	store.synced = map[string]*syncedStore{
+		"Pod/ns":     pods,
+		"Service/ns": services,
+	}
+
+	require.NoError(t, pods.Replace([]interface{}{}, ""))
+	assert.Equal(t, map[string]bool{"Pod/ns": true, "Service/ns": false}, store.SyncStatus())
+
+	require.NoError(t, services.Replace([]interface{}{}, ""))
+	assert.False(t, store.HasSynced(), "listeners were not sent the objects yet")
+
+	store.workloadsSynced = true
+	assert.True(t, store.HasSynced())
+
+	ch := make(chan *ObjectResourceMessage)
+	store.RegisterListener("late", ch)
+
+	select {
+	case msg := <-ch:
+		assert.Equal(t, ResyncMessageAction, msg.Action)
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener registered after sync did not get the objects")
+	}
+}
+
+func TestStoreSyncWithFailingObject(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(testScheme), testScheme, logf.Log.WithName("sync_test"))
+	store := NewMeterDefinitionStore(context.TODO(), logf.Log.WithName("sync_test"), cc, nil, nil, nil, nil, nil, nil, testScheme)
+	l := newTestListener(store)
+
+	meterdef := newValidMeterDefinition()
+	meterdef.UID = "meterdef-uid"
+	meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].LabelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "app"},
+	}
+	require.NoError(t, store.Add(meterdef))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "a",
+		Namespace: "apps",
+		UID:       "apps-a",
+		Labels:    map[string]string{"app": "app"},
+	}}
+
+	// the object that isn't a kubernetes object fails to be added
+	pods := &syncedStore{Store: store}
+	require.NoError(t, pods.Replace([]interface{}{"not-an-object", pod}, ""))
+	assert.True(t, pods.HasSynced())
+	assert.Len(t, drainMessages(l), 1)
+	assert.Equal(t, []string{"apps-a"}, store.ListKeys())
+}
@@ -0,0 +1,515 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	MeterDefinitionValidatingWebhookPath         = "/validate-marketplace-redhat-com-v1beta1-meterdefinition"
+	ClusterMeterDefinitionValidatingWebhookPath  = "/validate-marketplace-redhat-com-v1beta1-clustermeterdefinition"
+	MeterDefinitionRevisionValidatingWebhookPath = "/validate-marketplace-redhat-com-v1beta1-meterdefinitionrevision"
+)
+
+var (
+	supportedWorkloadVertexTypes = sets.NewString(
+		string(v1beta1.WorkloadVertexOperatorGroup),
+		string(v1beta1.WorkloadVertexNamespace),
+	)
+	supportedWorkloadTypes = sets.NewString(
+		string(v1beta1.WorkloadTypePod),
+		string(v1beta1.WorkloadTypeService),
+		string(v1beta1.WorkloadTypeServiceMonitor),
+		string(v1beta1.WorkloadTypePVC),
+		string(v1beta1.WorkloadTypeDeployment),
+		string(v1beta1.WorkloadTypeStatefulSet),
+		string(v1beta1.WorkloadTypeDaemonSet),
+		string(v1beta1.WorkloadTypeJob),
+		string(v1beta1.WorkloadTypeCronJob),
+		string(v1beta1.WorkloadTypeCustomResource),
+	)
+	supportedFieldFilterOperators = sets.NewString(
+		string(v1beta1.FieldFilterOpIn),
+		string(v1beta1.FieldFilterOpNotIn),
+		string(v1beta1.FieldFilterOpExists),
+		string(v1beta1.FieldFilterOpDoesNotExist),
+		string(v1beta1.FieldFilterOpHasPrefix),
+	)
+	supportedAggregations = sets.NewString("sum", "min", "max", "avg")
+)
+
+// ValidateMeterDefinition checks the meter definition for errors that would
+// otherwise only be found when building its filters or querying prometheus.
+// The mapper is used to check the owner CRDs of the workloads exist.
+func ValidateMeterDefinition(meterdef *v1beta1.MeterDefinition, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateVertex(&meterdef.Spec, specPath)...)
+	allErrs = append(allErrs, validateWorkloads(meterdef.Spec.Workloads, specPath.Child("workloads"), mapper)...)
+
+	return allErrs
+}
+
+// ValidateClusterMeterDefinition checks the cluster meter definition the same
+// way as a MeterDefinition, with its namespace selector and exclusions in
+// place of the vertex.
+func ValidateClusterMeterDefinition(meterdef *v1beta1.ClusterMeterDefinition, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if meterdef.Spec.NamespaceSelector != nil {
+		allErrs = append(allErrs, validateSelector(meterdef.Spec.NamespaceSelector, specPath.Child("namespaceSelector"))...)
+	}
+
+	excludedPath := specPath.Child("excludedNamespaces")
+	for i, ns := range meterdef.Spec.ExcludedNamespaces {
+		for _, msg := range apivalidation.ValidateNamespaceName(ns, false) {
+			allErrs = append(allErrs, field.Invalid(excludedPath.Index(i), ns, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateWorkloads(meterdef.Spec.Workloads, specPath.Child("workloads"), mapper)...)
+
+	return allErrs
+}
+
+func validateWorkloads(workloads []v1beta1.Workload, workloadsPath *field.Path, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(workloads) == 0 {
+		allErrs = append(allErrs, field.Required(workloadsPath, "at least 1 workload is required"))
+	}
+
+	names := sets.NewString()
+	for i := range workloads {
+		workload := &workloads[i]
+		workloadPath := workloadsPath.Index(i)
+
+		if names.Has(workload.Name) {
+			allErrs = append(allErrs, field.Duplicate(workloadPath.Child("name"), workload.Name))
+		}
+		names.Insert(workload.Name)
+
+		allErrs = append(allErrs, validateWorkload(workload, workloadPath, mapper)...)
+	}
+
+	return allErrs
+}
+
+func validateVertex(spec *v1beta1.MeterDefinitionSpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	vertexPath := specPath.Child("workloadVertexType")
+	selectorPath := specPath.Child("workloadVertexLabelSelector")
+
+	if spec.WorkloadVertexType != "" && !supportedWorkloadVertexTypes.Has(string(spec.WorkloadVertexType)) {
+		allErrs = append(allErrs, field.NotSupported(vertexPath, spec.WorkloadVertexType, supportedWorkloadVertexTypes.List()))
+	}
+
+	if spec.VertexLabelSelector == nil {
+		return allErrs
+	}
+
+	if spec.WorkloadVertexType != v1beta1.WorkloadVertexNamespace {
+		allErrs = append(allErrs, field.Forbidden(selectorPath, "may only be set when workloadVertexType is Namespace"))
+	}
+
+	return append(allErrs, validateSelector(spec.VertexLabelSelector, selectorPath)...)
+}
+
+func validateWorkload(workload *v1beta1.Workload, workloadPath *field.Path, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workload.Name == "" {
+		allErrs = append(allErrs, field.Required(workloadPath.Child("name"), ""))
+	}
+
+	if !supportedWorkloadTypes.Has(string(workload.WorkloadType)) {
+		allErrs = append(allErrs, field.NotSupported(workloadPath.Child("type"), workload.WorkloadType, supportedWorkloadTypes.List()))
+	}
+
+	customResourcePath := workloadPath.Child("customResource")
+	if workload.WorkloadType == v1beta1.WorkloadTypeCustomResource {
+		if workload.CustomResource == nil {
+			allErrs = append(allErrs, field.Required(customResourcePath, "required when type is CustomResource"))
+		} else {
+			allErrs = append(allErrs, validateCustomResource(workload.CustomResource, customResourcePath, mapper)...)
+		}
+	} else if workload.CustomResource != nil {
+		allErrs = append(allErrs, field.Forbidden(customResourcePath, "may only be set when type is CustomResource"))
+	}
+
+	if workload.WorkloadType != v1beta1.WorkloadTypeCustomResource &&
+		workload.LabelSelector == nil && workload.AnnotationSelector == nil && workload.OwnerCRD == nil &&
+		len(workload.FieldFilters) == 0 {
+		allErrs = append(allErrs, field.Required(workloadPath, "1 of ownerCRD, annotationSelector, labelSelector or fieldFilters is required"))
+	}
+
+	if workload.LabelSelector != nil {
+		allErrs = append(allErrs, validateSelector(workload.LabelSelector, workloadPath.Child("labelSelector"))...)
+	}
+
+	if workload.AnnotationSelector != nil {
+		allErrs = append(allErrs, validateSelector(workload.AnnotationSelector, workloadPath.Child("annotationSelector"))...)
+	}
+
+	fieldFiltersPath := workloadPath.Child("fieldFilters")
+	for j, fieldFilter := range workload.FieldFilters {
+		allErrs = append(allErrs, validateFieldFilter(fieldFilter, fieldFiltersPath.Index(j))...)
+	}
+
+	if workload.OwnerCRD != nil {
+		allErrs = append(allErrs, validateOwnerCRD(workload, workloadPath.Child("ownerCRD"), mapper)...)
+	} else {
+		if workload.OwnerDepth != nil {
+			allErrs = append(allErrs, field.Forbidden(workloadPath.Child("ownerDepth"), "may only be set with ownerCRD"))
+		}
+
+		if workload.MatchAnyOwner {
+			allErrs = append(allErrs, field.Forbidden(workloadPath.Child("matchAnyOwner"), "may only be set with ownerCRD"))
+		}
+	}
+
+	if workload.OwnerDepth != nil && *workload.OwnerDepth < 1 {
+		allErrs = append(allErrs, field.Invalid(workloadPath.Child("ownerDepth"), *workload.OwnerDepth, "must be at least 1"))
+	}
+
+	metricLabelsPath := workloadPath.Child("metricLabels")
+	if len(workload.MetricLabels) == 0 {
+		allErrs = append(allErrs, field.Required(metricLabelsPath, "at least 1 metric label is required"))
+	}
+
+	for j, metricLabel := range workload.MetricLabels {
+		allErrs = append(allErrs, validateMetricLabel(metricLabel, metricLabelsPath.Index(j))...)
+	}
+
+	return allErrs
+}
+
+func validateSelector(selector *metav1.LabelSelector, selectorPath *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabelSelector(selector, selectorPath)
+	if len(allErrs) != 0 {
+		return allErrs
+	}
+
+	// the filters use the selector as is, so it must also convert
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(selectorPath, selector, err.Error()))
+	}
+
+	return allErrs
+}
+
+func validateFieldFilter(fieldFilter v1beta1.FieldFilter, fieldFilterPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	jsonPathPath := fieldFilterPath.Child("jsonPath")
+	if fieldFilter.JSONPath == "" {
+		allErrs = append(allErrs, field.Required(jsonPathPath, ""))
+	} else if err := jsonpath.New("fieldFilter").Parse(fieldFilter.JSONPath); err != nil {
+		allErrs = append(allErrs, field.Invalid(jsonPathPath, fieldFilter.JSONPath, err.Error()))
+	}
+
+	valuesPath := fieldFilterPath.Child("values")
+	switch fieldFilter.Operator {
+	case v1beta1.FieldFilterOpIn, v1beta1.FieldFilterOpNotIn, v1beta1.FieldFilterOpHasPrefix:
+		if len(fieldFilter.Values) == 0 {
+			allErrs = append(allErrs, field.Required(valuesPath, "must be specified when operator is In, NotIn or HasPrefix"))
+		}
+	case v1beta1.FieldFilterOpExists, v1beta1.FieldFilterOpDoesNotExist:
+		if len(fieldFilter.Values) != 0 {
+			allErrs = append(allErrs, field.Forbidden(valuesPath, "may not be specified when operator is Exists or DoesNotExist"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fieldFilterPath.Child("operator"), fieldFilter.Operator, supportedFieldFilterOperators.List()))
+	}
+
+	return allErrs
+}
+
+func validateOwnerCRD(workload *v1beta1.Workload, ownerPath *field.Path, mapper meta.RESTMapper) field.ErrorList {
+	owner := workload.OwnerCRD
+
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil || (gv.Version != v1beta1.OwnerWildcard && owner.Kind != v1beta1.OwnerWildcard) {
+		return validateGroupVersionKind(owner, ownerPath, mapper)
+	}
+
+	allErrs := field.ErrorList{}
+
+	if owner.Kind == "" {
+		return append(allErrs, field.Required(ownerPath.Child("kind"), ""))
+	}
+
+	// any kind of the group may be an owner so only a specific kind is checked
+	if mapper == nil || owner.Kind == v1beta1.OwnerWildcard {
+		return allErrs
+	}
+
+	groupKind := schema.GroupKind{Group: gv.Group, Kind: owner.Kind}
+	if _, err := mapper.RESTMappings(groupKind); err != nil {
+		if meta.IsNoMatchError(err) {
+			return append(allErrs, field.NotFound(ownerPath, groupKind.String()))
+		}
+
+		return append(allErrs, field.InternalError(ownerPath, err))
+	}
+
+	return allErrs
+}
+
+func validateCustomResource(customResource *v1beta1.CustomResourceWorkload, customResourcePath *field.Path, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := validateGroupVersionKind(&customResource.GroupVersionKind, customResourcePath, mapper)
+
+	valueFieldsPath := customResourcePath.Child("valueFields")
+	names := sets.NewString()
+
+	for i, valueField := range customResource.ValueFields {
+		valueFieldPath := valueFieldsPath.Index(i)
+
+		if valueField.Name == "" {
+			allErrs = append(allErrs, field.Required(valueFieldPath.Child("name"), ""))
+		} else if names.Has(valueField.Name) {
+			allErrs = append(allErrs, field.Duplicate(valueFieldPath.Child("name"), valueField.Name))
+		}
+		names.Insert(valueField.Name)
+
+		if valueField.JSONPath == "" {
+			allErrs = append(allErrs, field.Required(valueFieldPath.Child("jsonPath"), ""))
+		} else if err := jsonpath.New(valueField.Name).Parse(valueField.JSONPath); err != nil {
+			allErrs = append(allErrs, field.Invalid(valueFieldPath.Child("jsonPath"), valueField.JSONPath, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateGroupVersionKind(gvk *common.GroupVersionKind, gvkPath *field.Path, mapper meta.RESTMapper) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	gv, err := schema.ParseGroupVersion(gvk.APIVersion)
+	if err != nil {
+		return append(allErrs, field.Invalid(gvkPath.Child("apiVersion"), gvk.APIVersion, err.Error()))
+	}
+
+	if gvk.Kind == "" {
+		return append(allErrs, field.Required(gvkPath.Child("kind"), ""))
+	}
+
+	if mapper == nil {
+		return allErrs
+	}
+
+	if _, err := mapper.RESTMapping(gv.WithKind(gvk.Kind).GroupKind(), gv.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return append(allErrs, field.NotFound(gvkPath, gv.WithKind(gvk.Kind).String()))
+		}
+
+		return append(allErrs, field.InternalError(gvkPath, err))
+	}
+
+	return allErrs
+}
+
+func validateMetricLabel(metricLabel v1beta1.MeterLabelQuery, metricLabelPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if metricLabel.Label == "" {
+		allErrs = append(allErrs, field.Required(metricLabelPath.Child("label"), ""))
+	}
+
+	if metricLabel.Aggregation != "" && !supportedAggregations.Has(metricLabel.Aggregation) {
+		allErrs = append(allErrs, field.NotSupported(metricLabelPath.Child("aggregation"), metricLabel.Aggregation, supportedAggregations.List()))
+	}
+
+	if metricLabel.Query != "" {
+		if _, err := parser.ParseExpr(metricLabel.Query); err != nil {
+			allErrs = append(allErrs, field.Invalid(metricLabelPath.Child("query"), metricLabel.Query, err.Error()))
+		}
+	} else if metricLabel.Label != "" && !model.IsValidMetricName(model.LabelValue(metricLabel.Label)) {
+		// without a query the label is queried as the metric name
+		allErrs = append(allErrs, field.Invalid(metricLabelPath.Child("label"), metricLabel.Label, "must be a valid metric name when query is not set"))
+	}
+
+	return allErrs
+}
+
+// MeterDefinitionValidator is the validating admission webhook for
+// MeterDefinitions.
+type MeterDefinitionValidator struct {
+	Mapper  meta.RESTMapper
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &MeterDefinitionValidator{}
+var _ admission.DecoderInjector = &MeterDefinitionValidator{}
+
+func (v *MeterDefinitionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	meterdef := &v1beta1.MeterDefinition{}
+	if err := v.decoder.Decode(req, meterdef); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		oldMeterdef := &v1beta1.MeterDefinition{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldMeterdef); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		if skipUpdateValidation(meterdef, meterdef.Spec, oldMeterdef.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	return validationResponse("MeterDefinition", meterdef.Name, ValidateMeterDefinition(meterdef, v.Mapper))
+}
+
+func (v *MeterDefinitionValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// ClusterMeterDefinitionValidator is the validating admission webhook for
+// ClusterMeterDefinitions.
+type ClusterMeterDefinitionValidator struct {
+	Mapper  meta.RESTMapper
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ClusterMeterDefinitionValidator{}
+var _ admission.DecoderInjector = &ClusterMeterDefinitionValidator{}
+
+func (v *ClusterMeterDefinitionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	meterdef := &v1beta1.ClusterMeterDefinition{}
+	if err := v.decoder.Decode(req, meterdef); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		oldMeterdef := &v1beta1.ClusterMeterDefinition{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldMeterdef); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		if skipUpdateValidation(meterdef, meterdef.Spec, oldMeterdef.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	return validationResponse("ClusterMeterDefinition", meterdef.Name, ValidateClusterMeterDefinition(meterdef, v.Mapper))
+}
+
+func (v *ClusterMeterDefinitionValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// ValidateMeterDefinitionRevisionUpdate checks the spec of the revision has
+// not changed. Revisions are the record of the meter rules a report was run
+// with so they can not be changed once created.
+func ValidateMeterDefinitionRevisionUpdate(revision, oldRevision *v1beta1.MeterDefinitionRevision) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !equality.Semantic.DeepEqual(revision.Spec, oldRevision.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "is immutable"))
+	}
+
+	return allErrs
+}
+
+// MeterDefinitionRevisionValidator is the validating admission webhook for
+// MeterDefinitionRevisions.
+type MeterDefinitionRevisionValidator struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &MeterDefinitionRevisionValidator{}
+var _ admission.DecoderInjector = &MeterDefinitionRevisionValidator{}
+
+func (v *MeterDefinitionRevisionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	revision := &v1beta1.MeterDefinitionRevision{}
+	if err := v.decoder.Decode(req, revision); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	oldRevision := &v1beta1.MeterDefinitionRevision{}
+	if err := v.decoder.DecodeRaw(req.OldObject, oldRevision); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	return validationResponse("MeterDefinitionRevision", revision.Name, ValidateMeterDefinitionRevisionUpdate(revision, oldRevision))
+}
+
+func (v *MeterDefinitionRevisionValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// skipUpdateValidation returns true if an update does not change the spec or
+// the object is being deleted. Objects that were created before a rule was
+// added, or that reference a CRD that was removed, can still have their
+// metadata and status updated and their finalizers removed.
+func skipUpdateValidation(obj metav1.Object, spec, oldSpec interface{}) bool {
+	return obj.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(spec, oldSpec)
+}
+
+// validationResponse allows the request if there are no errors and denies it
+// with an Invalid status otherwise.
+func validationResponse(kind, name string, allErrs field.ErrorList) admission.Response {
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+
+	invalid := apierrors.NewInvalid(
+		v1beta1.SchemeGroupVersion.WithKind(kind).GroupKind(),
+		name,
+		allErrs,
+	)
+
+	response := admission.Denied(invalid.Error())
+	response.Result = &invalid.ErrStatus
+	return response
+}
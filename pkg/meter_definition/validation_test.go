@@ -0,0 +1,307 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newValidMeterDefinition() *v1beta1.MeterDefinition {
+	return &v1beta1.MeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-meterdefinition",
+			Namespace: "default",
+		},
+		Spec: v1beta1.MeterDefinitionSpec{
+			Group:              "partner.metering.com",
+			Kind:               "App",
+			WorkloadVertexType: v1beta1.WorkloadVertexOperatorGroup,
+			Workloads: []v1beta1.Workload{
+				{
+					Name:         "app-pods",
+					WorkloadType: v1beta1.WorkloadTypePod,
+					OwnerCRD: &common.GroupVersionKind{
+						APIVersion: "partner.metering.com/v1alpha1",
+						Kind:       "App",
+					},
+					MetricLabels: []v1beta1.MeterLabelQuery{
+						{Label: "container_spec_cpu_shares", Aggregation: "sum"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestMapper() meta.RESTMapper {
+	gv := schema.GroupVersion{Group: "partner.metering.com", Version: "v1alpha1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+	mapper.Add(gv.WithKind("App"), meta.RESTScopeNamespace)
+	return mapper
+}
+
+func errorFields(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestValidateMeterDefinition(t *testing.T) {
+	mapper := newTestMapper()
+
+	meterdef := newValidMeterDefinition()
+	assert.Empty(t, ValidateMeterDefinition(meterdef, mapper))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads = append(meterdef.Spec.Workloads, meterdef.Spec.Workloads[0])
+	assert.Equal(t, []string{"spec.workloads[1].name"}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].MetricLabels[0].Query = "sum(rate(http_requests_total[5m]"
+	meterdef.Spec.Workloads[0].MetricLabels[0].Aggregation = "count"
+	assert.Equal(t, []string{
+		"spec.workloads[0].metricLabels[0].aggregation",
+		"spec.workloads[0].metricLabels[0].query",
+	}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].OwnerCRD.Kind = "Missing"
+	meterdef.Spec.Workloads[0].LabelSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: "Contains"},
+		},
+	}
+	assert.Equal(t, []string{
+		"spec.workloads[0].labelSelector.matchExpressions[0].operator",
+		"spec.workloads[0].ownerCRD",
+	}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	assert.Equal(t, []string{"spec.workloads[0]"}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.VertexLabelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"metered": "true"},
+	}
+	assert.Equal(t, []string{"spec.workloadVertexLabelSelector"}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef.Spec.WorkloadVertexType = v1beta1.WorkloadVertexNamespace
+	assert.Empty(t, ValidateMeterDefinition(meterdef, mapper))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].WorkloadType = v1beta1.WorkloadTypeCustomResource
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].CustomResource = &v1beta1.CustomResourceWorkload{
+		GroupVersionKind: common.GroupVersionKind{
+			APIVersion: "partner.metering.com/v1alpha1",
+			Kind:       "App",
+		},
+		ValueFields: []v1beta1.CustomResourceValueField{
+			{Name: "size", JSONPath: "{.spec.size}"},
+		},
+	}
+	assert.Empty(t, ValidateMeterDefinition(meterdef, mapper))
+
+	meterdef.Spec.Workloads[0].CustomResource.ValueFields = append(
+		meterdef.Spec.Workloads[0].CustomResource.ValueFields,
+		v1beta1.CustomResourceValueField{Name: "size", JSONPath: "{.spec.size"},
+	)
+	assert.Equal(t, []string{
+		"spec.workloads[0].customResource.valueFields[1].name",
+		"spec.workloads[0].customResource.valueFields[1].jsonPath",
+	}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef.Spec.Workloads[0].CustomResource = nil
+	assert.Equal(t, []string{"spec.workloads[0].customResource"}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].OwnerCRD = nil
+	meterdef.Spec.Workloads[0].FieldFilters = []v1beta1.FieldFilter{
+		{JSONPath: "{.spec.containers[*].image}", Operator: v1beta1.FieldFilterOpHasPrefix, Values: []string{"registry.example.com/licensed/"}},
+	}
+	assert.Empty(t, ValidateMeterDefinition(meterdef, mapper))
+
+	meterdef.Spec.Workloads[0].FieldFilters = append(meterdef.Spec.Workloads[0].FieldFilters,
+		v1beta1.FieldFilter{JSONPath: "{.status.phase", Operator: v1beta1.FieldFilterOpIn},
+		v1beta1.FieldFilter{JSONPath: "{.spec.nodeName}", Operator: v1beta1.FieldFilterOpExists, Values: []string{"node"}},
+		v1beta1.FieldFilter{JSONPath: "{.spec.nodeName}", Operator: "Matches"},
+	)
+	assert.Equal(t, []string{
+		"spec.workloads[0].fieldFilters[1].jsonPath",
+		"spec.workloads[0].fieldFilters[1].values",
+		"spec.workloads[0].fieldFilters[2].values",
+		"spec.workloads[0].fieldFilters[3].operator",
+	}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+
+	meterdef = newValidMeterDefinition()
+	meterdef.Spec.Workloads[0].OwnerCRD.APIVersion = "partner.metering.com/*"
+	meterdef.Spec.Workloads[0].MatchAnyOwner = true
+	assert.Empty(t, ValidateMeterDefinition(meterdef, mapper))
+
+	meterdef.Spec.Workloads[0].OwnerCRD.Kind = "Missing"
+	depth := int32(0)
+	meterdef.Spec.Workloads[0].OwnerDepth = &depth
+	assert.Equal(t, []string{
+		"spec.workloads[0].ownerCRD",
+		"spec.workloads[0].ownerDepth",
+	}, errorFields(ValidateMeterDefinition(meterdef, mapper)))
+}
+
+func TestValidateClusterMeterDefinition(t *testing.T) {
+	mapper := newTestMapper()
+
+	meterdef := &v1beta1.ClusterMeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-clustermeterdefinition"},
+		Spec: v1beta1.ClusterMeterDefinitionSpec{
+			Group:              "partner.metering.com",
+			Kind:               "App",
+			ExcludedNamespaces: []string{"kube-system"},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"metered": "true"},
+			},
+			Workloads: newValidMeterDefinition().Spec.Workloads,
+		},
+	}
+	assert.Empty(t, ValidateClusterMeterDefinition(meterdef, mapper))
+
+	meterdef.Spec.ExcludedNamespaces = append(meterdef.Spec.ExcludedNamespaces, "Not_A_Namespace")
+	meterdef.Spec.NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{Key: "metered", Operator: "Contains"},
+	}
+	meterdef.Spec.Workloads = nil
+	assert.Equal(t, []string{
+		"spec.namespaceSelector.matchExpressions[0].operator",
+		"spec.excludedNamespaces[1]",
+		"spec.workloads",
+	}, errorFields(ValidateClusterMeterDefinition(meterdef, mapper)))
+}
+
+func TestValidateMeterDefinitionRevisionUpdate(t *testing.T) {
+	oldRevision := &v1beta1.MeterDefinitionRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "uid-1"},
+		Spec: v1beta1.MeterDefinitionRevisionSpec{
+			Revision:            1,
+			Hash:                "1234",
+			MeterDefinitionSpec: newValidMeterDefinition().Spec,
+		},
+	}
+
+	revision := oldRevision.DeepCopy()
+	revision.Labels = map[string]string{"team": "metering"}
+	assert.Empty(t, ValidateMeterDefinitionRevisionUpdate(revision, oldRevision))
+
+	revision.Spec.MeterDefinitionSpec.Kind = "App2"
+	assert.Equal(t, []string{"spec"}, errorFields(ValidateMeterDefinitionRevisionUpdate(revision, oldRevision)))
+}
+
+func newAdmissionRequest(t *testing.T, operation admissionv1beta1.Operation, obj, oldObj runtime.Object) admission.Request {
+	raw, err := json.Marshal(obj)
+	assert.NoError(t, err)
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+
+	if oldObj != nil {
+		oldRaw, err := json.Marshal(oldObj)
+		assert.NoError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: oldRaw}
+	}
+
+	return req
+}
+
+func TestMeterDefinitionValidatorUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1beta1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NoError(t, err)
+
+	validator := &MeterDefinitionValidator{Mapper: newTestMapper()}
+	assert.NoError(t, validator.InjectDecoder(decoder))
+
+	// the owner CRD was removed after the meterdef was created
+	oldMeterdef := newValidMeterDefinition()
+	oldMeterdef.Spec.Workloads[0].OwnerCRD.Kind = "Removed"
+
+	meterdef := oldMeterdef.DeepCopy()
+	meterdef.Labels = map[string]string{"team": "metering"}
+	resp := validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Update, meterdef, oldMeterdef))
+	assert.True(t, resp.Allowed)
+
+	meterdef = oldMeterdef.DeepCopy()
+	meterdef.Spec.Workloads[0].Name = "app-pods-2"
+	resp = validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Update, meterdef, oldMeterdef))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Result.Code)
+
+	now := metav1.Now()
+	meterdef.DeletionTimestamp = &now
+	resp = validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Update, meterdef, oldMeterdef))
+	assert.True(t, resp.Allowed)
+
+	resp = validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Create, oldMeterdef, nil))
+	assert.False(t, resp.Allowed)
+}
+
+func TestClusterMeterDefinitionValidatorUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1beta1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	assert.NoError(t, err)
+
+	validator := &ClusterMeterDefinitionValidator{Mapper: newTestMapper()}
+	assert.NoError(t, validator.InjectDecoder(decoder))
+
+	oldMeterdef := &v1beta1.ClusterMeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-clustermeterdefinition"},
+		Spec: v1beta1.ClusterMeterDefinitionSpec{
+			Group:              "partner.metering.com",
+			Kind:               "App",
+			ExcludedNamespaces: []string{"Not_A_Namespace"},
+			Workloads:          newValidMeterDefinition().Spec.Workloads,
+		},
+	}
+
+	meterdef := oldMeterdef.DeepCopy()
+	meterdef.Annotations = map[string]string{"team": "metering"}
+	resp := validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Update, meterdef, oldMeterdef))
+	assert.True(t, resp.Allowed)
+
+	meterdef.Spec.Kind = "App2"
+	resp = validator.Handle(context.TODO(), newAdmissionRequest(t, admissionv1beta1.Update, meterdef, oldMeterdef))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Result.Code)
+}
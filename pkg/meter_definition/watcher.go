@@ -18,10 +18,14 @@ import (
 	"context"
 
 	monitoringv1client "github.com/coreos/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
-	marketplacev1alpha1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	marketplacev1beta1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
@@ -70,7 +74,135 @@ func CreateServiceListWatch(kubeClient clientset.Interface, ns string) cache.Lis
 	}
 }
 
-func CreateMeterDefinitionWatch(c *marketplacev1alpha1client.MarketplaceV1alpha1Client, ns string) cache.ListerWatcher {
+func CreateEndpointsListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Endpoints(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Endpoints(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateDeploymentListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.AppsV1().Deployments(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.AppsV1().Deployments(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateStatefulSetListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.AppsV1().StatefulSets(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.AppsV1().StatefulSets(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateDaemonSetListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.AppsV1().DaemonSets(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.AppsV1().DaemonSets(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateJobListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.BatchV1().Jobs(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.BatchV1().Jobs(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateCronJobListWatch(kubeClient clientset.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.BatchV1beta1().CronJobs(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.BatchV1beta1().CronJobs(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateCustomResourceListWatch(c dynamic.Interface, resource schema.GroupVersionResource, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.Resource(resource).Namespace(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return c.Resource(resource).Namespace(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func CreateNamespaceListWatch(kubeClient clientset.Interface) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Namespaces().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Namespaces().Watch(context.TODO(), opts)
+		},
+	}
+}
+
+var csvResource = olmv1alpha1.SchemeGroupVersion.WithResource("clusterserviceversions")
+
+// CreateCSVListWatch lists and watches ClusterServiceVersions with the
+// dynamic client and converts them to typed objects.
+func CreateCSVListWatch(c dynamic.Interface, ns string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			list, err := c.Resource(csvResource).Namespace(ns).List(context.TODO(), opts)
+			if err != nil {
+				return nil, err
+			}
+
+			csvList := &olmv1alpha1.ClusterServiceVersionList{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(list.UnstructuredContent(), csvList)
+			return csvList, err
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			w, err := c.Resource(csvResource).Namespace(ns).Watch(context.TODO(), opts)
+			if err != nil {
+				return nil, err
+			}
+
+			return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+				u, ok := in.Object.(*unstructured.Unstructured)
+				if !ok {
+					return in, true
+				}
+
+				csv := &olmv1alpha1.ClusterServiceVersion{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), csv); err != nil {
+					return in, false
+				}
+
+				in.Object = csv
+				return in, true
+			}), nil
+		},
+	}
+}
+
+func CreateMeterDefinitionWatch(c *marketplacev1beta1client.MarketplaceV1beta1Client, ns string) cache.ListerWatcher {
 	return &cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
 			return c.MeterDefinitions(ns).List(context.TODO(), opts)
@@ -80,3 +212,14 @@ func CreateMeterDefinitionWatch(c *marketplacev1alpha1client.MarketplaceV1alpha1
 		},
 	}
 }
+
+func CreateClusterMeterDefinitionWatch(c *marketplacev1beta1client.MarketplaceV1beta1Client) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.ClusterMeterDefinitions().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return c.ClusterMeterDefinitions().Watch(context.TODO(), opts)
+		},
+	}
+}
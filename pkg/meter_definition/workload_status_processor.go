@@ -0,0 +1,322 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/prometheus"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	DefaultWorkloadStatusInterval = 5 * time.Minute
+	DefaultWorkloadStatusWindow   = time.Hour
+
+	workloadStatusStep         = time.Minute
+	workloadStatusQueryTimeout = 30 * time.Second
+)
+
+// WorkloadStatusConfig configures the WorkloadStatusProcessor.
+type WorkloadStatusConfig struct {
+	// API is the prometheus api the metrics are queried from. The
+	// processor is disabled if it is nil.
+	API v1.API
+
+	// Interval between updates of the workload status.
+	Interval time.Duration
+
+	// Window of samples the latest value of a metric is read from.
+	Window time.Duration
+}
+
+// WorkloadStatusProcessor periodically queries prometheus for the metrics
+// of each workload and records their latest values in the meter
+// definition status.
+type WorkloadStatusProcessor struct {
+	log    logr.Logger
+	cc     ClientCommandRunner
+	config WorkloadStatusConfig
+}
+
+// NewWorkloadStatusProcessor is the provider that creates
+// the processor.
+func NewWorkloadStatusProcessor(
+	log logr.Logger,
+	cc ClientCommandRunner,
+	config *WorkloadStatusConfig,
+) *WorkloadStatusProcessor {
+	c := *config
+
+	if c.Interval <= 0 {
+		c.Interval = DefaultWorkloadStatusInterval
+	}
+
+	if c.Window <= 0 {
+		c.Window = DefaultWorkloadStatusWindow
+	}
+
+	return &WorkloadStatusProcessor{
+		log:    log.WithValues("process", "workloadStatusProcessor"),
+		cc:     cc,
+		config: c,
+	}
+}
+
+// Start updates the workload status of all the meter definitions every
+// interval until the context is closed.
+func (u *WorkloadStatusProcessor) Start(ctx context.Context) error {
+	if u.config.API == nil {
+		u.log.Info("prometheus is not configured, workload status is disabled")
+		return nil
+	}
+
+	ticker := time.NewTicker(u.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.processAll(ctx, time.Now()); err != nil {
+			u.log.Error(err, "failed to update workload status")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *WorkloadStatusProcessor) processAll(ctx context.Context, now time.Time) error {
+	meterDefinitionList := &marketplacev1beta1.MeterDefinitionList{}
+
+	result, _ := u.cc.Do(ctx, ListAction(meterDefinitionList))
+	if result.Is(Error) {
+		return errors.Wrap(result, "failed to list meterdefinitions")
+	}
+
+	clusterMeterDefinitionList := &marketplacev1beta1.ClusterMeterDefinitionList{}
+
+	result, _ = u.cc.Do(ctx, ListAction(clusterMeterDefinitionList))
+	if result.Is(Error) {
+		return errors.Wrap(result, "failed to list clustermeterdefinitions")
+	}
+
+	mdefs := make([]*marketplacev1beta1.MeterDefinition, 0, len(meterDefinitionList.Items)+len(clusterMeterDefinitionList.Items))
+	for i := range meterDefinitionList.Items {
+		mdefs = append(mdefs, &meterDefinitionList.Items[i])
+	}
+	for i := range clusterMeterDefinitionList.Items {
+		mdefs = append(mdefs, clusterMeterDefinitionList.Items[i].ToMeterDefinition())
+	}
+
+	var errs []error
+	for _, mdef := range mdefs {
+		key := types.NamespacedName{Name: mdef.Name, Namespace: mdef.Namespace}
+
+		if err := u.process(ctx, key, mdef, now); err != nil {
+			errs = append(errs, errors.WithDetails(err, "mdef", key.String()))
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+// process queries the metrics of a meter definition and updates its
+// workload status and reporting condition. A key without a namespace is
+// a ClusterMeterDefinition.
+func (u *WorkloadStatusProcessor) process(
+	ctx context.Context,
+	key types.NamespacedName,
+	mdef *marketplacev1beta1.MeterDefinition,
+	now time.Time,
+) error {
+	workloadStatus, condition, ok := u.queryWorkloadStatus(ctx, mdef, now)
+	if !ok {
+		return nil
+	}
+
+	obj, mdefStatus := newMeterDefinitionObject(key)
+
+	result, _ := u.cc.Do(ctx,
+		HandleResult(
+			GetAction(key, obj),
+			OnContinue(Call(func() (ClientAction, error) {
+				changed := mdefStatus.Conditions.SetCondition(condition)
+
+				if !reflect.DeepEqual(mdefStatus.WorkloadStatus, workloadStatus) {
+					mdefStatus.WorkloadStatus = workloadStatus
+					changed = true
+				}
+
+				if !changed {
+					return nil, nil
+				}
+
+				u.log.Info("updating workload status", "mdef", key, "reason", condition.Reason)
+				return UpdateAction(obj, UpdateStatusOnly(true)), nil
+			})),
+		),
+	)
+
+	if result.Is(NotFound) {
+		return nil
+	}
+
+	if result.Is(Error) {
+		return result
+	}
+
+	return nil
+}
+
+// queryWorkloadStatus runs the query of each metric of each workload over
+// the window and returns the latest values and the reporting condition.
+// It returns false if the meter definition has no metrics.
+func (u *WorkloadStatusProcessor) queryWorkloadStatus(
+	ctx context.Context,
+	mdef *marketplacev1beta1.MeterDefinition,
+	now time.Time,
+) ([]marketplacev1beta1.WorkloadStatus, status.Condition, bool) {
+	var (
+		workloadStatus []marketplacev1beta1.WorkloadStatus
+		failed, empty  []string
+		queried        bool
+	)
+
+	for _, workload := range mdef.Spec.Workloads {
+		for _, metric := range workload.MetricLabels {
+			queried = true
+			name := fmt.Sprintf("%s/%s", workload.Name, metric.Label)
+
+			query := &prometheus.PromQuery{
+				Metric: metric.Label,
+				Type:   workload.WorkloadType,
+				MeterDef: types.NamespacedName{
+					Name:      mdef.Name,
+					Namespace: mdef.Namespace,
+				},
+				Query:         metric.Query,
+				Start:         now.Add(-u.config.Window),
+				End:           now,
+				Step:          workloadStatusStep,
+				AggregateFunc: metric.Aggregation,
+			}
+
+			value, err := u.queryRange(ctx, query)
+			if err != nil {
+				u.log.Error(err, "failed to query metric", "mdef", query.MeterDef, "workload", workload.Name, "metric", metric.Label)
+				failed = append(failed, fmt.Sprintf("%s: %s", name, err.Error()))
+				continue
+			}
+
+			current, readTime, ok := latestValue(value)
+			if !ok {
+				empty = append(empty, name)
+				continue
+			}
+
+			workloadStatus = append(workloadStatus, marketplacev1beta1.WorkloadStatus{
+				Name:               workload.Name,
+				Metric:             metric.Label,
+				CurrentMetricValue: strconv.FormatFloat(current, 'f', -1, 64),
+				LastReadTime:       metav1.NewTime(readTime),
+			})
+		}
+	}
+
+	var condition status.Condition
+
+	switch {
+	case len(failed) != 0:
+		condition = marketplacev1beta1.MeterDefConditionMetricQueryFailed
+		condition.Message = "Queries failed for " + strings.Join(failed, "; ")
+	case len(empty) != 0:
+		condition = marketplacev1beta1.MeterDefConditionNoMetricValues
+		condition.Message = "No values in the last " + u.config.Window.String() + " for " + strings.Join(empty, ", ")
+	default:
+		condition = marketplacev1beta1.MeterDefConditionReporting
+	}
+
+	return workloadStatus, condition, queried
+}
+
+func (u *WorkloadStatusProcessor) queryRange(ctx context.Context, query *prometheus.PromQuery) (model.Value, error) {
+	ctx, cancel := context.WithTimeout(ctx, workloadStatusQueryTimeout)
+	defer cancel()
+
+	value, warnings, err := u.config.API.QueryRange(ctx, query.String(), v1.Range{
+		Start: query.Start,
+		End:   query.End,
+		Step:  query.Step,
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error with query")
+	}
+
+	if len(warnings) > 0 {
+		u.log.Info("warnings", "warnings", warnings)
+	}
+
+	return value, nil
+}
+
+// latestValue returns the sum of the series that have a sample at the
+// latest time of the range result.
+func latestValue(value model.Value) (float64, time.Time, bool) {
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+
+	var (
+		latest model.Time
+		sum    float64
+		found  bool
+	)
+
+	for _, stream := range matrix {
+		if len(stream.Values) == 0 {
+			continue
+		}
+
+		sample := stream.Values[len(stream.Values)-1]
+
+		switch {
+		case !found || sample.Timestamp.After(latest):
+			latest = sample.Timestamp
+			sum = float64(sample.Value)
+			found = true
+		case sample.Timestamp.Equal(latest):
+			sum = sum + float64(sample.Value)
+		}
+	}
+
+	return sum, latest.Time(), found
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meter_definition
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestLatestValue(t *testing.T) {
+	matrix := model.Matrix{
+		{
+			Metric: model.Metric{"pod": "a"},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+		},
+		{
+			Metric: model.Metric{"pod": "b"},
+			Values: []model.SamplePair{{Timestamp: 2000, Value: 3}},
+		},
+		{
+			// a pod that is gone is not part of the latest value
+			Metric: model.Metric{"pod": "c"},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: 10}},
+		},
+	}
+
+	value, readTime, ok := latestValue(matrix)
+	assert.True(t, ok)
+	assert.Equal(t, float64(5), value)
+	assert.Equal(t, model.Time(2000).Time(), readTime)
+
+	_, _, ok = latestValue(model.Matrix{})
+	assert.False(t, ok)
+}
+
+func TestWorkloadStatusProcessor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		query := r.Form.Get("query")
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.Contains(query, "app_requests_total"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"pod":"app-1","namespace":"apps"},"values":[[1600000000,"1"],[1600000060,"4"]]},` +
+				`{"metric":{"pod":"app-2","namespace":"apps"},"values":[[1600000060,"2.5"]]}]}}`))
+		case strings.Contains(query, "app_storage_bytes"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	apiClient, err := api.NewClient(api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	mdef := &v1beta1.MeterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-meterdef", Namespace: "apps"},
+		Spec: v1beta1.MeterDefinitionSpec{
+			Group: "partner.metering.com",
+			Kind:  "App",
+			Workloads: []v1beta1.Workload{
+				{
+					Name:         "app-pods",
+					WorkloadType: v1beta1.WorkloadTypePod,
+					MetricLabels: []v1beta1.MeterLabelQuery{
+						{Label: "app_requests_total", Aggregation: "sum"},
+					},
+				},
+			},
+		},
+	}
+
+	testScheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(testScheme))
+
+	k8sClient := fake.NewFakeClientWithScheme(testScheme, mdef)
+	logger := logf.Log.WithName("workload_status_test")
+	cc := reconcileutils.NewClientCommand(k8sClient, testScheme, logger)
+
+	sut := NewWorkloadStatusProcessor(logger, cc, &WorkloadStatusConfig{API: v1.NewAPI(apiClient)})
+	key := types.NamespacedName{Name: "app-meterdef", Namespace: "apps"}
+
+	require.NoError(t, sut.processAll(context.TODO(), time.Unix(1600000100, 0)))
+	require.NoError(t, k8sClient.Get(context.TODO(), key, mdef))
+
+	assert.Equal(t, []v1beta1.WorkloadStatus{
+		{
+			Name:               "app-pods",
+			Metric:             "app_requests_total",
+			CurrentMetricValue: "6.5",
+			LastReadTime:       metav1.NewTime(time.Unix(1600000060, 0)),
+		},
+	}, mdef.Status.WorkloadStatus)
+
+	cond := mdef.Status.Conditions.GetCondition(v1beta1.MeterDefConditionTypeReporting)
+	require.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+
+	mdef.Spec.Workloads[0].MetricLabels = append(mdef.Spec.Workloads[0].MetricLabels,
+		v1beta1.MeterLabelQuery{Label: "app_storage_bytes", Aggregation: "sum"})
+	require.NoError(t, k8sClient.Update(context.TODO(), mdef))
+
+	require.NoError(t, sut.processAll(context.TODO(), time.Unix(1600000100, 0)))
+	require.NoError(t, k8sClient.Get(context.TODO(), key, mdef))
+
+	cond = mdef.Status.Conditions.GetCondition(v1beta1.MeterDefConditionTypeReporting)
+	require.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+	assert.Equal(t, v1beta1.MeterDefConditionReasonNoMetricValues, cond.Reason)
+	assert.Contains(t, cond.Message, "app-pods/app_storage_bytes")
+	assert.Len(t, mdef.Status.WorkloadStatus, 1)
+
+	mdef.Spec.Workloads[0].MetricLabels = append(mdef.Spec.Workloads[0].MetricLabels,
+		v1beta1.MeterLabelQuery{Label: "app_broken", Query: "app_broken{", Aggregation: "sum"})
+	require.NoError(t, k8sClient.Update(context.TODO(), mdef))
+
+	require.NoError(t, sut.processAll(context.TODO(), time.Unix(1600000100, 0)))
+	require.NoError(t, k8sClient.Get(context.TODO(), key, mdef))
+
+	cond = mdef.Status.Conditions.GetCondition(v1beta1.MeterDefConditionTypeReporting)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1beta1.MeterDefConditionReasonMetricQueryFailed, cond.Reason)
+	assert.Contains(t, cond.Message, "app-pods/app_broken")
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	debugPath          = "/debug/"
+	debugMeterDefsPath = "/debug/meterdefs"
+	debugObjectsPath   = "/debug/objects/"
+	debugExplainPath   = "/debug/explain"
+)
+
+// debugHandler serves the lookups of the meter definition store as JSON to
+// explain why objects match a meter definition or not.
+type debugHandler struct {
+	meterDefStore *meter_definition.MeterDefinitionStore
+}
+
+// debugConfig is the address the debug endpoints are served on. They are
+// not authenticated so they are served on localhost by default.
+type debugConfig struct {
+	Host string
+	Port int
+}
+
+func provideDebugConfig(opts *Options) debugConfig {
+	return debugConfig{Host: opts.DebugHost, Port: opts.DebugPort}
+}
+
+type debugObject struct {
+	Object           meter_definition.ObjectDebug            `json:"object"`
+	MeterDefinitions []*meter_definition.ObjectResourceValue `json:"meterDefinitions"`
+}
+
+type debugError struct {
+	Error string `json:"error"`
+}
+
+func (h *debugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == debugMeterDefsPath:
+		writeJSON(w, http.StatusOK, h.meterDefStore.DebugMeterDefinitions())
+	case strings.HasPrefix(r.URL.Path, debugObjectsPath):
+		h.serveObject(w, r, strings.TrimPrefix(r.URL.Path, debugObjectsPath))
+	case r.URL.Path == debugExplainPath:
+		h.serveExplain(w, r)
+	default:
+		writeJSON(w, http.StatusNotFound, debugError{Error: "not found"})
+	}
+}
+
+func (h *debugHandler) serveObject(w http.ResponseWriter, r *http.Request, uid string) {
+	obj, ok := h.findObject(uid, w)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, debugObject{
+		Object:           meter_definition.NewObjectDebug(obj),
+		MeterDefinitions: h.meterDefStore.Query(meter_definition.ObjectResourceQuery{ObjectUID: obj.GetUID()}),
+	})
+}
+
+func (h *debugHandler) serveExplain(w http.ResponseWriter, r *http.Request) {
+	meterdef := r.URL.Query().Get("meterdef")
+	uid := r.URL.Query().Get("object")
+
+	if meterdef == "" || uid == "" {
+		writeJSON(w, http.StatusBadRequest, debugError{Error: "meterdef and object are required"})
+		return
+	}
+
+	obj, ok := h.findObject(uid, w)
+	if !ok {
+		return
+	}
+
+	explanation, err := h.meterDefStore.Explain(meterdef, obj)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, debugError{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, explanation)
+}
+
+// findObject returns the object with the uid from the meter definition
+// store. An error response is written if it is not found.
+func (h *debugHandler) findObject(uid string, w http.ResponseWriter) (metav1.Object, bool) {
+	if uid == "" {
+		writeJSON(w, http.StatusBadRequest, debugError{Error: "object uid is required"})
+		return nil, false
+	}
+
+	obj, exists, err := h.meterDefStore.FindObject(types.UID(uid))
+	switch {
+	case exists:
+		return obj, true
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, debugError{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusNotFound, debugError{Error: "object not found"})
+	}
+
+	return nil, false
+}
+
+// serveDebug serves the debug endpoints on their own listener so they can be
+// kept off the metrics port.
+func serveDebug(debug http.Handler, config debugConfig) {
+	listenAddress := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+
+	log.Info("Starting debug server", "listenAddress", listenAddress)
+
+	mux := http.NewServeMux()
+	mux.Handle(debugPath, debug)
+
+	err := http.ListenAndServe(listenAddress, mux)
+	if err != nil {
+		log.Error(err, "failing to listen and serve")
+		panic(err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error(err, "failed to write debug response")
+	}
+}
@@ -18,7 +18,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/redhat-marketplace/redhat-marketplace-operator/internal/metrics"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"k8s.io/klog"
 
 	"github.com/spf13/pflag"
@@ -34,7 +38,10 @@ type Options struct {
 	Host          string
 	TelemetryPort int
 	TelemetryHost string
+	DebugPort     int
+	DebugHost     string
 	Namespaces    options.NamespaceList
+	Resources     []string
 	Shard         int32
 	TotalShards   int
 	Pod           string
@@ -43,6 +50,12 @@ type Options struct {
 
 	EnableGZIPEncoding bool
 
+	PrometheusAddress      string
+	PrometheusCAFile       string
+	PrometheusTokenFile    string
+	WorkloadStatusInterval time.Duration
+	WorkloadStatusWindow   time.Duration
+
 	flags *pflag.FlagSet
 }
 
@@ -56,11 +69,22 @@ func ConvertOptions(optsIn *Options) *options.Options {
 		TelemetryPort:      optsIn.TelemetryPort,
 		TelemetryHost:      optsIn.TelemetryHost,
 		Namespaces:         optsIn.Namespaces,
+		Collectors:         resourceSet(optsIn.Resources),
 		Version:            optsIn.Version,
 		EnableGZIPEncoding: optsIn.EnableGZIPEncoding,
 	}
 }
 
+// resourceSet returns the enabled resources as the collectors of the
+// kube-state-metrics options.
+func resourceSet(resources []string) options.CollectorSet {
+	set := options.CollectorSet{}
+	for _, resource := range resources {
+		set[resource] = struct{}{}
+	}
+	return set
+}
+
 // NewOptions returns a new instance of `Options`.
 func NewOptions() *Options {
 	return &Options{}
@@ -88,16 +112,24 @@ func (o *Options) AddFlags() {
 	o.flags.StringVar(&o.Host, "host", "0.0.0.0", `Host to expose metrics on.`)
 	o.flags.IntVar(&o.TelemetryPort, "telemetry-port", 8081, `Port to expose kube-state-metrics self metrics on.`)
 	o.flags.StringVar(&o.TelemetryHost, "telemetry-host", "0.0.0.0", `Host to expose kube-state-metrics self metrics on.`)
+	o.flags.IntVar(&o.DebugPort, "debug-port", 8082, `Port to expose the debug endpoints on.`)
+	o.flags.StringVar(&o.DebugHost, "debug-host", "127.0.0.1", `Host to expose the debug endpoints on. The endpoints are not authenticated.`)
 	o.flags.Var(&o.Namespaces, "namespaces", fmt.Sprintf("Comma-separated list of namespaces to be enabled. Defaults to %q", &DefaultNamespaces))
+	o.flags.StringSliceVar(&o.Resources, "resources", metrics.DefaultResources, fmt.Sprintf("Comma-separated list of resources metric stores are enabled for. Endpoints are enabled with services and servicemonitors. Available resources are %q", strings.Join(metrics.AvailableResources(), ",")))
 	o.flags.Int32Var(&o.Shard, "shard", int32(0), "The instances shard nominal (zero indexed) within the total number of shards. (default 0)")
 	o.flags.IntVar(&o.TotalShards, "total-shards", 1, "The total number of shards. Sharding is disabled when total shards is set to 1.")
 
-	autoshardingNotice := "When set, it is expected that --pod and --pod-namespace are both set. Most likely this should be passed via the downward API. This is used for auto-detecting sharding. If set, this has preference over statically configured sharding. This is experimental, it may be removed without notice."
+	autoshardingNotice := "When set, it is expected that --pod and --pod-namespace are both set. Most likely this should be passed via the downward API. The shard is the ordinal of the pod in its StatefulSet and the total shards are the replicas of the StatefulSet. If set, this has preference over statically configured sharding."
 
-	o.flags.StringVar(&o.Pod, "pod", "", "Name of the pod that contains the kube-state-metrics container. "+autoshardingNotice)
+	o.flags.StringVar(&o.Pod, "pod", "", "Name of the pod that contains the metric-state container. "+autoshardingNotice)
 	o.flags.StringVar(&o.Namespace, "pod-namespace", "", "Name of the namespace of the pod specified by --pod. "+autoshardingNotice)
 	o.flags.BoolVarP(&o.Version, "version", "", false, "kube-state-metrics build version information")
 	o.flags.BoolVar(&o.EnableGZIPEncoding, "enable-gzip-encoding", false, "Gzip responses when requested by clients via 'Accept-Encoding: gzip' header.")
+	o.flags.StringVar(&o.PrometheusAddress, "prometheus-address", "", "Address of the prometheus the workload status of meter definitions is queried from. The workload status is disabled if not set.")
+	o.flags.StringVar(&o.PrometheusCAFile, "prometheus-cafile", "", "CA file for prometheus")
+	o.flags.StringVar(&o.PrometheusTokenFile, "prometheus-tokenfile", "", "Token file for prometheus")
+	o.flags.DurationVar(&o.WorkloadStatusInterval, "workload-status-interval", meter_definition.DefaultWorkloadStatusInterval, "Interval between updates of the workload status of meter definitions.")
+	o.flags.DurationVar(&o.WorkloadStatusWindow, "workload-status-window", meter_definition.DefaultWorkloadStatusWindow, "Window the latest metric values of the workload status are read from.")
 }
 
 func (o *Options) Mount(addFlags func(newSet *pflag.FlagSet)) {
@@ -0,0 +1,65 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_server
+
+import (
+	"net/http"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/internal/metrics"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+)
+
+const readyzPath = "/readyz"
+
+// readyHandler reports the server ready once the meter definition store
+// and every metrics store have synced, so the first scrape after a restart
+// does not publish partial series.
+type readyHandler struct {
+	meterDefStore *meter_definition.MeterDefinitionStore
+	stores        []*metrics.MetricsStore
+}
+
+type readyStatus struct {
+	Ready                bool            `json:"ready"`
+	MeterDefinitionStore map[string]bool `json:"meterDefinitionStore"`
+	MetricStores         map[string]bool `json:"metricStores"`
+}
+
+func (h *readyHandler) status() *readyStatus {
+	status := &readyStatus{
+		Ready:                h.meterDefStore.HasSynced(),
+		MeterDefinitionStore: h.meterDefStore.SyncStatus(),
+		MetricStores:         make(map[string]bool, len(h.stores)),
+	}
+
+	for _, store := range h.stores {
+		synced := store.HasSynced()
+		status.MetricStores[store.Name()] = synced
+		status.Ready = status.Ready && synced
+	}
+
+	return status
+}
+
+func (h *readyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := h.status()
+
+	if !status.Ready {
+		writeJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
@@ -24,17 +24,19 @@ import (
 	"strings"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/internal/metrics"
 	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/managers"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/reporter"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
 	"k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/openshift/origin/pkg/util/proc"
-	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	corev1 "k8s.io/api/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
@@ -63,6 +65,10 @@ type Service struct {
 	statusProcessor  *meter_definition.StatusProcessor
 	serviceProcessor *meter_definition.ServiceProcessor
 	isCacheStarted   managers.CacheIsStarted
+	sharding         meter_definition.Sharding
+	debugConfig      debugConfig
+
+	workloadStatusProcessor *meter_definition.WorkloadStatusProcessor
 }
 
 func (s *Service) Serve(done <-chan struct{}) error {
@@ -73,8 +79,20 @@ func (s *Service) Serve(done <-chan struct{}) error {
 	storeBuilder := metrics.NewBuilder()
 	storeBuilder.WithNamespaces(options.DefaultNamespaces)
 
+	if len(opts.Collectors) != 0 {
+		if err := storeBuilder.WithEnabledResources(opts.Collectors.AsSlice()); err != nil {
+			log.Error(err, "failed to enable resources")
+			return err
+		}
+	}
+
 	proc.StartReaper()
 
+	sharding := s.sharding
+	log.Info("serving shard", "shard", sharding.Shard, "totalShards", sharding.TotalShards)
+	storeBuilder.WithSharding(sharding.Shard, sharding.TotalShards)
+	s.meterDefStore.SetSharding(sharding)
+
 	go func() {
 		err := s.statusProcessor.Start(ctx)
 		log.Error(err, "failed to register status processor")
@@ -85,6 +103,15 @@ func (s *Service) Serve(done <-chan struct{}) error {
 		log.Error(err, "failed to register service processor")
 		panic(err)
 	}()
+	// the workload status is the same for every shard
+	if sharding.Shard == 0 {
+		go func() {
+			err := s.workloadStatusProcessor.Start(ctx)
+			if err != nil {
+				log.Error(err, "failed to run workload status processor")
+			}
+		}()
+	}
 
 	s.meterDefStore.SetNamespaces(options.DefaultNamespaces)
 	s.meterDefStore.Start()
@@ -98,9 +125,11 @@ func (s *Service) Serve(done <-chan struct{}) error {
 		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
 		prometheus.NewGoCollector(),
 	)
+	s.metricsRegistry.MustRegister(s.meterDefStore.Collectors()...)
 	go telemetryServer(s.metricsRegistry, s.opts.TelemetryHost, s.opts.TelemetryPort)
 
-	serveMetrics(ctx, storeBuilder, s.opts, s.opts.Host, opts.Port, s.opts.EnableGZIPEncoding)
+	go serveDebug(&debugHandler{meterDefStore: s.meterDefStore}, s.debugConfig)
+	serveMetrics(ctx, storeBuilder, s.meterDefStore, s.opts, s.opts.Host, opts.Port, s.opts.EnableGZIPEncoding)
 	return nil
 }
 
@@ -139,7 +168,7 @@ func addIndex(
 			&corev1.Pod{},
 			&corev1.Service{},
 			&corev1.PersistentVolumeClaim{},
-			&marketplacev1alpha1.MeterDefinition{},
+			&marketplacev1beta1.MeterDefinition{},
 			&monitoringv1.ServiceMonitor{},
 		})
 
@@ -155,8 +184,35 @@ func provideRegistry() *prometheus.Registry {
 	return prometheus.NewRegistry()
 }
 
-func provideContext() context.Context {
-	return context.Background()
+func provideWorkloadStatusConfig(opts *Options) (*meter_definition.WorkloadStatusConfig, error) {
+	config := &meter_definition.WorkloadStatusConfig{
+		Interval: opts.WorkloadStatusInterval,
+		Window:   opts.WorkloadStatusWindow,
+	}
+
+	if opts.PrometheusAddress == "" {
+		return config, nil
+	}
+
+	client, err := reporter.NewSecureClient(&reporter.PrometheusSecureClientConfig{
+		Address:        opts.PrometheusAddress,
+		ServerCertFile: opts.PrometheusCAFile,
+		TokenFile:      opts.PrometheusTokenFile,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.API = v1.NewAPI(client)
+	return config, nil
+}
+
+// provideContext is the context of the server. The informers and reflectors
+// started with it are stopped by the cleanup function.
+func provideContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return ctx, func() { cancel() }
 }
 
 func telemetryServer(registry prometheus.Gatherer, host string, port int) {
@@ -189,7 +245,7 @@ func telemetryServer(registry prometheus.Gatherer, host string, port int) {
 	}
 }
 
-func serveMetrics(ctx context.Context, storeBuilder *metrics.Builder, opts *options.Options, host string, port int, enableGZIPEncoding bool) {
+func serveMetrics(ctx context.Context, storeBuilder *metrics.Builder, meterDefStore *meter_definition.MeterDefinitionStore, opts *options.Options, host string, port int, enableGZIPEncoding bool) {
 	// Address to listen on for web interface and telemetry
 	listenAddress := net.JoinHostPort(host, strconv.Itoa(port))
 
@@ -212,6 +268,8 @@ func serveMetrics(ctx context.Context, storeBuilder *metrics.Builder, opts *opti
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(http.StatusText(http.StatusOK)))
 	})
+	// Add readyzPath
+	mux.Handle(readyzPath, &readyHandler{meterDefStore: meterDefStore, stores: stores})
 	// Add index
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
@@ -221,6 +279,7 @@ func serveMetrics(ctx context.Context, storeBuilder *metrics.Builder, opts *opti
 			 <ul>
              <li><a href='` + metricsPath + `'>metrics</a></li>
              <li><a href='` + healthzPath + `'>healthz</a></li>
+             <li><a href='` + readyzPath + `'>readyz</a></li>
 			 </ul>
              </body>
              </html>`))
@@ -0,0 +1,81 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric_server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// provideSharding returns the shard of the server. When the pod is set the
+// shard is the ordinal of the pod in its StatefulSet and the total shards
+// are the replicas of the StatefulSet, otherwise the shard options are used.
+func provideSharding(ctx context.Context, opts *Options, kubeClient clientset.Interface) (meter_definition.Sharding, error) {
+	if opts.Pod == "" || opts.Namespace == "" {
+		sharding := meter_definition.Sharding{Shard: opts.Shard, TotalShards: opts.TotalShards}
+		return sharding, validateSharding(sharding)
+	}
+
+	pod, err := kubeClient.CoreV1().Pods(opts.Namespace).Get(ctx, opts.Pod, metav1.GetOptions{})
+	if err != nil {
+		return meter_definition.Sharding{}, errors.Wrap(err, "failed to get pod")
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "StatefulSet" {
+		return meter_definition.Sharding{}, errors.NewWithDetails("pod is not owned by a StatefulSet", "pod", opts.Pod)
+	}
+
+	statefulSet, err := kubeClient.AppsV1().StatefulSets(opts.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return meter_definition.Sharding{}, errors.Wrap(err, "failed to get statefulset")
+	}
+
+	return statefulSetSharding(opts.Pod, statefulSet)
+}
+
+// statefulSetSharding returns the shard of the pod of the StatefulSet.
+func statefulSetSharding(podName string, statefulSet *appsv1.StatefulSet) (meter_definition.Sharding, error) {
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(podName, statefulSet.Name+"-"))
+	if err != nil {
+		return meter_definition.Sharding{}, errors.WrapWithDetails(err, "failed to parse ordinal of pod", "pod", podName)
+	}
+
+	sharding := meter_definition.Sharding{Shard: int32(ordinal), TotalShards: 1}
+	if statefulSet.Spec.Replicas != nil {
+		sharding.TotalShards = int(*statefulSet.Spec.Replicas)
+	}
+
+	return sharding, validateSharding(sharding)
+}
+
+func validateSharding(sharding meter_definition.Sharding) error {
+	if sharding.TotalShards < 1 {
+		return errors.NewWithDetails("total shards must be at least 1", "totalShards", sharding.TotalShards)
+	}
+
+	if sharding.Shard < 0 || int(sharding.Shard) >= sharding.TotalShards {
+		return errors.NewWithDetails("shard must be between 0 and total shards", "shard", sharding.Shard, "totalShards", sharding.TotalShards)
+	}
+
+	return nil
+}
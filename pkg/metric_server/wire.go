@@ -22,15 +22,16 @@ import (
 	"github.com/google/wire"
 	rhmclient "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/controller"
-	marketplacev1alpha1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
+	marketplacev1beta1client "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/managers"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"k8s.io/client-go/metadata"
 )
 
 func NewServer(
 	opts *Options,
-) (*Service, error) {
+) (*Service, func(), error) {
 	panic(wire.Build(
 		managers.ProvideCachedClientSet,
 		getClientOptions,
@@ -43,8 +44,13 @@ func NewServer(
 		meter_definition.NewMeterDefinitionStore,
 		meter_definition.NewStatusProcessor,
 		meter_definition.NewServiceProcessor,
-		marketplacev1alpha1client.NewForConfig,
+		meter_definition.NewWorkloadStatusProcessor,
+		provideWorkloadStatusConfig,
+		provideSharding,
+		provideDebugConfig,
+		marketplacev1beta1client.NewForConfig,
 		monitoringv1client.NewForConfig,
+		metadata.NewForConfig,
 		provideContext,
 		rhmclient.NewFindOwnerHelper,
 		addIndex,
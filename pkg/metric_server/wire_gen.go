@@ -9,12 +9,13 @@ import (
 	"github.com/coreos/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/client"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/controller"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated/clientset/versioned/typed/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/managers"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
@@ -24,14 +25,14 @@ import (
 
 // Injectors from wire.go:
 
-func NewServer(opts *Options) (*Service, error) {
+func NewServer(opts *Options) (*Service, func(), error) {
 	restConfig, err := config.GetConfig()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	restMapper, err := managers.NewDynamicRESTMapper(restConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	opsSrcSchemeDefinition := controller.ProvideOpsSrcScheme()
 	monitoringSchemeDefinition := controller.ProvideMonitoringScheme()
@@ -41,60 +42,86 @@ func NewServer(opts *Options) (*Service, error) {
 	localSchemes := controller.ProvideLocalSchemes(opsSrcSchemeDefinition, monitoringSchemeDefinition, olmV1SchemeDefinition, olmV1Alpha1SchemeDefinition, openshiftConfigV1SchemeDefinition)
 	scheme, err := managers.ProvideScheme(restConfig, localSchemes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	clientOptions := getClientOptions()
 	cache, err := managers.ProvideNewCache(restConfig, restMapper, scheme, clientOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	clientClient, err := managers.ProvideClient(restConfig, restMapper, scheme, cache, clientOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	options := ConvertOptions(opts)
 	registry := provideRegistry()
 	logger := _wireLoggerValue
 	clientCommandRunner := reconcileutils.NewClientCommand(clientClient, scheme, logger)
-	context := provideContext()
-	dynamicInterface, err := dynamic.NewForConfig(restConfig)
+	context, cleanup := provideContext()
+	metadataInterface, err := metadata.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		cleanup()
+		return nil, nil, err
 	}
-	findOwnerHelper := client.NewFindOwnerHelper(dynamicInterface, restMapper)
+	findOwnerHelper := client.NewFindOwnerHelper(context, metadataInterface, restMapper)
 	monitoringV1Client, err := v1.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		cleanup()
+		return nil, nil, err
+	}
+	marketplaceV1beta1Client, err := v1beta1.NewForConfig(restConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
 	}
-	marketplaceV1alpha1Client, err := v1alpha1.NewForConfig(restConfig)
+	dynamicInterface, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		cleanup()
+		return nil, nil, err
 	}
-	meterDefinitionStore := meter_definition.NewMeterDefinitionStore(context, logger, clientCommandRunner, clientset, findOwnerHelper, monitoringV1Client, marketplaceV1alpha1Client, scheme)
+	meterDefinitionStore := meter_definition.NewMeterDefinitionStore(context, logger, clientCommandRunner, clientset, findOwnerHelper, monitoringV1Client, marketplaceV1beta1Client, dynamicInterface, restMapper, scheme)
 	statusProcessor := meter_definition.NewStatusProcessor(logger, clientCommandRunner, meterDefinitionStore)
 	serviceProcessor := meter_definition.NewServiceProcessor(logger, clientCommandRunner, meterDefinitionStore)
+	workloadStatusConfig, err := provideWorkloadStatusConfig(opts)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	workloadStatusProcessor := meter_definition.NewWorkloadStatusProcessor(logger, clientCommandRunner, workloadStatusConfig)
 	cacheIsIndexed, err := addIndex(context, cache)
 	if err != nil {
-		return nil, err
+		cleanup()
+		return nil, nil, err
 	}
 	cacheIsStarted := managers.StartCache(context, cache, logger, cacheIsIndexed)
+	sharding, err := provideSharding(context, opts, clientset)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	debugConfig2 := provideDebugConfig(opts)
 	service := &Service{
-		k8sclient:        clientClient,
-		k8sRestClient:    clientset,
-		opts:             options,
-		cache:            cache,
-		metricsRegistry:  registry,
-		cc:               clientCommandRunner,
-		meterDefStore:    meterDefinitionStore,
-		statusProcessor:  statusProcessor,
-		serviceProcessor: serviceProcessor,
-		isCacheStarted:   cacheIsStarted,
+		k8sclient:               clientClient,
+		k8sRestClient:           clientset,
+		opts:                    options,
+		cache:                   cache,
+		metricsRegistry:         registry,
+		cc:                      clientCommandRunner,
+		meterDefStore:           meterDefinitionStore,
+		statusProcessor:         statusProcessor,
+		serviceProcessor:        serviceProcessor,
+		isCacheStarted:          cacheIsStarted,
+		sharding:                sharding,
+		debugConfig:             debugConfig2,
+		workloadStatusProcessor: workloadStatusProcessor,
 	}
-	return service, nil
+	return service, func() {
+		cleanup()
+	}, nil
 }
 
 var (
@@ -0,0 +1,137 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PromQuery builds the query of a metric of a meter definition workload.
+type PromQuery struct {
+	Type          v1beta1.WorkloadType
+	MeterDef      types.NamespacedName
+	Metric        string
+	Query         string
+	Start, End    time.Time
+	Step          time.Duration
+	Time          string
+	AggregateFunc string
+	AggregateBy   []string
+}
+
+func (q *PromQuery) makeLeftSide() string {
+	switch q.Type {
+	case v1beta1.WorkloadTypePVC:
+		return fmt.Sprintf(`avg(meterdef_persistentvolumeclaim_info{meter_def_name="%v",meter_def_namespace="%v",phase="Bound"}) without (instance, container, endpoint, job, service)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypePod:
+		return fmt.Sprintf(`avg(meterdef_pod_info{meter_def_name="%v",meter_def_namespace="%v"}) without (pod_uid, instance, container, endpoint, job, service)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeService:
+		// Service and service monitor are handled the same
+		fallthrough
+	case v1beta1.WorkloadTypeServiceMonitor:
+		// services without ready addresses are scaled to zero
+		return fmt.Sprintf(`clamp_max(avg(meterdef_endpoints_ready_addresses{meter_def_name="%v",meter_def_namespace="%v"} > 0) without (instance, container, endpoint, job, pod), 1)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeDeployment:
+		return fmt.Sprintf(`avg(meterdef_deployment_info{meter_def_name="%v",meter_def_namespace="%v"}) without (deployment_uid, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeStatefulSet:
+		return fmt.Sprintf(`avg(meterdef_statefulset_info{meter_def_name="%v",meter_def_namespace="%v"}) without (statefulset_uid, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeDaemonSet:
+		return fmt.Sprintf(`avg(meterdef_daemonset_info{meter_def_name="%v",meter_def_namespace="%v"}) without (daemonset_uid, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeJob:
+		return fmt.Sprintf(`avg(meterdef_job_info{meter_def_name="%v",meter_def_namespace="%v"}) without (job_uid, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeCronJob:
+		return fmt.Sprintf(`avg(meterdef_cronjob_info{meter_def_name="%v",meter_def_namespace="%v"}) without (cronjob_uid, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	case v1beta1.WorkloadTypeCustomResource:
+		return fmt.Sprintf(`avg(meterdef_customresource_info{meter_def_name="%v",meter_def_namespace="%v"}) without (customresource_uid, customresource_group, customresource_kind, instance, container, endpoint, job, service, pod)`, q.MeterDef.Name, q.MeterDef.Namespace)
+	default:
+		return "NOTSUPPORTED"
+	}
+}
+
+func (q *PromQuery) makeJoin() string {
+	switch q.Type {
+	case v1beta1.WorkloadTypePVC:
+		return "* on(persistentvolumeclaim,namespace) group_right"
+	case v1beta1.WorkloadTypePod:
+		return "* on(pod,namespace) group_right"
+	case v1beta1.WorkloadTypeService:
+		fallthrough
+	case v1beta1.WorkloadTypeServiceMonitor:
+		return "* on(service,namespace) group_right"
+	case v1beta1.WorkloadTypeDeployment:
+		return "* on(deployment,namespace) group_right"
+	case v1beta1.WorkloadTypeStatefulSet:
+		return "* on(statefulset,namespace) group_right"
+	case v1beta1.WorkloadTypeDaemonSet:
+		return "* on(daemonset,namespace) group_right"
+	case v1beta1.WorkloadTypeJob:
+		return "* on(job_name,namespace) group_right"
+	case v1beta1.WorkloadTypeCronJob:
+		return "* on(cronjob,namespace) group_right"
+	case v1beta1.WorkloadTypeCustomResource:
+		return "* on(customresource,namespace) group_right"
+	default:
+		return "NOTSUPPORTED"
+	}
+}
+
+func (q *PromQuery) makeAggregateBy() string {
+	switch q.Type {
+	case v1beta1.WorkloadTypePVC:
+		return fmt.Sprintf(`%v by (persistentvolumeclaim,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypePod:
+		return fmt.Sprintf(`%v by (pod,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeService:
+		fallthrough
+	case v1beta1.WorkloadTypeServiceMonitor:
+		return fmt.Sprintf(`%v by (service,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeDeployment:
+		return fmt.Sprintf(`%v by (deployment,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeStatefulSet:
+		return fmt.Sprintf(`%v by (statefulset,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeDaemonSet:
+		return fmt.Sprintf(`%v by (daemonset,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeJob:
+		return fmt.Sprintf(`%v by (job_name,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeCronJob:
+		return fmt.Sprintf(`%v by (cronjob,namespace)`, q.AggregateFunc)
+	case v1beta1.WorkloadTypeCustomResource:
+		return fmt.Sprintf(`%v by (customresource,namespace)`, q.AggregateFunc)
+	default:
+		return "NOTSUPPORTED"
+	}
+}
+
+// String returns the query joined to the info metric of the workload type.
+func (q *PromQuery) String() string {
+	aggregate := q.makeAggregateBy()
+	leftSide := q.makeLeftSide()
+	join := q.makeJoin()
+
+	var query string
+	if q.Query != "" {
+		query = q.Query
+	} else {
+		query = fmt.Sprintf("%s{}", q.Metric)
+	}
+
+	return fmt.Sprintf(
+		`%v (%v %v %v)`, aggregate, leftSide, join, query,
+	)
+}
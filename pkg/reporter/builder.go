@@ -24,6 +24,7 @@ import (
 	"github.com/imdario/mergo"
 	"github.com/mitchellh/mapstructure"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type ReportMetadata struct {
@@ -90,6 +91,10 @@ type MetricBase struct {
 	Key              MetricKey              `mapstructure:",squash"`
 	AdditionalLabels map[string]interface{} `mapstructure:"additionalLabels"`
 	Metrics          map[string]interface{} `mapstructure:"rhmUsageMetrics"`
+
+	// MeterDefinitionHashes are the hashes of the revisions of the meter
+	// definitions the metrics were queried with, by meter definition
+	MeterDefinitionHashes map[string]string `mapstructure:"meterDefinitionHashes,omitempty"`
 }
 
 func TimeToReportTimeStr(myTime time.Time) string {
@@ -159,6 +164,22 @@ func (m *MetricBase) AddMetrics(keysAndValues ...interface{}) error {
 	return nil
 }
 
+// AddMeterDefinitionHash stamps the metric with the hash of the revision of
+// the meter definition it was queried with. Cluster meter definitions are
+// keyed by name only.
+func (m *MetricBase) AddMeterDefinitionHash(meterDef types.NamespacedName, hash string) {
+	if m.MeterDefinitionHashes == nil {
+		m.MeterDefinitionHashes = make(map[string]string)
+	}
+
+	key := meterDef.Name
+	if meterDef.Namespace != "" {
+		key = meterDef.String()
+	}
+
+	m.MeterDefinitionHashes[key] = hash
+}
+
 func (m *MetricsReport) AddMetrics(metrics ...*MetricBase) error {
 	for _, metric := range metrics {
 		result := make(map[string]interface{})
@@ -16,6 +16,7 @@ package reporter
 
 import (
 	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -82,4 +83,14 @@ var _ = Describe("Builder", func() {
 			}),
 		})))
 	})
+
+	It("should add meterdefinition hashes to a base", func() {
+		metricBase.AddMeterDefinitionHash(types.NamespacedName{Name: "foo", Namespace: "ns"}, "1234")
+		metricBase.AddMeterDefinitionHash(types.NamespacedName{Name: "cluster-foo"}, "5678")
+		Expect(metricsReport.AddMetrics(metricBase)).To(Succeed())
+		Expect(metricsReport.Metrics[0]).To(HaveKeyWithValue("meterDefinitionHashes", map[string]string{
+			"ns/foo":      "1234",
+			"cluster-foo": "5678",
+		}))
+	})
 })
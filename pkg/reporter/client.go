@@ -20,6 +20,7 @@ import (
 	"encoding/base64"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/prometheus/client_golang/api"
@@ -30,6 +31,9 @@ type PrometheusSecureClientConfig struct {
 
 	Token string
 
+	// TokenFile is read on each request so a rotated token is picked up
+	TokenFile string
+
 	UserAuth *UserAuth
 
 	ServerCertFile string
@@ -60,6 +64,10 @@ func NewSecureClient(config *PrometheusSecureClientConfig) (api.Client, error) {
 		transport = WithBearerAuth(transport, config.Token)
 	}
 
+	if config.TokenFile != "" {
+		transport = WithBearerTokenFile(transport, config.TokenFile)
+	}
+
 	client, err := api.NewClient(api.Config{
 		Address:      config.Address,
 		RoundTripper: transport,
@@ -105,6 +113,30 @@ func WithBearerAuth(rt http.RoundTripper, token string) http.RoundTripper {
 	return addHead
 }
 
+type withTokenFile struct {
+	file string
+	rt   http.RoundTripper
+}
+
+func WithBearerTokenFile(rt http.RoundTripper, file string) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return withTokenFile{file: file, rt: rt}
+}
+
+func (t withTokenFile) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read token file")
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return t.rt.RoundTrip(req)
+}
+
 func WithHeader(rt http.RoundTripper) withHeader {
 	if rt == nil {
 		rt = http.DefaultTransport
@@ -22,7 +22,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/prometheus"
 )
 
 var _ = Describe("Query", func() {
@@ -32,11 +33,11 @@ var _ = Describe("Query", func() {
 		start, _ = time.Parse(time.RFC3339, "2020-04-19T13:00:00Z")
 		end, _   = time.Parse(time.RFC3339, "2020-04-19T16:00:00Z")
 
-		rpcDurationSecondsQuery *PromQuery
+		rpcDurationSecondsQuery *prometheus.PromQuery
 	)
 
 	BeforeEach(func() {
-		rpcDurationSecondsQuery = &PromQuery{
+		rpcDurationSecondsQuery = &prometheus.PromQuery{
 			Metric: "rpc_durations_seconds_count",
 			Query:  `foo{bar="true"}`,
 			Start:  start,
@@ -64,7 +65,7 @@ var _ = Describe("Query", func() {
 	})
 
 	It("should build a query", func() {
-		q1 := &PromQuery{
+		q1 := &prometheus.PromQuery{
 			Metric: "foo",
 			Query:  "kube_persistentvolumeclaim_resource_requests_storage_bytes",
 			MeterDef: types.NamespacedName{
@@ -72,16 +73,61 @@ var _ = Describe("Query", func() {
 				Namespace: "foons",
 			},
 			AggregateFunc: "sum",
-			Type:          v1alpha1.WorkloadTypePVC,
+			Type:          v1beta1.WorkloadTypePVC,
 		}
 
 		expected := "sum by (persistentvolumeclaim,namespace) (avg(meterdef_persistentvolumeclaim_info{meter_def_name=\"foo\",meter_def_namespace=\"foons\",phase=\"Bound\"}) without (instance, container, endpoint, job, service) * on(persistentvolumeclaim,namespace) group_right kube_persistentvolumeclaim_resource_requests_storage_bytes)"
 		Expect(q1.String()).To(Equal(expected), "failed to create query for pvc")
 	})
 
+	It("should build a query for controller workloads", func() {
+		q1 := &prometheus.PromQuery{
+			Metric: "foo",
+			Query:  "kube_deployment_status_replicas_available",
+			MeterDef: types.NamespacedName{
+				Name:      "foo",
+				Namespace: "foons",
+			},
+			AggregateFunc: "max",
+			Type:          v1beta1.WorkloadTypeDeployment,
+		}
+
+		expected := "max by (deployment,namespace) (avg(meterdef_deployment_info{meter_def_name=\"foo\",meter_def_namespace=\"foons\"}) without (deployment_uid, instance, container, endpoint, job, service, pod) * on(deployment,namespace) group_right kube_deployment_status_replicas_available)"
+		Expect(q1.String()).To(Equal(expected), "failed to create query for deployment")
+
+		q2 := &prometheus.PromQuery{
+			Metric: "foo",
+			MeterDef: types.NamespacedName{
+				Name:      "foo",
+				Namespace: "foons",
+			},
+			AggregateFunc: "sum",
+			Type:          v1beta1.WorkloadTypeJob,
+		}
+
+		Expect(q2.String()).To(ContainSubstring("sum by (job_name,namespace)"), "failed to create query for job")
+		Expect(q2.String()).To(ContainSubstring("* on(job_name,namespace) group_right foo{}"), "failed to create query for job")
+	})
+
+	It("should build a query for custom resources", func() {
+		q1 := &prometheus.PromQuery{
+			Metric: "foo",
+			Query:  `meterdef_customresource_value{field="size"}`,
+			MeterDef: types.NamespacedName{
+				Name:      "foo",
+				Namespace: "foons",
+			},
+			AggregateFunc: "sum",
+			Type:          v1beta1.WorkloadTypeCustomResource,
+		}
+
+		expected := "sum by (customresource,namespace) (avg(meterdef_customresource_info{meter_def_name=\"foo\",meter_def_namespace=\"foons\"}) without (customresource_uid, customresource_group, customresource_kind, instance, container, endpoint, job, service, pod) * on(customresource,namespace) group_right meterdef_customresource_value{field=\"size\"})"
+		Expect(q1.String()).To(Equal(expected), "failed to create query for custom resource")
+	})
+
 	PIt("should build a query", func() {
 		By("building a query with no args")
-		q1 := &PromQuery{
+		q1 := &prometheus.PromQuery{
 			Metric: "foo",
 		}
 
@@ -30,8 +30,9 @@ import (
 	"github.com/prometheus/client_golang/api"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/prometheus"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,8 +41,19 @@ import (
 )
 
 var (
-	additionalLabels = []model.LabelName{"pod", "namespace", "service", "persistentvolumeclaim"}
-	logger           = logf.Log.WithName("reporter")
+	additionalLabels = []model.LabelName{
+		"pod",
+		"namespace",
+		"service",
+		"persistentvolumeclaim",
+		"deployment",
+		"statefulset",
+		"daemonset",
+		"job_name",
+		"cronjob",
+		"customresource",
+	}
+	logger = logf.Log.WithName("reporter")
 )
 
 // Goals of the reporter:
@@ -62,8 +74,11 @@ type MarketplaceReporter struct {
 	k8sclient         client.Client
 	mktconfig         *marketplacev1alpha1.MarketplaceConfig
 	report            *marketplacev1alpha1.MeterReport
-	meterDefinitions  []marketplacev1alpha1.MeterDefinition
+	meterDefinitions  []v1beta1.MeterDefinition
+	revisions         []v1beta1.MeterDefinitionRevision
 	prometheusService *corev1.Service
+	results           *meterDefResults
+	totals            *meterTotals
 	*Config
 }
 
@@ -74,7 +89,8 @@ func NewMarketplaceReporter(
 	k8sclient client.Client,
 	report *marketplacev1alpha1.MeterReport,
 	mktconfig *marketplacev1alpha1.MarketplaceConfig,
-	meterDefinitions []marketplacev1alpha1.MeterDefinition,
+	meterDefinitions []v1beta1.MeterDefinition,
+	revisions []v1beta1.MeterDefinitionRevision,
 	prometheusService *corev1.Service,
 	apiClient api.Client,
 ) (*MarketplaceReporter, error) {
@@ -84,6 +100,7 @@ func NewMarketplaceReporter(
 		mktconfig:         mktconfig,
 		report:            report,
 		meterDefinitions:  meterDefinitions,
+		revisions:         revisions,
 		Config:            config,
 		prometheusService: prometheusService,
 	}, nil
@@ -98,24 +115,42 @@ func (r *MarketplaceReporter) CollectMetrics(ctxIn context.Context) (map[MetricK
 	resultsMap := make(map[MetricKey]*MetricBase)
 	var resultsMapMutex sync.Mutex
 
+	r.results = newMeterDefResults(r.meterDefinitions)
+	r.totals = newMeterTotals()
+
 	if len(r.meterDefinitions) == 0 {
 		return resultsMap, []error{}, errors.Wrap(ErrNoMeterDefinitionsFound, "no meterDefs found")
 	}
 
-	meterDefsChan := make(chan *marketplacev1alpha1.MeterDefinition, len(r.meterDefinitions))
+	// each meter definition is queried with the spec of the revisions that
+	// were effective during the report
+	windows := []meterDefWindow{}
+	for i := range r.meterDefinitions {
+		mdefWindows, err := meterDefWindows(
+			&r.meterDefinitions[i],
+			r.revisions,
+			r.report.Spec.StartTime.Time,
+			r.report.Spec.EndTime.Time,
+			time.Hour)
+
+		if err != nil {
+			return resultsMap, []error{}, errors.Wrap(err, "failed to get meterdef revisions")
+		}
+
+		windows = append(windows, mdefWindows...)
+	}
+
+	meterDefsChan := make(chan meterDefWindow, len(windows))
 	promModelsChan := make(chan meterDefPromModel)
 	errorsChan := make(chan error)
 	queryDone := make(chan bool)
 	processDone := make(chan bool)
-
-	defer close(errorsChan)
+	errorsDone := make(chan struct{})
 
 	logger.Info("starting query")
 
 	go r.query(
 		ctx,
-		r.report.Spec.StartTime.Time,
-		r.report.Spec.EndTime.Time,
 		meterDefsChan,
 		promModelsChan,
 		queryDone,
@@ -133,14 +168,15 @@ func (r *MarketplaceReporter) CollectMetrics(ctxIn context.Context) (map[MetricK
 		errorsChan)
 
 	// send & close data pipe
-	for _, meterDef := range r.meterDefinitions {
-		meterDefsChan <- &meterDef
+	for _, window := range windows {
+		meterDefsChan <- window
 	}
 	close(meterDefsChan)
 
 	errorList := []error{}
 
 	go func() {
+		defer close(errorsDone)
 		for err := range errorsChan {
 			logger.Error(err, "error occurred processing")
 			errorList = append(errorList, err)
@@ -160,25 +196,51 @@ func (r *MarketplaceReporter) CollectMetrics(ctxIn context.Context) (map[MetricK
 		}
 	}()
 
+	close(errorsChan)
+	<-errorsDone
+
 	return resultsMap, errorList, nil
 }
 
+// MeterDefinitionResults returns the query results for each meterdefinition
+// from the last collection.
+func (r *MarketplaceReporter) MeterDefinitionResults() []marketplacev1alpha1.MeterDefinitionResult {
+	if r.results == nil {
+		return []marketplacev1alpha1.MeterDefinitionResult{}
+	}
+
+	return r.results.List()
+}
+
+// MeterTotals returns the sum of each metric by workload from the last
+// collection.
+func (r *MarketplaceReporter) MeterTotals() []marketplacev1alpha1.MeterTotal {
+	if r.totals == nil {
+		return []marketplacev1alpha1.MeterTotal{}
+	}
+
+	return r.totals.List()
+}
+
 type meterDefPromModel struct {
-	*marketplacev1alpha1.MeterDefinition
+	*v1beta1.MeterDefinition
 	model.Value
 	MetricName string
-	Type       v1alpha1.WorkloadType
+	Workload   string
+	Type       v1beta1.WorkloadType
+	Hash       string
 }
 
 func (r *MarketplaceReporter) query(
 	ctx context.Context,
-	startTime, endTime time.Time,
-	inMeterDefs <-chan *marketplacev1alpha1.MeterDefinition,
+	inMeterDefs <-chan meterDefWindow,
 	outPromModels chan<- meterDefPromModel,
 	done chan bool,
 	errorsch chan<- error,
 ) {
-	queryProcess := func(mdef *marketplacev1alpha1.MeterDefinition) {
+	queryProcess := func(window meterDefWindow) {
+		mdef := window.MeterDefinition
+
 		for _, workload := range mdef.Spec.Workloads {
 			for _, metric := range workload.MetricLabels {
 				logger.Info("query", "metric", metric)
@@ -186,7 +248,7 @@ func (r *MarketplaceReporter) query(
 				// Guage = delta
 				// Counter = increase
 				// Histogram and summary are unsupported
-				query := &PromQuery{
+				query := &prometheus.PromQuery{
 					Metric: metric.Label,
 					Type:   workload.WorkloadType,
 					MeterDef: types.NamespacedName{
@@ -195,8 +257,8 @@ func (r *MarketplaceReporter) query(
 					},
 					Query:         metric.Query,
 					Time:          "60m",
-					Start:         startTime,
-					End:           endTime,
+					Start:         window.Start,
+					End:           window.End,
 					Step:          time.Hour,
 					AggregateFunc: metric.Aggregation,
 				}
@@ -205,6 +267,7 @@ func (r *MarketplaceReporter) query(
 				var val model.Value
 				var warnings v1.Warnings
 
+				queryStart := time.Now()
 				err := utils.Retry(func() error {
 					var err error
 					val, warnings, err = r.queryRange(query)
@@ -216,24 +279,27 @@ func (r *MarketplaceReporter) query(
 					return nil
 				}, *r.Retry)
 
+				r.results.AddQuery(query.MeterDef, time.Since(queryStart))
+
 				if warnings != nil {
 					logger.Info("warnings %v", warnings)
 				}
 
 				if err != nil {
 					logger.Error(err, "error encountered")
+					r.results.AddError(query.MeterDef, workload.Name, metric.Label, err)
 					errorsch <- err
 					return
 				}
 
-				outPromModels <- meterDefPromModel{mdef, val, metric.Label, query.Type}
+				outPromModels <- meterDefPromModel{mdef, val, metric.Label, workload.Name, query.Type, window.Hash}
 			}
 		}
 	}
 
 	wgWait(ctx, "queryProcess", *r.MaxRoutines, done, func() {
-		for mdef := range inMeterDefs {
-			queryProcess(mdef)
+		for window := range inMeterDefs {
+			queryProcess(window)
 		}
 	})
 }
@@ -250,10 +316,16 @@ func (r *MarketplaceReporter) process(
 	syncProcess := func(
 		pmodel meterDefPromModel,
 		name string,
-		mdef *marketplacev1alpha1.MeterDefinition,
+		mdef *v1beta1.MeterDefinition,
 		report *marketplacev1alpha1.MeterReport,
 		m model.Value,
 	) {
+		meterDefName := types.NamespacedName{Name: mdef.Name, Namespace: mdef.Namespace}
+		addError := func(err error) {
+			r.results.AddError(meterDefName, pmodel.Workload, name, err)
+			errorsch <- err
+		}
+
 		//# do the work
 		switch m.Type() {
 		case model.ValMatrix:
@@ -261,6 +333,7 @@ func (r *MarketplaceReporter) process(
 
 			for _, matrix := range matrixVals {
 				logger.Info("adding metric", "metric", matrix.Metric)
+				r.results.AddRows(meterDefName, len(matrix.Values))
 
 				for _, pair := range matrix.Values {
 					func() {
@@ -269,7 +342,7 @@ func (r *MarketplaceReporter) process(
 						labelMatrix, err := kvToMap(labels)
 
 						if err != nil {
-							errorsch <- errors.Wrap(err, "failed adding additional labels")
+							addError(errors.Wrap(err, "failed adding additional labels"))
 							return
 						}
 
@@ -277,24 +350,48 @@ func (r *MarketplaceReporter) process(
 						namespace := labelMatrix["namespace"].(string)
 
 						switch pmodel.Type {
-						case v1alpha1.WorkloadTypePVC:
+						case v1beta1.WorkloadTypePVC:
 							if pvc, ok := labelMatrix["persistentvolumeclaim"]; ok {
 								objName = pvc.(string)
 							}
-						case v1alpha1.WorkloadTypePod:
+						case v1beta1.WorkloadTypePod:
 							if pod, ok := labelMatrix["pod"]; ok {
 								objName = pod.(string)
 							}
-						case v1alpha1.WorkloadTypeServiceMonitor:
+						case v1beta1.WorkloadTypeServiceMonitor:
 							fallthrough
-						case v1alpha1.WorkloadTypeService:
+						case v1beta1.WorkloadTypeService:
 							if service, ok := labelMatrix["service"]; ok {
 								objName = service.(string)
 							}
+						case v1beta1.WorkloadTypeDeployment:
+							if deployment, ok := labelMatrix["deployment"]; ok {
+								objName = deployment.(string)
+							}
+						case v1beta1.WorkloadTypeStatefulSet:
+							if statefulSet, ok := labelMatrix["statefulset"]; ok {
+								objName = statefulSet.(string)
+							}
+						case v1beta1.WorkloadTypeDaemonSet:
+							if daemonSet, ok := labelMatrix["daemonset"]; ok {
+								objName = daemonSet.(string)
+							}
+						case v1beta1.WorkloadTypeJob:
+							if job, ok := labelMatrix["job_name"]; ok {
+								objName = job.(string)
+							}
+						case v1beta1.WorkloadTypeCronJob:
+							if cronJob, ok := labelMatrix["cronjob"]; ok {
+								objName = cronJob.(string)
+							}
+						case v1beta1.WorkloadTypeCustomResource:
+							if customResource, ok := labelMatrix["customresource"]; ok {
+								objName = customResource.(string)
+							}
 						}
 
 						if objName == "" {
-							errorsch <- errors.New("can't fine objName")
+							addError(errors.New("can't find objName"))
 							return
 						}
 
@@ -326,18 +423,21 @@ func (r *MarketplaceReporter) process(
 						err = base.AddAdditionalLabels(labels...)
 
 						if err != nil {
-							errorsch <- errors.Wrap(err, "failed adding additional labels")
+							addError(errors.Wrap(err, "failed adding additional labels"))
 							return
 						}
 
 						err = base.AddMetrics(metricPairs...)
 
 						if err != nil {
-							errorsch <- errors.Wrap(err, "failed adding metrics")
+							addError(errors.Wrap(err, "failed adding metrics"))
 							return
 						}
 
+						base.AddMeterDefinitionHash(meterDefName, pmodel.Hash)
+
 						results[key] = base
+						r.totals.Add(mdef.Spec.Group, mdef.Spec.Kind, namespace, objName, name, float64(pair.Value))
 					}()
 				}
 			}
@@ -345,7 +445,7 @@ func (r *MarketplaceReporter) process(
 		case model.ValVector:
 		case model.ValScalar:
 		case model.ValNone:
-			errorsch <- errors.Errorf("can't process model type=%s", m.Type())
+			addError(errors.Errorf("can't process model type=%s", m.Type()))
 		}
 	}
 
@@ -0,0 +1,193 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// meterDefResults tracks the outcome of the queries for each meterdefinition
+// in a report. It is safe for concurrent use.
+type meterDefResults struct {
+	mutex   sync.Mutex
+	order   []types.NamespacedName
+	results map[types.NamespacedName]*marketplacev1alpha1.MeterDefinitionResult
+}
+
+func newMeterDefResults(meterDefinitions []marketplacev1beta1.MeterDefinition) *meterDefResults {
+	m := &meterDefResults{
+		results: make(map[types.NamespacedName]*marketplacev1alpha1.MeterDefinitionResult),
+	}
+
+	for _, mdef := range meterDefinitions {
+		m.get(types.NamespacedName{Name: mdef.Name, Namespace: mdef.Namespace})
+	}
+
+	return m
+}
+
+// get returns the result for the meterdefinition; the mutex must be held.
+func (m *meterDefResults) get(name types.NamespacedName) *marketplacev1alpha1.MeterDefinitionResult {
+	result, ok := m.results[name]
+
+	if !ok {
+		result = &marketplacev1alpha1.MeterDefinitionResult{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+		}
+		m.results[name] = result
+		m.order = append(m.order, name)
+	}
+
+	return result
+}
+
+func (m *meterDefResults) AddQuery(name types.NamespacedName, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := m.get(name)
+	result.QueryCount = result.QueryCount + 1
+	result.Duration = metav1.Duration{Duration: result.Duration.Duration + duration}
+}
+
+func (m *meterDefResults) AddRows(name types.NamespacedName, rows int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := m.get(name)
+	result.RowCount = result.RowCount + rows
+}
+
+func (m *meterDefResults) AddError(name types.NamespacedName, workload, label string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := m.get(name)
+	result.Errors = append(result.Errors, marketplacev1alpha1.MeterReportQueryError{
+		Workload: workload,
+		Label:    label,
+		Error:    err.Error(),
+	})
+}
+
+// List returns a copy of the results in the order of the meterdefinitions.
+func (m *meterDefResults) List() []marketplacev1alpha1.MeterDefinitionResult {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	list := make([]marketplacev1alpha1.MeterDefinitionResult, 0, len(m.order))
+	for _, name := range m.order {
+		list = append(list, *m.results[name].DeepCopy())
+	}
+
+	return list
+}
+
+// meterTotals sums the metric values of each workload in a report. It is safe
+// for concurrent use.
+type meterTotals struct {
+	mutex  sync.Mutex
+	totals map[marketplacev1alpha1.MeterTotal]float64
+}
+
+func newMeterTotals() *meterTotals {
+	return &meterTotals{
+		totals: make(map[marketplacev1alpha1.MeterTotal]float64),
+	}
+}
+
+func (m *meterTotals) Add(domain, kind, namespace, workload, metric string, value float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := marketplacev1alpha1.MeterTotal{
+		Domain:    domain,
+		Kind:      kind,
+		Namespace: namespace,
+		Workload:  workload,
+		Metric:    metric,
+	}
+	m.totals[key] = m.totals[key] + value
+}
+
+// List returns the totals sorted by domain, kind, namespace, workload and metric.
+func (m *meterTotals) List() []marketplacev1alpha1.MeterTotal {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	list := make([]marketplacev1alpha1.MeterTotal, 0, len(m.totals))
+	for key, value := range m.totals {
+		key.Value = strconv.FormatFloat(value, 'f', -1, 64)
+		list = append(list, key)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Less(list[j])
+	})
+
+	return list
+}
+
+// fileDigests returns the base name and SHA-256 digest of each file.
+func fileDigests(files ...string) ([]marketplacev1alpha1.MeterReportFile, error) {
+	digests := make([]marketplacev1alpha1.MeterReportFile, 0, len(files))
+
+	for _, file := range files {
+		digest, err := fileDigest(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		digests = append(digests, marketplacev1alpha1.MeterReportFile{
+			Name:   filepath.Base(file),
+			SHA256: digest,
+		})
+	}
+
+	return digests, nil
+}
+
+func fileDigest(file string) (string, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open file")
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrap(err, "failed to read file")
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
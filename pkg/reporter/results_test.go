@@ -0,0 +1,122 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"emperror.dev/errors"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Results", func() {
+	var (
+		results *meterDefResults
+		foo     = types.NamespacedName{Name: "foo", Namespace: "ns"}
+		bar     = types.NamespacedName{Name: "bar", Namespace: "ns"}
+	)
+
+	BeforeEach(func() {
+		results = newMeterDefResults([]marketplacev1beta1.MeterDefinition{
+			{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "ns"}},
+		})
+	})
+
+	It("should track results per meterdefinition", func() {
+		results.AddQuery(foo, time.Second)
+		results.AddQuery(foo, 2*time.Second)
+		results.AddRows(foo, 24)
+		results.AddQuery(bar, time.Second)
+		results.AddError(bar, "app", "rpc_durations_seconds", errors.New("query failed"))
+
+		list := results.List()
+		Expect(list).To(Equal([]marketplacev1alpha1.MeterDefinitionResult{
+			{
+				Name:       "foo",
+				Namespace:  "ns",
+				QueryCount: 2,
+				RowCount:   24,
+				Duration:   metav1.Duration{Duration: 3 * time.Second},
+			},
+			{
+				Name:       "bar",
+				Namespace:  "ns",
+				QueryCount: 1,
+				Duration:   metav1.Duration{Duration: time.Second},
+				Errors: []marketplacev1alpha1.MeterReportQueryError{
+					{
+						Workload: "app",
+						Label:    "rpc_durations_seconds",
+						Error:    "query failed",
+					},
+				},
+			},
+		}))
+	})
+
+	It("should sum metric values per workload", func() {
+		totals := newMeterTotals()
+		totals.Add("apps.partner.metering.com", "App", "ns", "app-b", "rpc_durations_seconds", 1.5)
+		totals.Add("apps.partner.metering.com", "App", "ns", "app-a", "rpc_durations_seconds", 1)
+		totals.Add("apps.partner.metering.com", "App", "ns", "app-a", "rpc_durations_seconds", 2.25)
+
+		Expect(totals.List()).To(Equal([]marketplacev1alpha1.MeterTotal{
+			{
+				Domain:    "apps.partner.metering.com",
+				Kind:      "App",
+				Namespace: "ns",
+				Workload:  "app-a",
+				Metric:    "rpc_durations_seconds",
+				Value:     "3.25",
+			},
+			{
+				Domain:    "apps.partner.metering.com",
+				Kind:      "App",
+				Namespace: "ns",
+				Workload:  "app-b",
+				Metric:    "rpc_durations_seconds",
+				Value:     "1.5",
+			},
+		}))
+	})
+
+	It("should calculate file digests", func() {
+		dir, err := ioutil.TempDir("", "digest")
+		Expect(err).To(Succeed())
+		defer os.RemoveAll(dir)
+
+		file := filepath.Join(dir, "metadata.json")
+		Expect(ioutil.WriteFile(file, []byte("hello"), 0600)).To(Succeed())
+
+		digests, err := fileDigests(file)
+		Expect(err).To(Succeed())
+		Expect(digests).To(Equal([]marketplacev1alpha1.MeterReportFile{
+			{
+				Name:   "metadata.json",
+				SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			},
+		}))
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/meter_definition"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// meterDefWindow is the part of the report a revision of a meter definition
+// was effective for. The meter definition has the spec of the revision.
+type meterDefWindow struct {
+	*v1beta1.MeterDefinition
+	Hash       string
+	Start, End time.Time
+}
+
+// meterDefWindows splits the report into the windows each revision of the
+// meter definition was effective for. An interval belongs to the latest
+// revision effective at its start, intervals before the first revision
+// belong to the first one. Without revisions the current spec is used for
+// the whole report.
+func meterDefWindows(
+	mdef *v1beta1.MeterDefinition,
+	revisions []v1beta1.MeterDefinitionRevision,
+	start, end time.Time,
+	step time.Duration,
+) ([]meterDefWindow, error) {
+	var mdefRevisions []*v1beta1.MeterDefinitionRevision
+	for i := range revisions {
+		if mdef.UID != "" && revisions[i].Spec.MeterDefinition.UID == mdef.UID {
+			mdefRevisions = append(mdefRevisions, &revisions[i])
+		}
+	}
+
+	if len(mdefRevisions) == 0 {
+		hash, err := meter_definition.MeterDefinitionHash(mdef, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return []meterDefWindow{{MeterDefinition: mdef, Hash: hash, Start: start, End: end}}, nil
+	}
+
+	sort.Slice(mdefRevisions, func(i, j int) bool {
+		return mdefRevisions[i].Spec.Revision < mdefRevisions[j].Spec.Revision
+	})
+
+	effectiveAt := func(t time.Time) *v1beta1.MeterDefinitionRevision {
+		effective := mdefRevisions[0]
+		for _, revision := range mdefRevisions {
+			if revision.Spec.EffectiveTime.Time.After(t) {
+				break
+			}
+			effective = revision
+		}
+		return effective
+	}
+
+	windows := []meterDefWindow{}
+	var current *v1beta1.MeterDefinitionRevision
+
+	for t := start; !t.After(end); t = t.Add(step) {
+		revision := effectiveAt(t)
+
+		if revision == current {
+			windows[len(windows)-1].End = t
+			continue
+		}
+
+		current = revision
+		revisionMdef := mdef.DeepCopy()
+		revision.Spec.MeterDefinitionSpec.DeepCopyInto(&revisionMdef.Spec)
+
+		windows = append(windows, meterDefWindow{
+			MeterDefinition: revisionMdef,
+			Hash:            revision.Spec.Hash,
+			Start:           t,
+			End:             t,
+		})
+	}
+
+	if len(windows) != 0 {
+		windows[len(windows)-1].End = end
+	}
+
+	return windows, nil
+}
+
+// getMeterDefinitionRevisions lists the revisions of the meter definitions
+// of the report only.
+func getMeterDefinitionRevisions(
+	ctx context.Context,
+	meterDefinitions []v1beta1.MeterDefinition,
+	cc ClientCommandRunner,
+) ([]v1beta1.MeterDefinitionRevision, error) {
+	revisions := &v1beta1.MeterDefinitionRevisionList{}
+
+	uids := []string{}
+	for _, mdef := range meterDefinitions {
+		if mdef.UID != "" {
+			uids = append(uids, string(mdef.UID))
+		}
+	}
+
+	if len(uids) == 0 {
+		return []v1beta1.MeterDefinitionRevision{}, nil
+	}
+
+	requirement, err := labels.NewRequirement(v1beta1.MeterDefinitionRevisionUIDLabel, selection.In, uids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select meterdef revisions")
+	}
+
+	result, _ := cc.Do(ctx, ListAction(revisions, client.MatchingLabelsSelector{
+		Selector: labels.NewSelector().Add(*requirement),
+	}))
+
+	if result.Is(NotFound) {
+		return []v1beta1.MeterDefinitionRevision{}, nil
+	}
+
+	if !result.Is(Continue) {
+		return nil, errors.Wrap(result, "failed to get meterdef revisions")
+	}
+
+	return revisions.Items, nil
+}
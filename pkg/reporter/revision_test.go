@@ -0,0 +1,147 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/common"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revisions", func() {
+	var (
+		mdef      *marketplacev1beta1.MeterDefinition
+		revisions []marketplacev1beta1.MeterDefinitionRevision
+		start     = time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		end       = start.Add(24 * time.Hour)
+	)
+
+	newRevision := func(revision int64, kind string, effective time.Time) marketplacev1beta1.MeterDefinitionRevision {
+		spec := *mdef.Spec.DeepCopy()
+		spec.Kind = kind
+
+		return marketplacev1beta1.MeterDefinitionRevision{
+			Spec: marketplacev1beta1.MeterDefinitionRevisionSpec{
+				MeterDefinition:     common.NamespacedNameReference{UID: mdef.UID, Name: mdef.Name, Namespace: mdef.Namespace},
+				Revision:            revision,
+				Hash:                kind,
+				EffectiveTime:       metav1.NewTime(effective),
+				MeterDefinitionSpec: spec,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mdef = &marketplacev1beta1.MeterDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns", UID: "foo-uid"},
+			Spec: marketplacev1beta1.MeterDefinitionSpec{
+				Group: "apps.partner.metering.com",
+				Kind:  "App3",
+			},
+		}
+
+		revisions = []marketplacev1beta1.MeterDefinitionRevision{
+			newRevision(2, "App2", start.Add(10*time.Hour+30*time.Minute)),
+			newRevision(1, "App", start.Add(-24*time.Hour)),
+			newRevision(3, "App3", end.Add(time.Hour)),
+		}
+		revisions = append(revisions, marketplacev1beta1.MeterDefinitionRevision{
+			Spec: marketplacev1beta1.MeterDefinitionRevisionSpec{
+				MeterDefinition: common.NamespacedNameReference{UID: "bar-uid", Name: "bar", Namespace: "ns"},
+				Revision:        1,
+				Hash:            "bar",
+			},
+		})
+	})
+
+	It("should query each interval with the revision effective at its start", func() {
+		windows, err := meterDefWindows(mdef, revisions, start, end, time.Hour)
+		Expect(err).To(Succeed())
+		Expect(windows).To(HaveLen(2))
+
+		Expect(windows[0].Hash).To(Equal("App"))
+		Expect(windows[0].Spec.Kind).To(Equal("App"))
+		Expect(windows[0].Start).To(Equal(start))
+		Expect(windows[0].End).To(Equal(start.Add(10 * time.Hour)))
+
+		Expect(windows[1].Hash).To(Equal("App2"))
+		Expect(windows[1].Spec.Kind).To(Equal("App2"))
+		Expect(windows[1].Start).To(Equal(start.Add(11 * time.Hour)))
+		Expect(windows[1].End).To(Equal(end))
+
+		Expect(mdef.Spec.Kind).To(Equal("App3"), "live meterdefinition should not change")
+	})
+
+	It("should use the first revision before it was effective", func() {
+		windows, err := meterDefWindows(mdef, revisions[:1], start, end, time.Hour)
+		Expect(err).To(Succeed())
+		Expect(windows).To(HaveLen(1))
+		Expect(windows[0].Hash).To(Equal("App2"))
+		Expect(windows[0].Start).To(Equal(start))
+		Expect(windows[0].End).To(Equal(end))
+	})
+
+	It("should use the current spec without revisions", func() {
+		windows, err := meterDefWindows(mdef, revisions[3:], start, end, time.Hour)
+		Expect(err).To(Succeed())
+		Expect(windows).To(HaveLen(1))
+		Expect(windows[0].MeterDefinition).To(Equal(mdef))
+		Expect(windows[0].Hash).ToNot(BeEmpty())
+		Expect(windows[0].Start).To(Equal(start))
+		Expect(windows[0].End).To(Equal(end))
+	})
+
+	It("should list the revisions of the meter definitions of the report", func() {
+		newRevision := func(uid types.UID, revision int64) *marketplacev1beta1.MeterDefinitionRevision {
+			return &marketplacev1beta1.MeterDefinitionRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: marketplacev1beta1.MeterDefinitionRevisionName(uid, revision),
+					Labels: map[string]string{
+						marketplacev1beta1.MeterDefinitionRevisionUIDLabel: string(uid),
+					},
+				},
+			}
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(marketplacev1beta1.AddToScheme(scheme)).To(Succeed())
+		cc := reconcileutils.NewClientCommand(fake.NewFakeClientWithScheme(scheme,
+			newRevision(mdef.UID, 1),
+			newRevision(mdef.UID, 2),
+			newRevision("other-uid", 1),
+		), scheme, logger)
+
+		listed, err := getMeterDefinitionRevisions(context.TODO(), []marketplacev1beta1.MeterDefinition{*mdef}, cc)
+		Expect(err).To(Succeed())
+		Expect(listed).To(HaveLen(2))
+		for _, revision := range listed {
+			Expect(revision.Labels).To(HaveKeyWithValue(marketplacev1beta1.MeterDefinitionRevisionUIDLabel, string(mdef.UID)))
+		}
+
+		listed, err = getMeterDefinitionRevisions(context.TODO(), []marketplacev1beta1.MeterDefinition{{}}, cc)
+		Expect(err).To(Succeed())
+		Expect(listed).To(BeEmpty())
+	})
+})
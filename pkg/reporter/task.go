@@ -28,14 +28,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/gotidy/ptr"
 	openshiftconfigv1 "github.com/openshift/api/config/v1"
+	"github.com/operator-framework/operator-sdk/pkg/status"
 	"github.com/prometheus/client_golang/api"
 	"github.com/prometheus/common/log"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1beta1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/managers"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils"
 	. "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/version"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -56,6 +59,14 @@ type Task struct {
 	Uploader  *RedHatInsightsUploader
 }
 
+// reportGenerator collects the metrics of a report and writes its files.
+type reportGenerator interface {
+	CollectMetrics(ctx context.Context) (map[MetricKey]*MetricBase, []error, error)
+	WriteReport(source uuid.UUID, metrics map[MetricKey]*MetricBase) ([]string, error)
+	MeterDefinitionResults() []marketplacev1alpha1.MeterDefinitionResult
+	MeterTotals() []marketplacev1alpha1.MeterTotal
+}
+
 func (r *Task) Run() error {
 	logger.Info("task run start")
 	stopCh := make(chan struct{})
@@ -63,11 +74,26 @@ func (r *Task) Run() error {
 
 	r.Cache.WaitForCacheSync(stopCh)
 
-	logger.Info("creating reporter job")
-	reporter, err := NewReporter(r)
+	return r.run(func() (reportGenerator, error) {
+		logger.Info("creating reporter job")
+		return NewReporter(r)
+	})
+}
+
+func (r *Task) run(newReporter func() (reportGenerator, error)) (err error) {
+	runStartTime := metav1.Now()
+
+	// the outcome of a failed run is recorded on the report
+	defer func() {
+		if err != nil {
+			r.recordFailure(runStartTime, err)
+		}
+	}()
+
+	reporter, err := newReporter()
 
 	if err != nil {
-		return err
+		return errors.Wrap(err, "error creating reporter")
 	}
 
 	logger.Info("starting collection")
@@ -75,7 +101,7 @@ func (r *Task) Run() error {
 
 	if err != nil {
 		logger.Error(err, "error collecting metrics")
-		return err
+		return errors.Wrap(err, "error collecting metrics")
 	}
 
 	reportID := uuid.New()
@@ -94,8 +120,18 @@ func (r *Task) Run() error {
 	fileName := fmt.Sprintf("%s/../upload-%s.tar.gz", dirpath, reportID.String())
 	err = TargzFolder(dirpath, fileName)
 
+	if err != nil {
+		return errors.Wrap(err, "error tarring report")
+	}
+
 	logger.Info("tarring", "outputfile", fileName)
 
+	reportFiles, err := fileDigests(append(files, fileName)...)
+
+	if err != nil {
+		return errors.Wrap(err, "error calculating file digests")
+	}
+
 	if r.Config.Upload {
 		err = r.Uploader.UploadFile(fileName)
 
@@ -106,22 +142,65 @@ func (r *Task) Run() error {
 		logger.Info("uploaded metrics", "metrics", len(metrics))
 	}
 
+	err = r.updateReport(func(report *marketplacev1alpha1.MeterReport) {
+		report.Status.MetricUploadCount = ptr.Int(len(metrics))
+
+		report.Status.QueryErrorList = []string{}
+
+		for _, err := range errorList {
+			report.Status.QueryErrorList = append(report.Status.QueryErrorList, err.Error())
+		}
+
+		runFinishTime := metav1.Now()
+		report.Status.ReportID = reportID.String()
+		report.Status.ReporterVersion = version.Version
+		report.Status.RunStartTime = &runStartTime
+		report.Status.RunFinishTime = &runFinishTime
+		report.Status.Files = reportFiles
+		report.Status.MeterDefinitionResults = reporter.MeterDefinitionResults()
+		report.Status.MeterTotals = reporter.MeterTotals()
+		report.Status.Conditions.SetCondition(marketplacev1alpha1.ReportConditionRunSucceeded)
+	})
+
+	if err != nil {
+		log.Error(err, "failed to update report")
+	}
+
+	return nil
+}
+
+// recordFailure sets the failed run condition with the error on the report.
+func (r *Task) recordFailure(runStartTime metav1.Time, runErr error) {
+	err := r.updateReport(func(report *marketplacev1alpha1.MeterReport) {
+		runFinishTime := metav1.Now()
+		report.Status.ReporterVersion = version.Version
+		report.Status.RunStartTime = &runStartTime
+		report.Status.RunFinishTime = &runFinishTime
+		report.Status.Conditions.SetCondition(marketplacev1alpha1.NewReportConditionRunFailed(runErr))
+	})
+
+	if err != nil {
+		log.Error(err, "failed to record run failure on report")
+	}
+}
+
+// updateReport updates the status of the report of the task with update.
+func (r *Task) updateReport(update func(report *marketplacev1alpha1.MeterReport)) error {
 	report := &marketplacev1alpha1.MeterReport{}
-	err = utils.Retry(func() error {
+
+	return utils.Retry(func() error {
 		result, _ := r.CC.Do(
 			r.Ctx,
 			HandleResult(
 				GetAction(types.NamespacedName(r.ReportName), report),
 				OnContinue(Call(func() (ClientAction, error) {
-					report.Status.MetricUploadCount = ptr.Int(len(metrics))
-
-					report.Status.QueryErrorList = []string{}
-
-					for _, err := range errorList {
-						report.Status.QueryErrorList = append(report.Status.QueryErrorList, err.Error())
+					if report.Status.Conditions == nil {
+						conds := status.NewConditions(marketplacev1alpha1.ReportConditionJobSubmitted)
+						report.Status.Conditions = &conds
 					}
 
-					return UpdateAction(report), nil
+					update(report)
+					return UpdateAction(report, UpdateStatusOnly(true)), nil
 				})),
 			),
 		)
@@ -132,12 +211,6 @@ func (r *Task) Run() error {
 
 		return nil
 	}, 3)
-
-	if err != nil {
-		log.Error(err, "failed to update report")
-	}
-
-	return nil
 }
 
 func provideApiClient(
@@ -322,30 +395,51 @@ func getMeterDefinitions(
 	ctx context.Context,
 	report *marketplacev1alpha1.MeterReport,
 	cc ClientCommandRunner,
-) ([]marketplacev1alpha1.MeterDefinition, error) {
-	defs := &marketplacev1alpha1.MeterDefinitionList{}
+) ([]marketplacev1beta1.MeterDefinition, error) {
+	defs := &marketplacev1beta1.MeterDefinitionList{}
 
+	// reports keep the meterdefinitions they were run with as v1alpha1
 	if len(report.Spec.MeterDefinitions) > 0 {
-		return report.Spec.MeterDefinitions, nil
+		meterDefinitions := make([]marketplacev1beta1.MeterDefinition, len(report.Spec.MeterDefinitions))
+		for i := range report.Spec.MeterDefinitions {
+			if err := report.Spec.MeterDefinitions[i].ConvertTo(&meterDefinitions[i]); err != nil {
+				return nil, errors.Wrap(err, "failed to convert meterdef")
+			}
+		}
+
+		return meterDefinitions, nil
 	}
 
+	// cluster meterdefinitions are kept as meterdefinitions without a namespace
+	clusterDefs := &marketplacev1beta1.ClusterMeterDefinitionList{}
+
 	result, _ := cc.Do(ctx,
 		HandleResult(
 			ListAction(defs, client.InNamespace("")),
-			OnContinue(Call(func() (ClientAction, error) {
-				for _, item := range defs.Items {
-					item.Status = marketplacev1alpha1.MeterDefinitionStatus{}
-				}
+			OnContinue(HandleResult(
+				ListAction(clusterDefs),
+				OnContinue(Call(func() (ClientAction, error) {
+					for i := range clusterDefs.Items {
+						defs.Items = append(defs.Items, *clusterDefs.Items[i].ToMeterDefinition())
+					}
+
+					report.Spec.MeterDefinitions = make([]marketplacev1alpha1.MeterDefinition, len(defs.Items))
+					for i := range defs.Items {
+						defs.Items[i].Status = marketplacev1beta1.MeterDefinitionStatus{}
 
-				report.Spec.MeterDefinitions = defs.Items
+						if err := report.Spec.MeterDefinitions[i].ConvertFrom(&defs.Items[i]); err != nil {
+							return nil, errors.Wrap(err, "failed to convert meterdef")
+						}
+					}
 
-				return UpdateAction(report), nil
-			})),
+					return UpdateAction(report), nil
+				})),
+			)),
 		),
 	)
 
 	if result.Is(NotFound) {
-		return []marketplacev1alpha1.MeterDefinition{}, nil
+		return []marketplacev1beta1.MeterDefinition{}, nil
 	}
 
 	if !result.Is(Continue) {
@@ -0,0 +1,154 @@
+// Copyright 2020 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/pkg/utils/reconcileutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type stubReportGenerator struct {
+	dir string
+}
+
+func (g *stubReportGenerator) CollectMetrics(ctx context.Context) (map[MetricKey]*MetricBase, []error, error) {
+	return map[MetricKey]*MetricBase{}, []error{}, nil
+}
+
+func (g *stubReportGenerator) WriteReport(source uuid.UUID, metrics map[MetricKey]*MetricBase) ([]string, error) {
+	file := filepath.Join(g.dir, "report", "metrics.json")
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+
+	return []string{file}, ioutil.WriteFile(file, []byte("{}"), 0644)
+}
+
+func (g *stubReportGenerator) MeterDefinitionResults() []marketplacev1alpha1.MeterDefinitionResult {
+	return nil
+}
+
+func (g *stubReportGenerator) MeterTotals() []marketplacev1alpha1.MeterTotal {
+	return nil
+}
+
+func newStubReportGenerator(dir string) func() (reportGenerator, error) {
+	return func() (reportGenerator, error) {
+		return &stubReportGenerator{dir: dir}, nil
+	}
+}
+
+var _ = Describe("Task", func() {
+	var (
+		dir       string
+		k8sClient client.Client
+		task      *Task
+		report    *marketplacev1alpha1.MeterReport
+		name      = types.NamespacedName{Name: "report", Namespace: "openshift-redhat-marketplace"}
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "task")
+		Expect(err).To(Succeed())
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(marketplacev1alpha1.AddToScheme(s)).To(Succeed())
+
+		report = &marketplacev1alpha1.MeterReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+			},
+		}
+		k8sClient = fake.NewFakeClientWithScheme(s, report)
+
+		uploader, err := NewRedHatInsightsUploader(&RedHatInsightsUploaderConfig{
+			URL:             "https://cloud.redhat.com",
+			ClusterID:       "2858312a-ff6a-41ae-b108-3ed7b12111ef",
+			OperatorVersion: "1.0.0",
+			Token:           "token",
+		})
+		Expect(err).To(Succeed())
+
+		uploader.client.Transport = &stubRoundTripper{
+			roundTrip: func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(strings.NewReader("internal error")),
+					Header:     make(http.Header),
+				}
+			},
+		}
+
+		task = &Task{
+			ReportName: ReportName(name),
+			CC:         reconcileutils.NewClientCommand(k8sClient, s, logger),
+			K8SClient:  k8sClient,
+			Ctx:        context.TODO(),
+			Config:     &Config{Upload: true},
+			K8SScheme:  s,
+			Uploader:   uploader,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should record a failed upload on the report", func() {
+		Expect(task.run(newStubReportGenerator(dir))).ToNot(Succeed())
+
+		Expect(k8sClient.Get(context.TODO(), name, report)).To(Succeed())
+		Expect(report.Status.Conditions).ToNot(BeNil())
+
+		cond := report.Status.Conditions.GetCondition(marketplacev1alpha1.ReportConditionTypeRunSucceeded)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(marketplacev1alpha1.ReportConditionReasonRunFailed))
+		Expect(cond.Message).To(ContainSubstring("error uploading file"))
+		Expect(report.Status.RunFinishTime).ToNot(BeNil())
+		Expect(report.Status.ReportID).To(BeEmpty())
+	})
+
+	It("should record a successful run on the report", func() {
+		task.Config.Upload = false
+
+		Expect(task.run(newStubReportGenerator(dir))).To(Succeed())
+
+		Expect(k8sClient.Get(context.TODO(), name, report)).To(Succeed())
+		Expect(report.Status.Conditions.IsTrueFor(marketplacev1alpha1.ReportConditionTypeRunSucceeded)).To(BeTrue())
+		Expect(report.Status.ReportID).ToNot(BeEmpty())
+	})
+})
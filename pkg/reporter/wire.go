@@ -56,6 +56,7 @@ func NewReporter(
 		getMarketplaceReport,
 		getPrometheusService,
 		getMeterDefinitions,
+		getMeterDefinitionRevisions,
 		getMarketplaceConfig,
 		ReporterSet,
 	))
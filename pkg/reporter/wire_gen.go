@@ -92,6 +92,10 @@ func NewReporter(task *Task) (*MarketplaceReporter, error) {
 	if err != nil {
 		return nil, err
 	}
+	v2, err := getMeterDefinitionRevisions(contextContext, v, clientCommandRunner)
+	if err != nil {
+		return nil, err
+	}
 	service, err := getPrometheusService(contextContext, meterReport, clientCommandRunner)
 	if err != nil {
 		return nil, err
@@ -100,7 +104,7 @@ func NewReporter(task *Task) (*MarketplaceReporter, error) {
 	if err != nil {
 		return nil, err
 	}
-	marketplaceReporter, err := NewMarketplaceReporter(reporterConfig, client, meterReport, marketplaceConfig, v, service, apiClient)
+	marketplaceReporter, err := NewMarketplaceReporter(reporterConfig, client, meterReport, marketplaceConfig, v, v2, service, apiClient)
 	if err != nil {
 		return nil, err
 	}
@@ -65,16 +65,22 @@ const (
 	FILE_SOURCE_URL_FIELD                     = "FILE_SOURCE_URL"
 
 	/* CSV Controller Values */
-	CSV_FINALIZER                  = "csv.finalizer.marketplace.redhat.com"
-	CSV_NAME                       = "redhat-marketplace-operator"
-	CSV_ANNOTATION_NAME            = "csvName"
-	CSV_ANNOTATION_NAMESPACE       = "csvNamespace"
-	CSV_METERDEFINITION_ANNOTATION = "marketplace.redhat.com/meterDefinition"
+	CSV_FINALIZER                   = "csv.finalizer.marketplace.redhat.com"
+	CSV_NAME                        = "redhat-marketplace-operator"
+	CSV_ANNOTATION_NAME             = "csvName"
+	CSV_ANNOTATION_NAMESPACE        = "csvNamespace"
+	CSV_METERDEFINITION_ANNOTATION  = "marketplace.redhat.com/meterDefinition"
+	CSV_METERDEFINITIONS_ANNOTATION = "marketplace.redhat.com/meterDefinitions"
+
+	/* MeterDefinitionBinding Controller Values */
+	BINDING_ANNOTATION_NAME      = "bindingName"
+	BINDING_ANNOTATION_NAMESPACE = "bindingNamespace"
 
 
 	/* Time and Date */
 	DATE_FORMAT = "2006-01-02"
 	METER_REPORT_PREFIX = "meter-report-"
+	METER_REPORT_SUMMARY_PREFIX = "meter-report-summary-"
 )
 
 var (
@@ -31,7 +31,7 @@ mkdir -p "${SCRIPT_ROOT}/tmp"
 bash "${CODEGEN_PKG}"/generate-groups.sh "client,informer,lister" \
     github.com/redhat-marketplace/redhat-marketplace-operator/pkg/generated \
     github.com/redhat-marketplace/redhat-marketplace-operator/pkg/apis \
-    marketplace:v1alpha1 \
+    marketplace:v1alpha1,v1beta1 \
     --output-base "$(dirname "${BASH_SOURCE[0]}")/../tmp" \
     --go-header-file "${SCRIPT_ROOT}"/.header
 
@@ -124,14 +124,14 @@ var _ = Describe("MeterbaseController", func() {
 
 				By("creating metric-state")
 
-				deployment = &appsv1.Deployment{}
+				statefulSet := &appsv1.StatefulSet{}
 				service = &corev1.Service{}
 				serviceMonitor := &monitoringv1.ServiceMonitor{}
 
 				Eventually(func() bool {
 					result, _ := cc.Do(
 						context.Background(),
-						GetAction(types.NamespacedName{Name: "rhm-metric-state", Namespace: namespace}, deployment),
+						GetAction(types.NamespacedName{Name: "rhm-metric-state", Namespace: namespace}, statefulSet),
 						GetAction(types.NamespacedName{Name: "rhm-metric-state-service", Namespace: namespace}, service),
 						GetAction(types.NamespacedName{Name: "rhm-metric-state", Namespace: namespace}, serviceMonitor),
 					)
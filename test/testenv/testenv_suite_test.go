@@ -72,6 +72,7 @@ var _ = BeforeSuite(func(done Done) {
 	Expect(os.Setenv("TEST_ASSET_ETCD", "../../testbin/etcd")).To(Succeed())
 	Expect(os.Setenv("TEST_ASSET_KUBECTL", "../../testbin/kubectl")).To(Succeed())
 	Expect(os.Setenv("WATCH_NAMESPACE", "")).To(Succeed())
+	Expect(os.Setenv("ENABLE_WEBHOOKS", "false")).To(Succeed())
 
 	By("bootstrapping test environment")
 	t := true
@@ -24,6 +24,7 @@ func initializeMainCtrl(cfg *rest.Config) (*managers.ControllerMain, error) {
 	marketplaceController := controller.ProvideMarketplaceController(defaultCommandRunnerProvider)
 	meterbaseController := controller.ProvideMeterbaseController(defaultCommandRunnerProvider)
 	meterDefinitionController := controller.ProvideMeterDefinitionController(defaultCommandRunnerProvider)
+	meterDefinitionWebhook := controller.ProvideMeterDefinitionWebhook()
 	razeeDeployController := controller.ProvideRazeeDeployController()
 	olmSubscriptionController := controller.ProvideOlmSubscriptionController()
 	operatorConfig, err := config.ProvideConfig()
@@ -31,10 +32,12 @@ func initializeMainCtrl(cfg *rest.Config) (*managers.ControllerMain, error) {
 		return nil, err
 	}
 	meterReportController := controller.ProvideMeterReportController(defaultCommandRunnerProvider, operatorConfig)
+	meterReportSummaryController := controller.ProvideMeterReportSummaryController(defaultCommandRunnerProvider)
+	meterDefinitionBindingController := controller.ProvideMeterDefinitionBindingController(defaultCommandRunnerProvider)
 	olmClusterServiceVersionController := controller.ProvideOlmClusterServiceVersionController()
 	remoteResourceS3Controller := controller.ProvideRemoteResourceS3Controller()
 	nodeController := controller.ProvideNodeController()
-	controllerList := controller.ProvideControllerList(marketplaceController, meterbaseController, meterDefinitionController, razeeDeployController, olmSubscriptionController, meterReportController, olmClusterServiceVersionController, remoteResourceS3Controller, nodeController)
+	controllerList := controller.ProvideControllerList(marketplaceController, meterbaseController, meterDefinitionController, meterDefinitionWebhook, razeeDeployController, olmSubscriptionController, meterReportController, meterReportSummaryController, meterDefinitionBindingController, olmClusterServiceVersionController, remoteResourceS3Controller, nodeController)
 	opsSrcSchemeDefinition := controller.ProvideOpsSrcScheme()
 	monitoringSchemeDefinition := controller.ProvideMonitoringScheme()
 	olmV1SchemeDefinition := controller.ProvideOLMV1Scheme()